@@ -7,6 +7,12 @@ package analyse
 
 import (
 	"io"
+	"os"
+
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/stage/analyser/junit"
+	"github.com/c4-project/c4t/internal/stage/analyser/sarif"
+	"github.com/c4-project/c4t/internal/ux/htmlreport"
 
 	"github.com/c4-project/c4t/internal/plan/analysis"
 
@@ -22,6 +28,8 @@ import (
 
 	"github.com/c4-project/c4t/internal/ux/stdflag"
 	c "github.com/urfave/cli/v2"
+
+	cimpl "github.com/c4-project/c4t/internal/serviceimpl/compiler"
 )
 
 const (
@@ -34,12 +42,23 @@ const (
    Analysis includes, at time of writing:
 
    - computing basic statistics on compile and run times per compiler;
-   - categorising subjects by their final status.
+   - categorising subjects by their final status;
+   - clustering failing compilations by their failure signatures;
+   - extracting compiler warnings, and tracking which ones are new;
+   - tracking per-subject compile and run times, and flagging regressions;
+   - correlating test features (such as memory orders) with bad outcomes.
 
    The program can c4f on its analysis in various ways, depending on the given
    flags.  By passing one or more -show flags, one can receive a human-readable
-   summary of the plan file.  By passing -` + flagSaveDir + `, one can
-   archive failing corpora to a directory for later experimentation.`
+   summary of the plan file.  By passing -` + flagHTMLReport + `, one can
+   receive the same summary as a self-contained HTML page, and -` + flagJUnit + `
+   and -` + flagSarif + ` emit JUnit XML and SARIF for CI dashboards.  By passing
+   -` + flagSaveDir + `, one can archive failing corpora to a directory for
+   later experimentation; -` + flagSaveTarget + ` sends the archives on to a
+   local directory, SFTP host, or S3-compatible object store, and
+   -` + flagSaveMaxAge + ` and -` + flagSaveMaxSize + ` prune old archives.
+   Each archived subject carries a reproduction script and manifest, which
+   c4t-repro can use to re-run it.`
 
 	// FlagErrorOnBadStatus is used to activate error-on-bad-status.  It is exported for testing purposes.
 	FlagErrorOnBadStatus      = "error-on-bad-status"
@@ -47,16 +66,26 @@ const (
 	usageErrorOnBadStatus     = "report an error if plan contains subjects with bad statuses"
 	flagLoadFilters           = "filter-file"
 	usageLoadFilters          = "load compile result filters from this file"
+	flagSuppressions          = "suppression-file"
+	usageSuppressions         = "apply the known-bug suppressions in this file, recording their hits in a state file alongside it"
 	flagCsvCompilers          = "csv-compilers"
 	usageCsvCompilers         = "dump CSV of compilers and their run times"
 	flagCsvStages             = "csv-stages"
 	usageCsvStages            = "dump CSV of stages and their run times"
+	flagCsvFeatures           = "csv-features"
+	usageCsvFeatures          = "dump CSV of test features and the outcomes of compiling them"
 	flagShowCompilers         = "show-compilers"
 	flagShowCompilersShort    = "C"
 	usageShowCompilers        = "show breakdown of compilers and their run times"
 	flagShowCompilerLogs      = "show-compiler-logs"
 	flagShowCompilerLogsShort = "L"
 	usageShowCompilerLogs     = "show breakdown of compiler logs (requires -" + flagShowCompilers + ")"
+	flagShowClusters          = "show-clusters"
+	flagShowClustersShort     = "K"
+	usageShowClusters         = "show distinct failure clusters and their representatives"
+	flagShowFeatures          = "show-features"
+	flagShowFeaturesShort     = "F"
+	usageShowFeatures         = "show which test features correlate with bad outcomes, overall and per compiler"
 	flagShowMutation          = "show-mutation"
 	flagShowMutationShort     = "M"
 	usageShowMutation         = "show results of any mutation testing involved in this plan"
@@ -71,6 +100,28 @@ const (
 	usageShowSubjects         = "show subjects by status"
 	flagSaveDir               = "save-dir"
 	usageSaveDir              = "if present, save failing corpora to this `directory`"
+	flagSaveFormat            = "save-format"
+	usageSaveFormat           = "archive format for saved subjects (tgz, tzst, zip, or dir)"
+	flagSaveTarget            = "save-target"
+	usageSaveTarget           = "send saved runs to this `URL` (a directory, sftp://host/dir, or s3://bucket/prefix)"
+	flagSaveMaxAge            = "save-max-age"
+	usageSaveMaxAge           = "prune saved runs older than this"
+	flagSaveMaxSize           = "save-max-size"
+	usageSaveMaxSize          = "prune the oldest saved runs once they total more than this `SIZE` (eg 512M, 10G)"
+	flagSkipKnown             = "skip-known-clusters"
+	usageSkipKnown            = "when saving, skip subjects whose failures all fall into already-saved clusters"
+	flagWarnings              = "warning-file"
+	usageWarnings             = "report warnings not yet recorded in this file, and record them into it"
+	flagTimings               = "timing-file"
+	usageTimings              = "report subject timings that regress against this file, and record them into it"
+	flagRegressionRatio       = "regression-ratio"
+	usageRegressionRatio      = "minimum ratio of a timing to its historical median for it to count as a regression"
+	flagHTMLReport            = "html-report"
+	usageHTMLReport           = "write a self-contained HTML report of the analysis to this `FILE`"
+	flagJUnit                 = "junit-xml"
+	usageJUnit                = "write JUnit XML, with a test case per subject and compiler, to this `FILE`"
+	flagSarif                 = "sarif"
+	usageSarif                = "write SARIF of compiler errors and warnings to this `FILE`"
 )
 
 // App is the entry point for c4t-analyse.
@@ -93,8 +144,11 @@ func flags() []c.Flag {
 		&c.BoolFlag{Name: FlagErrorOnBadStatus, Aliases: []string{flagErrorOnBadStatusShort}, Usage: usageErrorOnBadStatus},
 		&c.BoolFlag{Name: flagCsvCompilers, Usage: usageCsvCompilers},
 		&c.BoolFlag{Name: flagCsvStages, Usage: usageCsvStages},
+		&c.BoolFlag{Name: flagCsvFeatures, Usage: usageCsvFeatures},
 		&c.BoolFlag{Name: flagShowCompilers, Aliases: []string{flagShowCompilersShort}, Usage: usageShowCompilers},
 		&c.BoolFlag{Name: flagShowCompilerLogs, Aliases: []string{flagShowCompilerLogsShort}, Usage: usageShowCompilerLogs},
+		&c.BoolFlag{Name: flagShowClusters, Aliases: []string{flagShowClustersShort}, Usage: usageShowClusters},
+		&c.BoolFlag{Name: flagShowFeatures, Aliases: []string{flagShowFeaturesShort}, Usage: usageShowFeatures},
 		&c.BoolFlag{Name: flagShowMutation, Aliases: []string{flagShowMutationShort}, Usage: usageShowMutation},
 		&c.BoolFlag{Name: flagShowOk, Aliases: []string{flagShowOkShort}, Usage: usageShowOk},
 		&c.BoolFlag{Name: flagShowPlanInfo, Aliases: []string{flagShowPlanInfoShort}, Usage: usageShowPlanInfo},
@@ -105,11 +159,51 @@ func flags() []c.Flag {
 			Usage:       usageSaveDir,
 			DefaultText: "do not save",
 		},
+		&c.BoolFlag{Name: flagSkipKnown, Usage: usageSkipKnown},
+		&c.StringFlag{Name: flagSaveFormat, Usage: usageSaveFormat, Value: saver.FormatTGZ.String()},
+		&c.StringFlag{Name: flagSaveTarget, Usage: usageSaveTarget, DefaultText: "keep in save directory"},
+		&c.DurationFlag{Name: flagSaveMaxAge, Usage: usageSaveMaxAge, DefaultText: "no limit"},
+		&c.StringFlag{Name: flagSaveMaxSize, Usage: usageSaveMaxSize, DefaultText: "no limit"},
+		&c.PathFlag{
+			Name:        flagHTMLReport,
+			Usage:       usageHTMLReport,
+			DefaultText: "do not write report",
+		},
+		&c.PathFlag{
+			Name:        flagJUnit,
+			Usage:       usageJUnit,
+			DefaultText: "do not write JUnit XML",
+		},
+		&c.PathFlag{
+			Name:        flagSarif,
+			Usage:       usageSarif,
+			DefaultText: "do not write SARIF",
+		},
 		&c.PathFlag{
 			Name:        flagLoadFilters,
 			Usage:       usageLoadFilters,
 			DefaultText: "do not load filters",
 		},
+		&c.PathFlag{
+			Name:        flagSuppressions,
+			Usage:       usageSuppressions,
+			DefaultText: "do not suppress",
+		},
+		&c.PathFlag{
+			Name:        flagWarnings,
+			Usage:       usageWarnings,
+			DefaultText: "do not track warnings",
+		},
+		&c.PathFlag{
+			Name:        flagTimings,
+			Usage:       usageTimings,
+			DefaultText: "do not track timings",
+		},
+		&c.Float64Flag{
+			Name:  flagRegressionRatio,
+			Usage: usageRegressionRatio,
+			Value: analysis.DefaultRegressionPolicy.MinRatio,
+		},
 	}
 }
 
@@ -119,19 +213,89 @@ func run(ctx *c.Context, outw io.Writer, _ io.Writer) error {
 		return err
 	}
 
+	obs, files, err := fileObservers(ctx, obs)
+	if err != nil {
+		closeAll(files)
+		return err
+	}
+
+	rerr := runAnalyser(ctx, obs)
+	errs := []error{rerr}
+	for _, f := range files {
+		errs = append(errs, f.Close())
+	}
+	return errhelp.FirstError(errs...)
+}
+
+func runAnalyser(ctx *c.Context, obs []analyser.Observer) error {
+	sdb, err := suppressions(ctx)
+	if err != nil {
+		return err
+	}
+	wdb, err := warnings(ctx)
+	if err != nil {
+		return err
+	}
+	tdb, err := timings(ctx)
+	if err != nil {
+		return err
+	}
+	scfg, err := saveConfig(ctx)
+	if err != nil {
+		return err
+	}
+
 	a, err := analyser.New(
 		analyser.ObserveWith(obs...),
 		analyser.Analysis(
 			analysis.WithFiltersFromFile(ctx.Path(flagLoadFilters)),
 			analysis.WithWorkerCount(stdflag.WorkerCountFromCli(ctx)),
+			analysis.WithRegressionPolicy(regressionPolicy(ctx)),
 		),
 		analyser.ErrorOnBadStatus(ctx.Bool(FlagErrorOnBadStatus)),
 		analyser.SaveToPathset(savedPaths(ctx)),
+		analyser.SkipKnownClusters(ctx.Bool(flagSkipKnown)),
+		analyser.SaveWith(scfg, nil),
+		analyser.ReproduceWith(&cimpl.CResolve),
+		analyser.Suppress(sdb),
+		analyser.TrackWarnings(wdb),
+		analyser.TrackTimings(tdb),
 	)
 	if err != nil {
 		return err
 	}
-	return ux.RunOnCliPlan(ctx, a, io.Discard)
+	err = ux.RunOnCliPlan(ctx, a, io.Discard)
+	return errhelp.FirstError(err, a.Close())
+}
+
+func suppressions(ctx *c.Context) (*analysis.SuppressionDB, error) {
+	path := ctx.Path(flagSuppressions)
+	if ystring.IsBlank(path) {
+		return nil, nil
+	}
+	return analysis.OpenSuppressionDB(path)
+}
+
+func warnings(ctx *c.Context) (*analysis.WarningDB, error) {
+	path := ctx.Path(flagWarnings)
+	if ystring.IsBlank(path) {
+		return nil, nil
+	}
+	return analysis.OpenWarningDB(path)
+}
+
+func timings(ctx *c.Context) (*analysis.TimingDB, error) {
+	path := ctx.Path(flagTimings)
+	if ystring.IsBlank(path) {
+		return nil, nil
+	}
+	return analysis.OpenTimingDB(path)
+}
+
+func regressionPolicy(ctx *c.Context) analysis.RegressionPolicy {
+	p := analysis.DefaultRegressionPolicy
+	p.MinRatio = ctx.Float64(flagRegressionRatio)
+	return p
 }
 
 func observers(ctx *c.Context, outw io.Writer) ([]analyser.Observer, error) {
@@ -143,20 +307,24 @@ func observers(ctx *c.Context, outw io.Writer) ([]analyser.Observer, error) {
 }
 
 func prettyObserver(ctx *c.Context, outw io.Writer) ([]analyser.Observer, error) {
+	showClusters := ctx.Bool(flagShowClusters)
 	showCompilers := ctx.Bool(flagShowCompilers)
 	// showCompilerLogs depends on showCompilers
+	showFeatures := ctx.Bool(flagShowFeatures)
 	showOk := ctx.Bool(flagShowOk)
 	showMutation := ctx.Bool(flagShowMutation)
 	showSubjects := ctx.Bool(flagShowSubjects)
 	showPlanInfo := ctx.Bool(flagShowPlanInfo)
 
-	if !(showCompilers || showOk || showSubjects || showMutation || showPlanInfo) {
+	if !(showClusters || showCompilers || showFeatures || showOk || showSubjects || showMutation || showPlanInfo) {
 		return nil, nil
 	}
 	po, err := pretty.NewPrinter(
 		pretty.WriteTo(outw),
+		pretty.ShowClusters(showClusters),
 		pretty.ShowCompilers(showCompilers),
 		pretty.ShowCompilerLogs(ctx.Bool(flagShowCompilerLogs)),
+		pretty.ShowFeatures(showFeatures),
 		pretty.ShowMutation(showMutation),
 		pretty.ShowOk(showOk),
 		pretty.ShowPlanInfo(showPlanInfo),
@@ -172,9 +340,78 @@ func csvObserver(ctx *c.Context, outw io.Writer, obs []analyser.Observer) ([]ana
 	if ctx.Bool(flagCsvStages) {
 		obs = append(obs, csvdump.NewStageWriter(outw))
 	}
+	if ctx.Bool(flagCsvFeatures) {
+		obs = append(obs, csvdump.NewFeatureWriter(outw))
+	}
 	return obs, nil
 }
 
+// fileObserver pairs a flag naming an output file with a constructor for an observer that writes to that file.
+type fileObserver struct {
+	flag string
+	make func(ctx *c.Context, w io.Writer) (analyser.Observer, error)
+}
+
+var fileObserverTable = []fileObserver{
+	{flag: flagHTMLReport, make: func(ctx *c.Context, w io.Writer) (analyser.Observer, error) {
+		scfg, err := saveConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return htmlreport.NewPrinter(htmlreport.WriteTo(w), htmlreport.LinkSaved(savedPaths(ctx), scfg.Format))
+	}},
+	{flag: flagJUnit, make: func(_ *c.Context, w io.Writer) (analyser.Observer, error) {
+		return junit.NewWriter(w), nil
+	}},
+	{flag: flagSarif, make: func(_ *c.Context, w io.Writer) (analyser.Observer, error) {
+		return sarif.NewWriter(w), nil
+	}},
+}
+
+// fileObservers appends to obs an observer for each file-output flag that is set.
+// It also returns the files opened, which the caller must close, even if there is an error.
+func fileObservers(ctx *c.Context, obs []analyser.Observer) ([]analyser.Observer, []*os.File, error) {
+	var files []*os.File
+	for _, fo := range fileObserverTable {
+		path := ctx.Path(fo.flag)
+		if ystring.IsBlank(path) {
+			continue
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return nil, files, err
+		}
+		files = append(files, f)
+		o, err := fo.make(ctx, f)
+		if err != nil {
+			return nil, files, err
+		}
+		obs = append(obs, o)
+	}
+	return obs, files, nil
+}
+
+func closeAll(files []*os.File) {
+	for _, f := range files {
+		_ = f.Close()
+	}
+}
+
+func saveConfig(ctx *c.Context) (saver.Config, error) {
+	cfg := saver.Config{
+		Target:    ctx.String(flagSaveTarget),
+		Retention: saver.Retention{MaxAge: ctx.Duration(flagSaveMaxAge)},
+	}
+	var err error
+	if cfg.Format, err = saver.FormatFromString(ctx.String(flagSaveFormat)); err != nil {
+		return cfg, err
+	}
+	if size := ctx.String(flagSaveMaxSize); !ystring.IsBlank(size) {
+		cfg.Retention.MaxSize, err = saver.ParseSize(size)
+	}
+	return cfg, err
+}
+
 func savedPaths(ctx *c.Context) *saver.Pathset {
 	root := ctx.Path(flagSaveDir)
 	if ystring.IsBlank(root) {
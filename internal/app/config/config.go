@@ -8,6 +8,7 @@ package config
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 
@@ -37,7 +38,10 @@ The following flags print information about the current c4t config:
 -` + FlagPrintGlobalPath + `: prints the path that c4t uses by default when looking for a config file.
 You can use this to open the global config in a text editor, or save the config file produced by this program there.
 
--` + FlagPrintCompilers + `: prints a list of the currently-configured compilers.`
+-` + FlagPrintCompilers + `: prints a list of the currently-configured compilers.
+
+-` + FlagPrintFuzzParams + `: asks c4f for the parameters and actions it supports, and prints them alongside any settings
+for them in the current config.  c4t checks the config's [fuzz.params] against the same list before fuzzing.`
 
 	// FlagPrintGlobalPath is the flag used for printing the global path.
 	FlagPrintGlobalPath      = "print-global-path"
@@ -47,6 +51,10 @@ You can use this to open the global config in a text editor, or save the config
 	FlagPrintCompilers      = "print-compilers"
 	flagPrintCompilersShort = "c"
 	usagePrintCompilers     = "print information about configured compilers"
+
+	FlagPrintFuzzParams      = "print-fuzz-params"
+	flagPrintFuzzParamsShort = "P"
+	usagePrintFuzzParams     = "print the parameters and actions that the fuzzer supports"
 )
 
 // App is the entry point for c4t-config.
@@ -75,20 +83,25 @@ func flags() []c.Flag {
 			Aliases: []string{flagPrintCompilersShort},
 			Usage:   usagePrintCompilers,
 		},
+		&c.BoolFlag{
+			Name:    FlagPrintFuzzParams,
+			Aliases: []string{flagPrintFuzzParamsShort},
+			Usage:   usagePrintFuzzParams,
+		},
 	}
 	flags = append(flags, stdflag.TabulatorCliFlags()...)
 	flags = append(flags, stdflag.ConfFileCliFlag())
-	return flags
+	return append(flags, stdflag.C4fRunnerCliFlags()...)
 }
 
 func run(ctx *c.Context, outw io.Writer, errw io.Writer) error {
 	if dc := dumpConfigFromCli(ctx); dc.isDumping() {
-		return dump(ctx, outw, dc)
+		return dump(ctx, outw, errw, dc)
 	}
 	return probeAndDump(ctx.Context, outw, errw)
 }
 
-func dump(ctx *c.Context, outw io.Writer, dc *dumpConfig) error {
+func dump(ctx *c.Context, outw, errw io.Writer, dc *dumpConfig) error {
 	var (
 		// Loaded only if we need it for dumping.
 		cfg *config.Config
@@ -110,24 +123,29 @@ func dump(ctx *c.Context, outw io.Writer, dc *dumpConfig) error {
 			return err
 		}
 	}
+	if dc.fuzzParams {
+		err = printFuzzParams(ctx, outw, errw)
+	}
 	return err
 }
 
 type dumpConfig struct {
 	globalPath bool
 	compilers  bool
+	fuzzParams bool
 }
 
 func dumpConfigFromCli(ctx *c.Context) *dumpConfig {
 	return &dumpConfig{
 		globalPath: ctx.Bool(FlagPrintGlobalPath),
 		compilers:  ctx.Bool(FlagPrintCompilers),
+		fuzzParams: ctx.Bool(FlagPrintFuzzParams),
 	}
 }
 
 func (d dumpConfig) isDumping() bool {
 	// to be expanded if we add more config dumpers
-	return d.compilers || d.globalPath
+	return d.compilers || d.globalPath || d.fuzzParams
 }
 
 func (d dumpConfig) needsConfig() bool {
@@ -135,6 +153,22 @@ func (d dumpConfig) needsConfig() bool {
 	return d.compilers
 }
 
+func printFuzzParams(ctx *c.Context, outw, errw io.Writer) error {
+	cat, err := stdflag.C4fRunnerFromCli(ctx, errw).ListParams(ctx.Context)
+	if err != nil {
+		return err
+	}
+	// The config is optional here: we show its settings if there is one, but the list is useful without it.
+	cfg, err := stdflag.ConfigFromCli(ctx)
+	if err != nil {
+		var nerr *config.NoConfigFileError
+		if !errors.As(err, &nerr) {
+			return err
+		}
+	}
+	return pretty.TabulateFuzzParams(stdflag.TabulatorFromCli(ctx, outw), cat, cfg)
+}
+
 func printGlobalPath(outw io.Writer) error {
 	path, err := config.GlobalFile()
 	if err != nil {
@@ -29,6 +29,7 @@ import (
 	"github.com/c4-project/c4t/internal/director"
 	"github.com/c4-project/c4t/internal/id"
 	"github.com/c4-project/c4t/internal/serviceimpl/compiler"
+	"github.com/c4-project/c4t/internal/serviceimpl/fuzzer"
 	"github.com/c4-project/c4t/internal/stage/planner"
 
 	"github.com/c4-project/c4t/internal/ux/stdflag"
@@ -73,6 +74,9 @@ const (
 	flagNoFuzz      = "no-fuzz"
 	flagNoFuzzShort = "F"
 	usageNoFuzz     = "turns off the fuzzer stage"
+
+	flagSkipKnown  = "skip-known-clusters"
+	usageSkipKnown = "don't save subjects whose failures all fall into already-saved clusters"
 )
 
 // App creates the c4t app.
@@ -85,6 +89,7 @@ func App(outw, errw io.Writer) *c.App {
 		Action: func(ctx *c.Context) error {
 			return run(ctx, errw)
 		},
+		Commands: []*c.Command{replayCommand(errw)},
 	}
 	return stdflag.SetCommonAppSettings(&a, outw, errw)
 }
@@ -102,6 +107,10 @@ func flags() []c.Flag {
 			Aliases: []string{flagNoFuzzShort},
 			Usage:   usageNoFuzz,
 		},
+		&c.BoolFlag{
+			Name:  flagSkipKnown,
+			Usage: usageSkipKnown,
+		},
 		&c.StringFlag{
 			Name:    flagMFilter,
 			Aliases: []string{stdflag.FlagMachine},
@@ -126,6 +135,11 @@ func run(ctx *c.Context, errw io.Writer) error {
 	if err != nil {
 		return err
 	}
+	if !ctx.Bool(flagNoFuzz) {
+		if err := stdflag.CheckFuzzParams(ctx, cfg, a, errw); err != nil {
+			return err
+		}
+	}
 	qs := stdflag.RootQuantitiesFromCli(ctx)
 
 	args := args{
@@ -134,6 +148,7 @@ func run(ctx *c.Context, errw io.Writer) error {
 		mfilter:      ctx.String(flagMFilter),
 		files:        ctx.Args().Slice(),
 		fuzzDisabled: ctx.Bool(flagNoFuzz),
+		skipKnown:    ctx.Bool(flagSkipKnown),
 	}
 
 	return runWithArgs(ctx.Context, cfg, qs, a, args)
@@ -145,6 +160,7 @@ type args struct {
 	mfilter      string
 	files        []string
 	fuzzDisabled bool
+	skipKnown    bool
 }
 
 func setupPprof(cppath string) (func(), error) {
@@ -184,7 +200,7 @@ func runWithObs(ctx context.Context, cfg *config.Config, args args, a *c4f.Runne
 	if err != nil {
 		return err
 	}
-	d, err := makeDirector(cfg, glob, a, o)
+	d, err := makeDirector(cfg, glob, a, o, director.SkipKnownClusters(args.skipKnown))
 	if err != nil {
 		return err
 	}
@@ -204,7 +220,7 @@ func runWithObs(ctx context.Context, cfg *config.Config, args args, a *c4f.Runne
 	return eg.Wait()
 }
 
-func makeDirector(cfg *config.Config, glob id.ID, a *c4f.Runner, obs *directorobs.Obs) (*director.Director, error) {
+func makeDirector(cfg *config.Config, glob id.ID, a *c4f.Runner, obs *directorobs.Obs, opts ...director.Option) (*director.Director, error) {
 	ms, err := cfg.Machines()
 	if err != nil {
 		return nil, err
@@ -213,6 +229,8 @@ func makeDirector(cfg *config.Config, glob id.ID, a *c4f.Runner, obs *directorob
 		director.ConfigFromGlobal(cfg),
 		director.FilterMachines(glob),
 		director.ObserveWith(obs.Observers()...),
+		director.YieldsFrom(obs.Yields()),
+		director.Options(opts...),
 	)
 }
 
@@ -236,7 +254,10 @@ func makeEnv(a *c4f.Runner, c *config.Config) director.Env {
 	return director.Env{
 		Fuzzer:     a,
 		BResolver:  &backend.Resolve,
+		FResolver:  fuzzer.ResolveFrom(a),
+		FRunner:    a.Base,
 		CInspector: &compiler.CResolve,
+		CDriver:    &compiler.CResolve,
 		Planner: planner.Source{
 			BProbe: cbf,
 			SProbe: a,
@@ -0,0 +1,248 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package director
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/1set/gut/ystring"
+	c "github.com/urfave/cli/v2"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/c4-project/c4t/internal/c4f"
+	"github.com/c4-project/c4t/internal/config"
+	"github.com/c4-project/c4t/internal/director"
+	"github.com/c4-project/c4t/internal/helper/iohelp"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/stat"
+	"github.com/c4-project/c4t/internal/ux/directorobs"
+	"github.com/c4-project/c4t/internal/ux/stdflag"
+)
+
+const (
+	replayName  = "replay"
+	replayUsage = "replays a past test cycle exactly"
+
+	replayReadme = `
+   This command reruns one past cycle of the director on one machine,
+   re-deriving its perturbed compilers, sampled corpus, and fuzzer seeds from
+   the cycle's recorded seed.
+
+   The cycle can come from a plan saved alongside a failing subject
+   (--` + flagReplayPlan + `), from the statistics history (--` + flagReplayIter + `, with -` + stdflag.FlagMachine + `), or from an
+   explicit seed (--` + flagReplaySeed + `, with -` + stdflag.FlagMachine + `).  The director must be given the
+   same input files and configuration as the original run.
+
+   If the cycle's record includes hashes of its fuzzer outputs, the command
+   checks that the replay produced identical files, and fails if not.
+   Otherwise, as with explicit seeds, it warns that the replay is unverified.`
+
+	flagReplayMachine  = "machine"
+	usageReplayMachine = "ID of the `machine` on which to replay the cycle"
+
+	flagReplayPlan  = "plan"
+	usageReplayPlan = "replay the cycle that produced this saved plan `FILE`"
+
+	flagReplayIter  = "iter"
+	usageReplayIter = "replay the most recent cycle with this `NUMBER` in the statistics history"
+
+	flagReplaySeed  = "seed"
+	usageReplaySeed = "replay a cycle with this plan `SEED`"
+
+	flagReplayOutDir      = "out-dir"
+	flagReplayOutDirShort = "d"
+	usageReplayOutDir     = "run the replay in this `DIR`ectory"
+)
+
+var (
+	// ErrReplayMismatch occurs when a replayed cycle doesn't reproduce the original's fuzzer outputs.
+	ErrReplayMismatch = errors.New("replay didn't reproduce the original cycle")
+
+	// ErrNoReplaySource occurs when the replay command isn't told which cycle to replay.
+	ErrNoReplaySource = errors.New("need a saved plan, history iteration, or seed to replay")
+
+	// ErrNoHistoryRecord occurs when the statistics history has no record of the cycle to replay.
+	ErrNoHistoryRecord = errors.New("no such cycle in statistics history")
+)
+
+func replayCommand(errw io.Writer) *c.Command {
+	return &c.Command{
+		Name:        replayName,
+		Usage:       replayUsage,
+		Description: strings.TrimSpace(replayReadme),
+		ArgsUsage:   "[FILE...]",
+		Flags:       replayFlags(),
+		Action: func(ctx *c.Context) error {
+			return runReplay(ctx, ctx.App.Writer, errw)
+		},
+	}
+}
+
+func replayFlags() []c.Flag {
+	fs := []c.Flag{
+		stdflag.ConfFileCliFlag(),
+		&c.StringFlag{
+			Name:    flagReplayMachine,
+			Aliases: []string{stdflag.FlagMachine},
+			Usage:   usageReplayMachine,
+		},
+		&c.PathFlag{Name: flagReplayPlan, Usage: usageReplayPlan},
+		&c.Uint64Flag{Name: flagReplayIter, Usage: usageReplayIter},
+		&c.Int64Flag{Name: flagReplaySeed, Usage: usageReplaySeed},
+		&c.PathFlag{
+			Name:        flagReplayOutDir,
+			Aliases:     []string{flagReplayOutDirShort},
+			Usage:       usageReplayOutDir,
+			DefaultText: "'replay' under the configured output directory",
+		},
+	}
+	return append(fs, stdflag.C4fRunnerCliFlags()...)
+}
+
+func runReplay(ctx *c.Context, outw, errw io.Writer) error {
+	a := stdflag.C4fRunnerFromCli(ctx, errw)
+	cfg, err := stdflag.ConfigFromCli(ctx)
+	if err != nil {
+		return err
+	}
+	if err := stdflag.CheckFuzzParams(ctx, cfg, a, errw); err != nil {
+		return err
+	}
+	r, err := replaySource(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	if err := replayConfig(ctx, cfg); err != nil {
+		return err
+	}
+
+	p, err := replay(ctx.Context, cfg, a, r, errw)
+	if err != nil {
+		return err
+	}
+
+	rep := r.Check(p)
+	if _, err := fmt.Fprintf(outw, "replayed cycle %d on %s (seed %d): %s\n", r.Iter, r.Machine, r.Seed, rep); err != nil {
+		return err
+	}
+	if rep.Unverified() {
+		if _, err := fmt.Fprintln(errw, "WARNING: the original cycle has no recorded fuzzer output hashes, so this replay can't be checked against it"); err != nil {
+			return err
+		}
+	}
+	if !rep.OK() {
+		return fmt.Errorf("%w: mismatched %v, missing %v", ErrReplayMismatch, rep.Mismatched, rep.Missing)
+	}
+	return nil
+}
+
+// replaySource works out which cycle to replay from the command-line arguments.
+func replaySource(ctx *c.Context, cfg *config.Config) (director.Replay, error) {
+	if path := ctx.Path(flagReplayPlan); ystring.IsNotBlank(path) {
+		var p plan.Plan
+		if err := plan.ReadFile(path, &p); err != nil {
+			return director.Replay{}, err
+		}
+		return director.ReplayOfPlan(&p), nil
+	}
+
+	mid, err := id.TryFromString(ctx.String(flagReplayMachine))
+	if err != nil {
+		return director.Replay{}, err
+	}
+	switch {
+	case ctx.IsSet(flagReplayIter):
+		return replayFromHistory(cfg, mid, ctx.Uint64(flagReplayIter))
+	case ctx.IsSet(flagReplaySeed):
+		return director.Replay{Machine: mid, Seed: ctx.Int64(flagReplaySeed)}, nil
+	default:
+		return director.Replay{}, ErrNoReplaySource
+	}
+}
+
+// replayFromHistory finds the most recent record of cycle iter on machine mid in the statistics history.
+func replayFromHistory(cfg *config.Config, mid id.ID, iter uint64) (director.Replay, error) {
+	path, err := cfg.Paths.HistoryFile()
+	if err != nil {
+		return director.Replay{}, err
+	}
+	rs, err := stat.LoadHistoryFile(path, stat.HistoryQuery{Machine: mid})
+	if err != nil {
+		return director.Replay{}, err
+	}
+	for j := len(rs) - 1; 0 <= j; j-- {
+		if rs[j].Iter == iter {
+			return rs[j].Replay(), nil
+		}
+	}
+	return director.Replay{}, fmt.Errorf("%w: %s #%d", ErrNoHistoryRecord, mid, iter)
+}
+
+// replayConfig adjusts cfg so that the replay neither disturbs the original run's output nor records itself in the
+// databases that track it.
+func replayConfig(ctx *c.Context, cfg *config.Config) error {
+	if err := cfg.OverrideInputs(ctx.Args().Slice()); err != nil {
+		return err
+	}
+	dir := ctx.Path(flagReplayOutDir)
+	if ystring.IsBlank(dir) {
+		var err error
+		if dir, err = cfg.Paths.OutPath(replayName); err != nil {
+			return err
+		}
+	}
+	cfg.Isolate(dir)
+	return nil
+}
+
+func replay(ctx context.Context, cfg *config.Config, a *c4f.Runner, r director.Replay, errw io.Writer) (*plan.Plan, error) {
+	l, err := directorobs.NewLogger(iohelp.NopWriteCloser{Writer: errw}, log.LstdFlags)
+	if err != nil {
+		return nil, err
+	}
+	fwd, err := directorobs.NewForwardObserver(1, l)
+	if err != nil {
+		return nil, err
+	}
+	ms, err := cfg.Machines()
+	if err != nil {
+		return nil, err
+	}
+	d, err := director.New(makeEnv(a, cfg), ms, cfg.Paths.Inputs,
+		director.ConfigFromGlobal(cfg),
+		director.FilterMachines(r.Machine),
+		director.ObserveWith(fwd),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	cctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var p *plan.Plan
+	eg, ectx := errgroup.WithContext(cctx)
+	eg.Go(func() error {
+		// The forwarder won't stop by itself if the replay fails before its instance starts.
+		defer cancel()
+		var err error
+		p, err = d.Replay(ectx, r)
+		return err
+	})
+	eg.Go(func() error {
+		if err := fwd.Run(ectx); !errors.Is(err, context.Canceled) {
+			return err
+		}
+		return nil
+	})
+	return p, eg.Wait()
+}
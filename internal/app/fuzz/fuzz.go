@@ -12,8 +12,11 @@ import (
 
 	"github.com/c4-project/c4t/internal/quantity"
 
+	"github.com/c4-project/c4t/internal/c4f"
 	"github.com/c4-project/c4t/internal/config"
 
+	"github.com/c4-project/c4t/internal/helper/srvrun"
+	fuzzimpl "github.com/c4-project/c4t/internal/serviceimpl/fuzzer"
 	"github.com/c4-project/c4t/internal/stage/fuzzer"
 
 	"github.com/c4-project/c4t/internal/ux/singleobs"
@@ -37,6 +40,7 @@ func App(outw, errw io.Writer) *c.App {
 		Action: func(ctx *c.Context) error {
 			return run(ctx, outw, errw)
 		},
+		Commands: []*c.Command{traceCommand(outw, errw)},
 	}
 	return stdflag.SetPlanAppSettings(a, outw, errw)
 }
@@ -59,6 +63,9 @@ func run(ctx *c.Context, outw, errw io.Writer) error {
 	}
 
 	a := stdflag.C4fRunnerFromCli(ctx, errw)
+	if err := stdflag.CheckFuzzParams(ctx, cfg, a, errw); err != nil {
+		return err
+	}
 	l := log.New(errw, "", 0)
 	f, err := makeFuzzer(ctx, cfg, a, l)
 	if err != nil {
@@ -67,7 +74,11 @@ func run(ctx *c.Context, outw, errw io.Writer) error {
 	return ux.RunOnCliPlan(ctx, f, outw)
 }
 
-func makeFuzzer(ctx *c.Context, cfg *config.Config, drv fuzzer.Driver, l *log.Logger) (*fuzzer.Fuzzer, error) {
+func makeFuzzer(ctx *c.Context, cfg *config.Config, a *c4f.Runner, l *log.Logger) (*fuzzer.Fuzzer, error) {
+	drv, err := fuzzer.ResolveDriver(a, cfg.Fuzz, fuzzimpl.ResolveFrom(a), srvrun.NewExecRunner(srvrun.StderrTo(l.Writer())))
+	if err != nil {
+		return nil, err
+	}
 	return fuzzer.New(
 		drv,
 		fuzzer.NewPathset(stdflag.OutDirFromCli(ctx)),
@@ -0,0 +1,174 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package fuzz
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	c "github.com/urfave/cli/v2"
+
+	"github.com/c4-project/c4t/internal/c4f"
+	"github.com/c4-project/c4t/internal/ux/stdflag"
+)
+
+const (
+	traceName  = "trace"
+	traceUsage = "inspects and replays fuzzer traces"
+
+	traceReadme = `
+   These commands work with the trace files that the fuzzer writes alongside
+   each fuzzed subject (in the 'trace' directory of the fuzzer's output).
+
+   'show' lists the actions in one trace; 'summary' tabulates how often each
+   action appears across a set of traces; and 'replay' applies a trace, or
+   a prefix of one, to the seed test from which it was made.  Replaying
+   successively shorter prefixes of a flagged subject's trace is a cheap way
+   to find the actions responsible for the flag.`
+
+	flagTruncate      = "truncate"
+	flagTruncateShort = "n"
+	usageTruncate     = "replay only the first `COUNT` actions of the trace"
+
+	flagReplayOut      = "output"
+	flagReplayOutShort = "o"
+	usageReplayOut     = "write the replayed test to this `FILE`"
+
+	// traceExt is the extension the fuzzer gives to trace files.
+	traceExt = ".trace"
+)
+
+// ErrTraceArgs occurs when a trace command gets the wrong number of arguments.
+var ErrTraceArgs = errors.New("wrong number of arguments")
+
+func traceCommand(outw, errw io.Writer) *c.Command {
+	return &c.Command{
+		Name:        traceName,
+		Usage:       traceUsage,
+		Description: strings.TrimSpace(traceReadme),
+		Subcommands: []*c.Command{
+			{
+				Name:      "show",
+				Usage:     "lists the actions in a trace",
+				ArgsUsage: "TRACE",
+				Action: func(ctx *c.Context) error {
+					return runTraceShow(ctx, outw)
+				},
+			},
+			{
+				Name:      "summary",
+				Usage:     "counts the actions across traces",
+				ArgsUsage: "TRACE-OR-DIR...",
+				Flags:     stdflag.TabulatorCliFlags(),
+				Action: func(ctx *c.Context) error {
+					return runTraceSummary(ctx, outw)
+				},
+			},
+			{
+				Name:      "replay",
+				Usage:     "replays a trace onto its seed test",
+				ArgsUsage: "TRACE SEED-TEST",
+				Flags:     traceReplayFlags(),
+				Action: func(ctx *c.Context) error {
+					return runTraceReplay(ctx, errw)
+				},
+			},
+		},
+	}
+}
+
+func traceReplayFlags() []c.Flag {
+	fs := []c.Flag{
+		&c.IntFlag{
+			Name:    flagTruncate,
+			Aliases: []string{flagTruncateShort},
+			Usage:   usageTruncate,
+			Value:   -1,
+		},
+		&c.PathFlag{
+			Name:     flagReplayOut,
+			Aliases:  []string{flagReplayOutShort},
+			Usage:    usageReplayOut,
+			Required: true,
+		},
+	}
+	return append(fs, stdflag.C4fRunnerCliFlags()...)
+}
+
+func runTraceShow(ctx *c.Context, outw io.Writer) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("%w: expected one trace file", ErrTraceArgs)
+	}
+	t, err := c4f.ReadTraceFile(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	for i, a := range t {
+		if _, err := fmt.Fprintf(outw, "%d\t%s\t%s\n", i, a.Name, a.Payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runTraceSummary(ctx *c.Context, outw io.Writer) error {
+	if ctx.NArg() == 0 {
+		return fmt.Errorf("%w: expected at least one trace file or directory", ErrTraceArgs)
+	}
+	var s c4f.TraceSummary
+	for _, path := range ctx.Args().Slice() {
+		if err := summariseTraces(&s, path); err != nil {
+			return err
+		}
+	}
+
+	tab := stdflag.TabulatorFromCli(ctx, outw)
+	tab.Header("action", "count", "share")
+	for _, n := range s.Names() {
+		k := s.Counts[n]
+		tab.Cell(n).Cell(k).Cell(fmt.Sprintf("%.1f%%", 100*float64(k)/float64(s.Actions))).EndRow()
+	}
+	if err := tab.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(outw, "%d actions across %d traces\n", s.Actions, s.Traces)
+	return err
+}
+
+// summariseTraces adds the trace at path, or all traces under it if it is a directory, to s.
+func summariseTraces(s *c4f.TraceSummary, path string) error {
+	return filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// Only filter by extension inside directories; explicitly named files are always traces.
+		if d.IsDir() || (p != path && filepath.Ext(p) != traceExt) {
+			return nil
+		}
+		t, err := c4f.ReadTraceFile(p)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", p, err)
+		}
+		s.Add(t)
+		return nil
+	})
+}
+
+func runTraceReplay(ctx *c.Context, errw io.Writer) error {
+	if ctx.NArg() != 2 {
+		return fmt.Errorf("%w: expected a trace file and a seed test", ErrTraceArgs)
+	}
+	t, err := c4f.ReadTraceFile(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	a := stdflag.C4fRunnerFromCli(ctx, errw)
+	return a.ReplayTrace(ctx.Context, t.Truncate(ctx.Int(flagTruncate)), ctx.Args().Get(1), ctx.Path(flagReplayOut))
+}
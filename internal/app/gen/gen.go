@@ -0,0 +1,147 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package gen contains the app definition for c4t-gen.
+package gen
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/c4-project/c4t/internal/litmusgen"
+	"github.com/c4-project/c4t/internal/ux/stdflag"
+	c "github.com/urfave/cli/v2"
+)
+
+const (
+	// Name is the name of the generator binary.
+	Name  = "c4t-gen"
+	usage = "generates C11 litmus tests from classic shapes"
+
+	readme = `
+   This program generates C11 litmus tests from classic test shapes (such as
+   message passing, store buffering, and IRIW), across combinations of memory
+   orders and atomic types, and writes them into a directory.  The directory
+   can then be used as an input to the planner or director, for instance to
+   smoke-test a new machine or compiler without installing c4f.  Alongside
+   each test, it records the statistics that the planner would otherwise ask
+   c4f for, so planning the tests doesn't need c4f either; fuzzing them still
+   does, so disable the fuzzer when running without it.
+
+   Each access in a shape gets one of three strengths: 'rlx' (relaxed), 'ra'
+   (release for stores, acquire for loads), or 'sc' (sequentially
+   consistent).  By default, every combination of strengths is generated;
+   -uniform generates only tests in which every access has the same strength.
+
+   It prints the path of each generated test.`
+
+	flagShapeLong  = "shape"
+	flagShapeShort = "s"
+	usageShape     = "generate this `shape` (can be repeated; default: all)"
+
+	flagTypeLong  = "type"
+	flagTypeShort = "t"
+	usageType     = "use this atomic `type` (can be repeated; default: int)"
+
+	flagStrengthLong = "strength"
+	usageStrength    = "combine this access `strength` (can be repeated; default: all)"
+
+	flagUniformLong  = "uniform"
+	flagUniformShort = "u"
+	usageUniform     = "give every access in each test the same strength"
+
+	defaultOutDir = "litmus"
+)
+
+// App creates the c4t-gen app.
+func App(outw, errw io.Writer) *c.App {
+	a := c.App{
+		Name:        Name,
+		Usage:       usage,
+		Description: readme,
+		Flags:       flags(),
+		Action: func(ctx *c.Context) error {
+			return run(ctx, outw)
+		},
+	}
+	return stdflag.SetCommonAppSettings(&a, outw, errw)
+}
+
+func flags() []c.Flag {
+	return []c.Flag{
+		stdflag.OutDirCliFlag(defaultOutDir),
+		&c.StringSliceFlag{
+			Name:    flagShapeLong,
+			Aliases: []string{flagShapeShort},
+			Usage:   usageShape + "; one of " + strings.Join(litmusgen.ShapeNames(), ", "),
+		},
+		&c.StringSliceFlag{
+			Name:    flagTypeLong,
+			Aliases: []string{flagTypeShort},
+			Usage:   usageType + "; one of " + strings.Join(typeNames(), ", "),
+		},
+		&c.StringSliceFlag{
+			Name:  flagStrengthLong,
+			Usage: usageStrength + "; one of rlx, ra, sc",
+		},
+		&c.BoolFlag{
+			Name:    flagUniformLong,
+			Aliases: []string{flagUniformShort},
+			Usage:   usageUniform,
+		},
+	}
+}
+
+func typeNames() []string {
+	ns := make([]string, len(litmusgen.Types))
+	for i, t := range litmusgen.Types {
+		ns[i] = t.Name
+	}
+	return ns
+}
+
+func run(ctx *c.Context, outw io.Writer) error {
+	cfg, err := config(ctx)
+	if err != nil {
+		return err
+	}
+	paths, err := litmusgen.WriteAll(stdflag.OutDirFromCli(ctx), cfg.Generate())
+	if err != nil {
+		return err
+	}
+	for _, p := range paths {
+		if _, err := fmt.Fprintln(outw, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func config(ctx *c.Context) (litmusgen.Config, error) {
+	cfg := litmusgen.Config{Uniform: ctx.Bool(flagUniformLong)}
+	for _, n := range ctx.StringSlice(flagShapeLong) {
+		s, err := litmusgen.LookupShape(n)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.Shapes = append(cfg.Shapes, s)
+	}
+	for _, n := range ctx.StringSlice(flagTypeLong) {
+		t, err := litmusgen.LookupType(n)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.Types = append(cfg.Types, t)
+	}
+	for _, n := range ctx.StringSlice(flagStrengthLong) {
+		s, err := litmusgen.StrengthOfString(n)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.Strengths = append(cfg.Strengths, s)
+	}
+	return cfg, nil
+}
@@ -11,6 +11,8 @@ import (
 	"io"
 	"path/filepath"
 
+	"github.com/c4-project/c4t/internal/app/gen"
+	"github.com/c4-project/c4t/internal/app/repro"
 	"github.com/c4-project/c4t/internal/app/stat"
 
 	"github.com/c4-project/c4t/internal/app/config"
@@ -77,12 +79,14 @@ var appFuncs = [...]func(io.Writer, io.Writer) *c.App{
 	director.App,
 	fuzz.App,
 	gccnt.App,
+	gen.App,
 	invoke.App,
 	lift.App,
 	mach.App,
 	obs.App,
 	perturb.App,
 	plan.App,
+	repro.App,
 	setc.App,
 	stat.App,
 }
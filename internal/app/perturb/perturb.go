@@ -11,7 +11,9 @@ import (
 	"log"
 	"os"
 
+	"github.com/1set/gut/ystring"
 	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/stat"
 
 	"github.com/c4-project/c4t/internal/stage/perturber"
 	"github.com/c4-project/c4t/internal/ux"
@@ -32,6 +34,12 @@ const (
 	flagFullIDs      = "full-ids"
 	flagFullIDsShort = "I"
 	usageFullIDs     = "map compilers to their 'full' IDs on perturbance"
+	flagWeighting    = "weighting"
+	usageWeighting   = "weight corpus sampling by `STRATEGY` (uniform, yield, novelty, or mixed)"
+	flagStratifyBy   = "stratify-by"
+	usageStratifyBy  = "stratify corpus sampling by `FEATURE`s (threads, rmw, or mem-orders)"
+	flagStatFile     = "stat-file"
+	usageStatFile    = "read subject yields for yield weighting from this statistics `FILE`"
 )
 
 // App creates the c4t-perturb app.
@@ -61,6 +69,9 @@ func flags() []c.Flag {
 		},
 		&c.BoolFlag{Name: flagFullIDs, Aliases: []string{flagFullIDsShort}, Usage: usageFullIDs},
 		stdflag.CorpusSizeCliFlag(),
+		&c.StringFlag{Name: flagWeighting, Usage: usageWeighting, DefaultText: "from config, else uniform"},
+		&c.StringSliceFlag{Name: flagStratifyBy, Usage: usageStratifyBy, DefaultText: "from config, else none"},
+		&c.PathFlag{Name: flagStatFile, Usage: usageStatFile, DefaultText: "treat every subject as untried"},
 	}
 }
 
@@ -78,7 +89,14 @@ func makePerturber(ctx *c.Context, errw io.Writer) (*perturber.Perturber, error)
 		return nil, err
 	}
 
-	qs := quantities(ctx, cfg)
+	qs, err := quantities(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	ys, err := yields(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	l := log.New(errw, "[perturb] ", log.LstdFlags)
 
@@ -88,13 +106,38 @@ func makePerturber(ctx *c.Context, errw io.Writer) (*perturber.Perturber, error)
 		perturber.OverrideQuantities(qs),
 		perturber.UseSeed(ctx.Int64(flagSeed)),
 		perturber.UseFullCompilerIDs(ctx.Bool(flagFullIDs)),
+		perturber.UseYields(ys),
 	)
 }
 
-func quantities(ctx *c.Context, cfg *config.Config) quantity.PerturbSet {
+func quantities(ctx *c.Context, cfg *config.Config) (quantity.PerturbSet, error) {
 	qs := cfg.Quantities.Perturb
-	qs.Override(quantity.PerturbSet{
-		CorpusSize: stdflag.CorpusSizeFromCli(ctx),
-	})
-	return qs
+	over := quantity.PerturbSet{CorpusSize: stdflag.CorpusSizeFromCli(ctx)}
+	if w := ctx.String(flagWeighting); !ystring.IsBlank(w) {
+		var err error
+		if over.Weighting, err = quantity.WeightingOfString(w); err != nil {
+			return qs, err
+		}
+	}
+	for _, f := range ctx.StringSlice(flagStratifyBy) {
+		st, err := quantity.StratumOfString(f)
+		if err != nil {
+			return qs, err
+		}
+		over.StratifyBy = append(over.StratifyBy, st)
+	}
+	qs.Override(over)
+	return qs, nil
+}
+
+func yields(ctx *c.Context) (perturber.YieldSource, error) {
+	path := ctx.Path(flagStatFile)
+	if ystring.IsBlank(path) {
+		return nil, nil
+	}
+	var s stat.Set
+	if err := s.LoadFile(path); err != nil {
+		return nil, err
+	}
+	return &s, nil
 }
@@ -0,0 +1,126 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package repro contains the app definition for c4t-repro.
+package repro
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/serviceimpl/backend"
+	"github.com/c4-project/c4t/internal/stage/analyser/saver"
+	"github.com/c4-project/c4t/internal/stage/mach"
+
+	cimpl "github.com/c4-project/c4t/internal/serviceimpl/compiler"
+	"github.com/c4-project/c4t/internal/ux/stdflag"
+	c "github.com/urfave/cli/v2"
+)
+
+const (
+	// Name is the name of the reproducer binary.
+	Name  = "c4t-repro"
+	usage = "re-runs saved failing subjects to see if they reproduce"
+
+	readme = `
+   This program takes one or more subject archives saved by the analyser, and
+   re-runs each through the machine-dependent phase of the tester on the local
+   machine, using the reproduction manifest and single-subject plan stored
+   inside the archive.
+
+   For each compilation that originally had a bad status, it reports whether
+   the re-run gave the same status.  It fails if any archive's failure didn't
+   reproduce on any of its compilations.
+
+   Each archive also contains a standalone shell script, repro.sh, that replays
+   the same compiler invocations without needing c4t.`
+)
+
+var (
+	// ErrNoArchives occurs when we aren't given any archives to reproduce.
+	ErrNoArchives = errors.New("expected at least one archive argument")
+	// ErrNotReproduced occurs when none of a subject's bad compilations reproduce.
+	ErrNotReproduced = errors.New("failure did not reproduce")
+)
+
+// App creates the c4t-repro app.
+func App(outw, errw io.Writer) *c.App {
+	a := c.App{
+		Name:        Name,
+		Usage:       usage,
+		Description: readme,
+		ArgsUsage:   "archive...",
+		Flags:       stdflag.MachCliFlags(),
+		Action: func(ctx *c.Context) error {
+			return run(ctx, outw)
+		},
+	}
+	return stdflag.SetCommonAppSettings(&a, outw, errw)
+}
+
+func run(ctx *c.Context, outw io.Writer) error {
+	if ctx.NArg() == 0 {
+		return ErrNoArchives
+	}
+	var err error
+	for _, path := range ctx.Args().Slice() {
+		err = errhelp.FirstError(err, reproduce(ctx, path, outw))
+	}
+	return err
+}
+
+func reproduce(ctx *c.Context, path string, outw io.Writer) error {
+	b, err := saver.OpenBundle(path)
+	if err != nil {
+		return err
+	}
+	rerr := reproduceBundle(ctx, b, outw)
+	cerr := b.Close()
+	return errhelp.FirstError(rerr, cerr)
+}
+
+func reproduceBundle(ctx *c.Context, b *saver.Bundle, outw io.Writer) error {
+	m, err := mach.New(
+		&cimpl.CResolve,
+		&backend.Resolve,
+		mach.OutputDir(stdflag.OutDirFromCli(ctx)),
+		mach.OverrideQuantities(stdflag.MachNodeQuantitySetFromCli(ctx)),
+	)
+	if err != nil {
+		return err
+	}
+	outs, rerr := b.Reproduce(ctx.Context, m)
+	cerr := m.Close()
+	if err := errhelp.FirstError(rerr, cerr); err != nil {
+		return fmt.Errorf("reproducing %s: %w", b.Manifest.Subject, err)
+	}
+	return report(outw, b.Manifest, outs)
+}
+
+func report(w io.Writer, m *saver.Manifest, outs []saver.ReproOutcome) error {
+	if _, err := fmt.Fprintf(w, "%s (saved from %s at %s):\n", m.Subject, m.Machine, m.Created); err != nil {
+		return err
+	}
+	nrepro := 0
+	for _, o := range outs {
+		verdict := "not reproduced"
+		if o.Reproduced() {
+			verdict = "reproduced"
+			nrepro++
+		}
+		if _, err := fmt.Fprintf(w, "  %s: saved %s, now %s (%s)\n", o.Compiler, o.Observed, o.Now, verdict); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "  %d of %d failing compilation(s) reproduced\n", nrepro, len(outs)); err != nil {
+		return err
+	}
+	if nrepro == 0 && len(outs) != 0 {
+		return fmt.Errorf("%s: %w", m.Subject, ErrNotReproduced)
+	}
+	return nil
+}
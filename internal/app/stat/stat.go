@@ -9,6 +9,13 @@ import (
 	"encoding/csv"
 	"fmt"
 	"io"
+	"os"
+	"time"
+
+	"github.com/c4-project/c4t/internal/id"
+
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/ux/htmlreport"
 
 	"github.com/c4-project/c4t/internal/stat/pretty"
 
@@ -26,7 +33,12 @@ const (
 
 	readme = `
    This program reads the statistics file maintained by the director, and
-   prints CSV or human-readable summaries of its contents.`
+   prints CSV, HTML, or human-readable summaries of its contents.
+
+   With -` + flagHistory + `, it instead reads the per-cycle history log
+   maintained alongside the statistics file, and prints a table (or, with
+   -` + stdflag.FlagOutputCsv + `, a CSV series) of the cycle records in a given
+   time window, optionally restricted to certain machines and compilers.`
 
 	flagCsvMutations   = "csv-mutations"
 	usageCsvMutations  = "dump CSV of mutation testing results"
@@ -35,6 +47,20 @@ const (
 	flagUseTotals      = "use-totals"
 	flagUseTotalsShort = "t"
 	usageUseTotals     = "use multi-session totals rather than per-session totals"
+	flagHTMLReport     = "html-report"
+	usageHTMLReport    = "write a self-contained HTML report of the statistics to this `FILE`"
+	flagHistory        = "history"
+	usageHistory       = "query the per-cycle history log rather than the statistics file"
+	flagHistoryFile    = "history-input"
+	usageHistoryFile   = "read per-cycle history from this `FILE`"
+	flagSince          = "since"
+	usageSince         = "only show history from this `TIME` (RFC3339, or a duration such as 24h before now)"
+	flagUntil          = "until"
+	usageUntil         = "only show history from before this `TIME` (RFC3339, or a duration such as 24h before now)"
+	flagMachine        = "machine"
+	usageMachine       = "only show history for machines matching this `GLOB`"
+	flagCompiler       = "compiler"
+	usageCompiler      = "only show history for compilers matching this `GLOB`"
 	flagStatFile       = "input"
 	flagStatFileShort  = "i"
 	usageStatFile      = "read statistics from this `FILE`"
@@ -55,21 +81,32 @@ func App(outw, errw io.Writer) *c.App {
 }
 
 func flags() []c.Flag {
-	return []c.Flag{
+	return append([]c.Flag{
 		stdflag.ConfFileCliFlag(),
 		&c.BoolFlag{Name: flagCsvMutations, Usage: usageCsvMutations},
 		&c.StringFlag{Name: flagShowMutations, Usage: usageShowMutations, DefaultText: "do not show"},
 		&c.BoolFlag{Name: flagUseTotals, Aliases: []string{flagUseTotalsShort}, Usage: usageUseTotals},
+		&c.PathFlag{Name: flagHTMLReport, Usage: usageHTMLReport, DefaultText: "do not write report"},
 		&c.PathFlag{
 			Name:        flagStatFile,
 			Aliases:     []string{flagStatFileShort},
 			Usage:       usageStatFile,
 			DefaultText: "read from configuration",
 		},
-	}
+		&c.BoolFlag{Name: flagHistory, Usage: usageHistory},
+		&c.PathFlag{Name: flagHistoryFile, Usage: usageHistoryFile, DefaultText: "read from configuration"},
+		&c.StringFlag{Name: flagSince, Usage: usageSince},
+		&c.StringFlag{Name: flagUntil, Usage: usageUntil},
+		&c.StringFlag{Name: flagMachine, Usage: usageMachine},
+		&c.StringFlag{Name: flagCompiler, Usage: usageCompiler},
+	}, stdflag.TabulatorCliFlags()...)
 }
 
 func run(ctx *c.Context, outw io.Writer, _ io.Writer) error {
+	if ctx.Bool(flagHistory) {
+		return queryHistory(ctx, outw)
+	}
+
 	// TODO(@MattWindsor91): maybe use stat persister?
 	set, err := getStats(ctx)
 	if err != nil {
@@ -88,9 +125,35 @@ func dump(ctx *c.Context, set *stat.Set, w io.Writer) error {
 		}
 	}
 
+	if err := writeHTMLReport(ctx, set, totals); err != nil {
+		return err
+	}
+
 	return prettyPrint(ctx, set, w, totals)
 }
 
+func writeHTMLReport(ctx *c.Context, set *stat.Set, totals bool) error {
+	path := ctx.Path(flagHTMLReport)
+	if ystring.IsBlank(path) {
+		return nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	werr := writeHTML(f, set, totals)
+	cerr := f.Close()
+	return errhelp.FirstError(werr, cerr)
+}
+
+func writeHTML(w io.Writer, set *stat.Set, totals bool) error {
+	hp, err := htmlreport.NewPrinter(htmlreport.WriteTo(w), htmlreport.Title("c4t statistics"), htmlreport.UseTotals(totals))
+	if err != nil {
+		return err
+	}
+	return hp.WriteStats(*set)
+}
+
 func prettyPrint(ctx *c.Context, set *stat.Set, w io.Writer, totals bool) error {
 	pp, err := makePretty(ctx, w, totals)
 	if pp == nil || err != nil {
@@ -157,3 +220,65 @@ func getStatPath(ctx *c.Context) (string, error) {
 	}
 	return cfg.Paths.StatFile()
 }
+
+func queryHistory(ctx *c.Context, w io.Writer) error {
+	q, err := historyQuery(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	fname, err := getHistoryPath(ctx)
+	if err != nil {
+		return err
+	}
+	rs, err := stat.LoadHistoryFile(fname, q)
+	if err != nil {
+		return err
+	}
+	return stat.TabulateHistory(stdflag.TabulatorFromCli(ctx, w), rs)
+}
+
+func historyQuery(ctx *c.Context, now time.Time) (stat.HistoryQuery, error) {
+	var (
+		q   stat.HistoryQuery
+		err error
+	)
+	if q.Since, err = parseTime(ctx.String(flagSince), now); err != nil {
+		return q, err
+	}
+	if q.Until, err = parseTime(ctx.String(flagUntil), now); err != nil {
+		return q, err
+	}
+	if q.Machine, err = id.TryFromString(ctx.String(flagMachine)); err != nil {
+		return q, err
+	}
+	q.Compiler, err = id.TryFromString(ctx.String(flagCompiler))
+	return q, err
+}
+
+// parseTime parses s as either an RFC3339 timestamp or a duration before now.
+// If s is blank, parseTime returns the zero time.
+func parseTime(s string, now time.Time) (time.Time, error) {
+	if ystring.IsBlank(s) {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return t, fmt.Errorf("time %q is neither a duration nor an RFC3339 timestamp", s)
+	}
+	return t, nil
+}
+
+// getHistoryPath computes the intended path to the history file.
+func getHistoryPath(ctx *c.Context) (string, error) {
+	if f := ctx.Path(flagHistoryFile); ystring.IsNotBlank(f) {
+		return f, nil
+	}
+	cfg, err := stdflag.ConfigFromCli(ctx)
+	if err != nil {
+		return "", err
+	}
+	return cfg.Paths.HistoryFile()
+}
@@ -33,57 +33,85 @@ fuzz {
 }
 `
 
+// parseParam converts the c4t parameter key/val to a c4f fuzzer config statement, or "" if it is malformed.
 func parseParam(key, val string) string {
-	khd, ktl, ok := id.FromString(key).Uncons()
-	if !ok {
+	st, err := paramStatement(key, val)
+	if err != nil {
 		return ""
 	}
-	switch khd {
-	case "int":
-		return parseIntParam(ktl, val)
-	case "bool":
-		return parseBoolParam(ktl, val)
-	case "action":
-		return parseActionWeight(ktl, val)
+	return st
+}
+
+// paramStatement converts the c4t parameter key/val to a c4f fuzzer config statement.
+func paramStatement(key, val string) (string, error) {
+	kind, name, err := splitParamKey(key)
+	if err != nil {
+		return "", err
+	}
+	switch kind {
+	case ParamKindInt:
+		return parseIntParam(name, val)
+	case ParamKindBool:
+		return parseBoolParam(name, val)
 	default:
-		return ""
+		return parseActionWeight(name, val)
+	}
+}
+
+// splitParamKey splits a c4t parameter key into its kind and the c4f name of the parameter or action.
+func splitParamKey(key string) (kind string, name id.ID, err error) {
+	kid, err := id.TryFromString(key)
+	if err != nil {
+		return "", id.ID{}, fmt.Errorf("%w: %q: %s", ErrBadParam, key, err)
+	}
+	kind, name, ok := kid.Uncons()
+	if !ok || name.IsEmpty() {
+		return "", id.ID{}, fmt.Errorf("%w: %q: key should be 'int.', 'bool.', or 'action.' then a name", ErrBadParam, key)
+	}
+	switch kind {
+	case ParamKindInt, ParamKindBool, ParamKindAction:
+		return kind, name, nil
+	default:
+		return "", id.ID{}, fmt.Errorf("%w: %q: unknown kind %q", ErrBadParam, key, kind)
 	}
 }
 
-func parseIntParam(key id.ID, val string) string {
+func parseIntParam(key id.ID, val string) (string, error) {
 	vint, err := strconv.Atoi(val)
 	if err != nil {
-		// ignore specific error
-		return ""
+		return "", errBadParamValue(ParamKindInt, key, val, "an integer")
 	}
-	return fmt.Sprintf("set param %s to %d", key, vint)
+	return fmt.Sprintf("set param %s to %d", key, vint), nil
 }
 
-func parseBoolParam(key id.ID, val string) string {
+func parseBoolParam(key id.ID, val string) (string, error) {
 	definites := map[string]bool{"on": true, "yes": true, "true": true, "off": false, "no": false, "false": false}
 	b, ok := definites[val]
 	if ok {
-		return fmt.Sprintf("set flag %s to %s", key, strconv.FormatBool(b))
+		return fmt.Sprintf("set flag %s to %s", key, strconv.FormatBool(b)), nil
 	}
 	return parseBoolRatioParam(key, val)
 }
 
-func parseBoolRatioParam(key id.ID, val string) string {
+func parseBoolRatioParam(key id.ID, val string) (string, error) {
 	var wins, losses int
 	_, err := fmt.Sscanf(val, "%d:%d", &wins, &losses)
 	if err != nil {
-		return ""
+		return "", errBadParamValue(ParamKindBool, key, val, "a boolean or a WINS:LOSSES ratio")
 	}
-	return fmt.Sprintf("set flag %s to ratio %d:%d", key, wins, losses)
+	return fmt.Sprintf("set flag %s to ratio %d:%d", key, wins, losses), nil
 }
 
-func parseActionWeight(key id.ID, val string) string {
+func parseActionWeight(key id.ID, val string) (string, error) {
 	vint, err := strconv.Atoi(val)
 	if err != nil {
-		// ignore specific error
-		return ""
+		return "", errBadParamValue(ParamKindAction, key, val, "an integer weight")
 	}
-	return fmt.Sprintf("action %s weight %d", key, vint)
+	return fmt.Sprintf("action %s weight %d", key, vint), nil
+}
+
+func errBadParamValue(kind string, key id.ID, val, want string) error {
+	return fmt.Errorf("%w: %s.%s is %q; want %s", ErrBadParam, kind, key, val, want)
 }
 
 // WriteFuzzConf writes a fuzzer configuration based on j to w.
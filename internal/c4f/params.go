@@ -0,0 +1,397 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package c4f
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/c4-project/c4t/internal/model/service/fuzzer"
+)
+
+const (
+	// ParamKindInt is the key prefix, and kind, of integer fuzzer parameters.
+	ParamKindInt = "int"
+	// ParamKindBool is the key prefix, and kind, of boolean fuzzer parameters (c4f calls these 'flags').
+	ParamKindBool = "bool"
+	// ParamKindAction is the key prefix, and kind, of fuzzer action weights.
+	ParamKindAction = "action"
+
+	// listKindFlag is the kind c4f uses for boolean parameters when listing them.
+	listKindFlag = "flag"
+)
+
+var (
+	// ErrBadParam occurs when a fuzzer parameter key or value is malformed.
+	ErrBadParam = errors.New("bad fuzzer parameter")
+
+	// ErrUnknownParam occurs when a fuzzer parameter key doesn't name anything c4f knows about.
+	ErrUnknownParam = errors.New("unknown fuzzer parameter")
+
+	// ErrParamListParse occurs when there is a parse error reading c4f's list of parameters or actions.
+	ErrParamListParse = errors.New("parameter list parse error")
+)
+
+// ParamSpec describes one fuzzer parameter or action that c4f supports.
+type ParamSpec struct {
+	// Key is the key under which the parameter appears in c4t's fuzzer configuration, such as 'int.cap.threads'.
+	Key string `json:"key"`
+	// Kind is the kind of parameter: one of ParamKindInt, ParamKindBool, or ParamKindAction.
+	Kind string `json:"kind"`
+	// Bounded is true if the parameter is an integer with an inclusive range from Min to Max.
+	Bounded bool `json:"bounded,omitempty"`
+	// Min is the lowest value of a bounded parameter.
+	Min int `json:"min,omitempty"`
+	// Max is the highest value of a bounded parameter.
+	Max int `json:"max,omitempty"`
+	// Summary is c4f's description of the parameter, if any.
+	Summary string `json:"summary,omitempty"`
+}
+
+// Range describes the values the parameter accepts.
+func (p ParamSpec) Range() string {
+	switch {
+	case p.Bounded:
+		return fmt.Sprintf("%d..%d", p.Min, p.Max)
+	case p.Kind == ParamKindInt:
+		return "integer"
+	case p.Kind == ParamKindBool:
+		return "boolean or WINS:LOSSES"
+	default:
+		return "weight"
+	}
+}
+
+// ParamCatalogue maps the keys of every fuzzer parameter and action that c4f supports to their specifications.
+type ParamCatalogue map[string]ParamSpec
+
+// Keys gets the keys in the catalogue in ascending order.
+func (c ParamCatalogue) Keys() []string {
+	ks := make([]string, 0, len(c))
+	for k := range c {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	return ks
+}
+
+// CheckParam checks that the value val is suitable for the parameter key.
+//
+// If cat is nil, CheckParam checks only that key and val are well-formed; otherwise, it also checks that key is in
+// cat, and that val is in its range.
+func CheckParam(key, val string, cat ParamCatalogue) error {
+	if _, err := paramStatement(key, val); err != nil {
+		return err
+	}
+	if cat == nil {
+		return nil
+	}
+	p, ok := cat[key]
+	if !ok {
+		return errUnknownParam(key, cat)
+	}
+	if !p.Bounded {
+		return nil
+	}
+	// paramStatement has already checked that this is an integer.
+	if v, _ := strconv.Atoi(val); v < p.Min || p.Max < v {
+		return fmt.Errorf("%w: %s is %d; want %s", ErrBadParam, key, v, p.Range())
+	}
+	return nil
+}
+
+func errUnknownParam(key string, cat ParamCatalogue) error {
+	if near := nearestKey(key, cat); near != "" {
+		return fmt.Errorf("%w: %q (did you mean %q?)", ErrUnknownParam, key, near)
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownParam, key)
+}
+
+// nearestKey finds the key in cat that is closest to key, if any is close enough to be a likely typo.
+func nearestKey(key string, cat ParamCatalogue) string {
+	best, bestd := "", len(key)/3+1
+	for _, k := range cat.Keys() {
+		if d := editDistance(key, k); d < bestd {
+			best, bestd = k, d
+		}
+	}
+	return best
+}
+
+// editDistance gets the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(minInt(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(x, y int) int {
+	if x < y {
+		return x
+	}
+	return y
+}
+
+// CheckConfig checks every fixed and tuned parameter in the fuzzer configuration cfg, as with CheckParam.
+// It reports every bad parameter at once, in key order.
+func CheckConfig(cfg *fuzzer.Config, cat ParamCatalogue) error {
+	if cfg == nil {
+		return nil
+	}
+	ks := make([]string, 0, len(cfg.Params))
+	for k := range cfg.Params {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+
+	var errs []error
+	for _, k := range ks {
+		errs = append(errs, CheckParam(k, cfg.Params[k], cat))
+	}
+	if cfg.Tune != nil {
+		for _, k := range cfg.Tune.Keys() {
+			errs = append(errs, checkTuned(k, cfg.Tune.Params[k], cat))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func checkTuned(key string, s fuzzer.TuneSpace, cat ParamCatalogue) error {
+	arms, err := s.Arms(key)
+	if err != nil {
+		return err
+	}
+	for _, a := range arms {
+		if err := CheckParam(key, a, cat); err != nil {
+			return fmt.Errorf("tuning %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// ListParams asks c4f for the parameters and actions it supports.
+func (a *Runner) ListParams(ctx context.Context) (ParamCatalogue, error) {
+	cat := ParamCatalogue{}
+	if err := a.list(ctx, "list-params", cat, ParseParamList); err != nil {
+		return nil, err
+	}
+	if err := a.list(ctx, "list-actions", cat, ParseActionList); err != nil {
+		return nil, err
+	}
+	return cat, nil
+}
+
+func (a *Runner) list(ctx context.Context, subcmd string, cat ParamCatalogue, parse func(ParamCatalogue, io.Reader) error) error {
+	var obuf bytes.Buffer
+	cs := CmdSpec{
+		Cmd:    BinC4fFuzz,
+		Subcmd: subcmd,
+		Stdout: &obuf,
+	}
+	if err := a.Run(ctx, cs); err != nil {
+		return err
+	}
+	return parse(cat, &obuf)
+}
+
+// ParseParamList parses the output of 'c4f list-params' from r into cat.
+//
+// Each parameter starts on an unindented line in the form "name [kind] [min..max] [summary]", where kind is 'int' or
+// 'flag'.  Any indented lines that follow continue the parameter: lines of the form "Kind: kind", "Range: min..max",
+// and "Summary: summary" supply the corresponding details, other lines of the form "Name: value" are ignored, and
+// the rest extend the summary.
+func ParseParamList(cat ParamCatalogue, r io.Reader) error {
+	return parseList(r, func(e listEntry) error {
+		kind, ok := e.attr("kind", "type")
+		if 2 <= len(e.head) {
+			kind, ok = e.head[1], true
+		}
+		if !ok {
+			return fmt.Errorf("%w: no kind for %q", ErrParamListParse, e.head[0])
+		}
+		p := ParamSpec{}
+		switch kind {
+		case ParamKindInt:
+			p.Kind = ParamKindInt
+		case listKindFlag, ParamKindBool:
+			p.Kind = ParamKindBool
+		default:
+			return fmt.Errorf("%w: unknown kind %q for %q", ErrParamListParse, kind, e.head[0])
+		}
+		var rest []string
+		if 2 <= len(e.head) {
+			rest = e.head[2:]
+		}
+		if p.Kind == ParamKindInt {
+			if rng, ok := e.attr("range"); ok {
+				rest = append([]string{rng}, rest...)
+			}
+			if len(rest) != 0 {
+				ok, err := parseRange(&p, rest[0])
+				if err != nil {
+					return err
+				}
+				if ok {
+					rest = rest[1:]
+				}
+			}
+		}
+		p.Key = p.Kind + "." + trimKind(e.head[0], p.Kind, kind)
+		p.Summary = e.summary(rest)
+		cat[p.Key] = p
+		return nil
+	})
+}
+
+// parseRange parses f into p's range if it looks like one.
+func parseRange(p *ParamSpec, f string) (bool, error) {
+	lo, hi, ok := strings.Cut(f, "..")
+	if !ok {
+		return false, nil
+	}
+	var err error
+	if p.Min, err = strconv.Atoi(lo); err != nil {
+		return false, fmt.Errorf("%w: bad range %q", ErrParamListParse, f)
+	}
+	if p.Max, err = strconv.Atoi(hi); err != nil {
+		return false, fmt.Errorf("%w: bad range %q", ErrParamListParse, f)
+	}
+	p.Bounded = true
+	return true, nil
+}
+
+// ParseActionList parses the output of 'c4f list-actions' from r into cat.
+//
+// Each action starts on an unindented line in the form "name [summary]".  Any indented lines that follow continue the
+// action: a line of the form "Summary: summary" supplies its summary, other lines of the form "Name: value" (such as
+// "Default weight: 10") are ignored, and the rest extend the summary.
+func ParseActionList(cat ParamCatalogue, r io.Reader) error {
+	return parseList(r, func(e listEntry) error {
+		p := ParamSpec{
+			Key:     ParamKindAction + "." + trimKind(e.head[0], ParamKindAction),
+			Kind:    ParamKindAction,
+			Summary: e.summary(e.head[1:]),
+		}
+		cat[p.Key] = p
+		return nil
+	})
+}
+
+// trimKind removes any of the kind prefixes kinds from the listed name name, in case c4f lists names with them.
+func trimKind(name string, kinds ...string) string {
+	for _, k := range kinds {
+		if n, ok := strings.CutPrefix(name, k+"."); ok {
+			return n
+		}
+	}
+	return name
+}
+
+// listEntry is one parameter or action in one of c4f's lists.
+type listEntry struct {
+	// head contains the fields of the entry's first line, without any colon after the name.
+	head []string
+	// attrs maps the lower-cased names of any "Name: value" lines in the rest of the entry to their values.
+	attrs map[string]string
+	// text contains the other lines in the rest of the entry.
+	text []string
+}
+
+// attr gets the first of the attributes names that e has, if any.
+func (e listEntry) attr(names ...string) (string, bool) {
+	for _, n := range names {
+		if v, ok := e.attrs[n]; ok {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// summary gets e's summary, starting with the head fields rest.
+func (e listEntry) summary(rest []string) string {
+	parts := append([]string{}, rest...)
+	if s, ok := e.attr("summary"); ok {
+		parts = append(parts, s)
+	}
+	return strings.Join(append(parts, e.text...), " ")
+}
+
+// add adds the continuation line fs to e.
+func (e *listEntry) add(fs []string) {
+	line := strings.Join(fs, " ")
+	name, val, ok := strings.Cut(line, ":")
+	switch {
+	case !ok:
+	case val == "":
+		// A heading, such as "Summary:", for the lines that follow it.
+		return
+	default:
+		if e.attrs == nil {
+			e.attrs = map[string]string{}
+		}
+		e.attrs[strings.ToLower(name)] = strings.TrimSpace(val)
+		return
+	}
+	e.text = append(e.text, line)
+}
+
+// parseList calls f on each entry in r.
+//
+// Entries start on unindented lines, and continue on any indented lines that follow.  Blank lines and comments are
+// ignored.
+func parseList(r io.Reader, f func(listEntry) error) error {
+	var (
+		e    listEntry
+		open bool
+	)
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := sc.Text()
+		fs := strings.Fields(line)
+		if len(fs) == 0 || strings.HasPrefix(fs[0], "#") {
+			continue
+		}
+		if open && (line[0] == ' ' || line[0] == '\t') {
+			e.add(fs)
+			continue
+		}
+		if open {
+			if err := f(e); err != nil {
+				return err
+			}
+		}
+		fs[0] = strings.TrimSuffix(fs[0], ":")
+		e, open = listEntry{head: fs}, true
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	if !open {
+		return nil
+	}
+	return f(e)
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package c4f_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/c4f"
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/fuzzer"
+	"github.com/c4-project/c4t/internal/model/service/mocks"
+)
+
+const (
+	testParamList = `# Integer parameters and flags.
+cap.threads int 1..64 maximum number of threads
+action.cap.upper int upper action cap
+mem.unsafe-weaken-orders flag weaken orders unsafely
+`
+	testActionList = `var.make makes a variable
+mem.strengthen strengthens a memory order
+`
+)
+
+func testCatalogue(t *testing.T) c4f.ParamCatalogue {
+	t.Helper()
+	cat := c4f.ParamCatalogue{}
+	require.NoError(t, c4f.ParseParamList(cat, strings.NewReader(testParamList)), "parsing param list")
+	require.NoError(t, c4f.ParseActionList(cat, strings.NewReader(testActionList)), "parsing action list")
+	return cat
+}
+
+// ExampleParseParamList is a runnable example for ParseParamList.
+func ExampleParseParamList() {
+	cat := c4f.ParamCatalogue{}
+	if err := c4f.ParseParamList(cat, strings.NewReader(testParamList)); err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	for _, k := range cat.Keys() {
+		fmt.Printf("%s: %s (%s)\n", k, cat[k].Range(), cat[k].Summary)
+	}
+
+	// Output:
+	// bool.mem.unsafe-weaken-orders: boolean or WINS:LOSSES (weaken orders unsafely)
+	// int.action.cap.upper: integer (upper action cap)
+	// int.cap.threads: 1..64 (maximum number of threads)
+}
+
+// TestParseParamList_multiLine tests that parsing multi-line parameter and action listings gives the same catalogue
+// as parsing the one-line ones.
+func TestParseParamList_multiLine(t *testing.T) {
+	t.Parallel()
+
+	cat := c4f.ParamCatalogue{}
+	for file, parse := range map[string]func(c4f.ParamCatalogue, io.Reader) error{
+		"list-params.txt":  c4f.ParseParamList,
+		"list-actions.txt": c4f.ParseActionList,
+	} {
+		f, err := os.Open(filepath.Join("testdata", file))
+		require.NoError(t, err, "opening", file)
+		err = parse(cat, f)
+		require.NoError(t, f.Close(), "closing", file)
+		require.NoError(t, err, "parsing", file)
+	}
+
+	want := testCatalogue(t)
+	require.ElementsMatch(t, want.Keys(), cat.Keys(), "keys should match one-line listings")
+	for _, k := range want.Keys() {
+		assert.Equal(t, want[k].Range(), cat[k].Range(), "range of %s", k)
+		assert.NotEmpty(t, cat[k].Summary, "summary of %s", k)
+	}
+	assert.Equal(t, "The maximum number of threads that the fuzzer may create.", cat["int.cap.threads"].Summary)
+	assert.Equal(t, "Makes a new variable.", cat["action.var.make"].Summary)
+}
+
+// TestParseParamList_bad tests that ParseParamList rejects malformed lists.
+func TestParseParamList_bad(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"one-field":      "cap.threads",
+		"unknown-kind":   "cap.threads float",
+		"bad-range":      "cap.threads int 1..many",
+		"no-kind":        "cap.threads:\n  Default: 8\n",
+		"bad-attr":       "cap.threads:\n  Kind: float\n",
+		"bad-attr-range": "cap.threads:\n  Kind: int\n  Range: 1..many\n",
+	}
+	for name, in := range cases {
+		in := in
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := c4f.ParseParamList(c4f.ParamCatalogue{}, strings.NewReader(in))
+			assert.ErrorIs(t, err, c4f.ErrParamListParse)
+		})
+	}
+}
+
+// TestCheckParam tests CheckParam on various keys and values, with and without a catalogue.
+func TestCheckParam(t *testing.T) {
+	t.Parallel()
+
+	cat := testCatalogue(t)
+	cases := map[string]struct {
+		key, val string
+		// errNil and errCat are the errors expected without and with the catalogue.
+		errNil, errCat error
+	}{
+		"ok-int":          {key: "int.cap.threads", val: "16"},
+		"ok-bool":         {key: "bool.mem.unsafe-weaken-orders", val: "yes"},
+		"ok-ratio":        {key: "bool.mem.unsafe-weaken-orders", val: "1:3"},
+		"ok-action":       {key: "action.var.make", val: "10"},
+		"out-of-range":    {key: "int.cap.threads", val: "100", errCat: c4f.ErrBadParam},
+		"typo":            {key: "action.var.mkae", val: "10", errCat: c4f.ErrUnknownParam},
+		"wrong-kind":      {key: "bool.cap.threads", val: "true", errCat: c4f.ErrUnknownParam},
+		"bad-int":         {key: "int.cap.threads", val: "six", errNil: c4f.ErrBadParam, errCat: c4f.ErrBadParam},
+		"bad-bool":        {key: "bool.mem.unsafe-weaken-orders", val: ":", errNil: c4f.ErrBadParam, errCat: c4f.ErrBadParam},
+		"bad-kind":        {key: "float.cap.threads", val: "1.0", errNil: c4f.ErrBadParam, errCat: c4f.ErrBadParam},
+		"no-name":         {key: "int", val: "1", errNil: c4f.ErrBadParam, errCat: c4f.ErrBadParam},
+		"empty-key":       {key: "", val: "1", errNil: c4f.ErrBadParam, errCat: c4f.ErrBadParam},
+		"bad-action-text": {key: "action.var.make", val: "ten", errNil: c4f.ErrBadParam, errCat: c4f.ErrBadParam},
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			checkErr(t, c.errNil, c4f.CheckParam(c.key, c.val, nil))
+			checkErr(t, c.errCat, c4f.CheckParam(c.key, c.val, cat))
+		})
+	}
+}
+
+func checkErr(t *testing.T, want, got error) {
+	t.Helper()
+	if want == nil {
+		assert.NoError(t, got)
+	} else {
+		assert.ErrorIs(t, got, want)
+	}
+}
+
+// ExampleCheckParam_typo shows how CheckParam suggests corrections for mistyped keys.
+func ExampleCheckParam_typo() {
+	cat := c4f.ParamCatalogue{}
+	_ = c4f.ParseParamList(cat, strings.NewReader(testParamList))
+	fmt.Println(c4f.CheckParam("int.action.cap.uper", "1000", cat))
+
+	// Output:
+	// unknown fuzzer parameter: "int.action.cap.uper" (did you mean "int.action.cap.upper"?)
+}
+
+// TestCheckConfig tests that CheckConfig reports every bad fixed and tuned parameter.
+func TestCheckConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := fuzzer.Config{
+		Params: map[string]string{
+			"int.cap.threads":      "100",
+			"int.action.cap.upper": "1000",
+			"action.var.mkae":      "10",
+		},
+		Tune: &fuzzer.TuneConfig{Params: map[string]fuzzer.TuneSpace{
+			"action.var.make": {Min: 0, Max: 20, Step: 5},
+			"int.cap.threads": {Min: 32, Max: 96, Step: 32},
+		}},
+	}
+	err := c4f.CheckConfig(&cfg, testCatalogue(t))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, c4f.ErrBadParam)
+	assert.ErrorIs(t, err, c4f.ErrUnknownParam)
+	// Two bad fixed parameters, and one bad tuned parameter.
+	assert.Len(t, strings.Split(err.Error(), "\n"), 3)
+}
+
+// TestRunner_ListParams tests Runner.ListParams using a mock command runner.
+func TestRunner_ListParams(t *testing.T) {
+	t.Parallel()
+
+	var w io.Writer
+	m := new(mocks.Runner)
+	m.Test(t)
+	m.On("WithStdout", mock.Anything).Return(m).Run(func(args mock.Arguments) {
+		w = args.Get(0).(io.Writer)
+	}).Twice()
+	for subcmd, out := range map[string]string{"list-params": testParamList, "list-actions": testActionList} {
+		out := out
+		m.On("Run", mock.Anything, service.RunInfo{
+			Cmd:  c4f.BinC4fFuzz,
+			Args: []string{subcmd},
+		}).Run(func(mock.Arguments) {
+			_, _ = io.WriteString(w, out)
+		}).Return(nil).Once()
+	}
+
+	cat, err := (&c4f.Runner{Base: m}).ListParams(context.Background())
+	require.NoError(t, err, "mocked param listing shouldn't error")
+	assert.Equal(t, testCatalogue(t), cat)
+
+	m.AssertExpectations(t)
+}
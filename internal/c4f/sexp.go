@@ -0,0 +1,176 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package c4f
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrSexpParse occurs when there is a parse error reading an S-expression from c4f.
+var ErrSexpParse = errors.New("S-expression parse error")
+
+// sexp is a minimal S-expression, as emitted by c4f's OCaml sexplib serialisers.
+//
+// An sexp is either an atom (if list is nil and isList is false) or a list.
+type sexp struct {
+	atom   string
+	list   []sexp
+	isList bool
+}
+
+// sexpAtom makes an atom sexp.
+func sexpAtom(a string) sexp {
+	return sexp{atom: a}
+}
+
+// sexpList makes a list sexp.
+func sexpList(xs ...sexp) sexp {
+	return sexp{list: xs, isList: true}
+}
+
+// String prints s on one line, quoting atoms where sexplib would.
+func (s sexp) String() string {
+	var sb strings.Builder
+	s.writeTo(&sb)
+	return sb.String()
+}
+
+func (s sexp) writeTo(sb *strings.Builder) {
+	if !s.isList {
+		sb.WriteString(quoteAtom(s.atom))
+		return
+	}
+	sb.WriteByte('(')
+	for i, x := range s.list {
+		if i != 0 {
+			sb.WriteByte(' ')
+		}
+		x.writeTo(sb)
+	}
+	sb.WriteByte(')')
+}
+
+func quoteAtom(a string) string {
+	if a == "" || strings.IndexFunc(a, needsQuote) != -1 {
+		return strconv.Quote(a)
+	}
+	return a
+}
+
+func needsQuote(r rune) bool {
+	return r == '\\' || isDelim(r)
+}
+
+// isDelim gets whether r ends an unquoted atom.
+func isDelim(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune(`()";`, r)
+}
+
+// parseSexps parses every S-expression in the string in.
+func parseSexps(in string) ([]sexp, error) {
+	p := sexpParser{in: in}
+	var xs []sexp
+	for {
+		p.skip()
+		if p.done() {
+			return xs, nil
+		}
+		x, err := p.parse()
+		if err != nil {
+			return nil, err
+		}
+		xs = append(xs, x)
+	}
+}
+
+type sexpParser struct {
+	in  string
+	pos int
+}
+
+func (p *sexpParser) done() bool {
+	return len(p.in) <= p.pos
+}
+
+// skip skips whitespace and line comments.
+func (p *sexpParser) skip() {
+	for !p.done() {
+		switch c := p.in[p.pos]; {
+		case c == ';':
+			for !p.done() && p.in[p.pos] != '\n' {
+				p.pos++
+			}
+		case unicode.IsSpace(rune(c)):
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *sexpParser) parse() (sexp, error) {
+	switch p.in[p.pos] {
+	case '(':
+		return p.parseList()
+	case ')':
+		return sexp{}, fmt.Errorf("%w: unexpected ')' at offset %d", ErrSexpParse, p.pos)
+	case '"':
+		return p.parseQuoted()
+	default:
+		return p.parseAtom(), nil
+	}
+}
+
+func (p *sexpParser) parseList() (sexp, error) {
+	start := p.pos
+	p.pos++
+	s := sexpList()
+	for {
+		p.skip()
+		if p.done() {
+			return sexp{}, fmt.Errorf("%w: unclosed '(' at offset %d", ErrSexpParse, start)
+		}
+		if p.in[p.pos] == ')' {
+			p.pos++
+			return s, nil
+		}
+		x, err := p.parse()
+		if err != nil {
+			return sexp{}, err
+		}
+		s.list = append(s.list, x)
+	}
+}
+
+func (p *sexpParser) parseQuoted() (sexp, error) {
+	start := p.pos
+	for p.pos++; !p.done(); p.pos++ {
+		switch p.in[p.pos] {
+		case '\\':
+			p.pos++
+		case '"':
+			p.pos++
+			a, err := strconv.Unquote(p.in[start:p.pos])
+			if err != nil {
+				return sexp{}, fmt.Errorf("%w: bad string at offset %d: %s", ErrSexpParse, start, err)
+			}
+			return sexpAtom(a), nil
+		}
+	}
+	return sexp{}, fmt.Errorf("%w: unclosed string at offset %d", ErrSexpParse, start)
+}
+
+func (p *sexpParser) parseAtom() sexp {
+	start := p.pos
+	for !p.done() && !isDelim(rune(p.in[p.pos])) {
+		p.pos++
+	}
+	return sexpAtom(p.in[start:p.pos])
+}
@@ -0,0 +1,244 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package c4f
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/id"
+)
+
+// ErrTraceParse occurs when there is a parse error reading a fuzzer trace.
+var ErrTraceParse = errors.New("trace parse error")
+
+const (
+	traceFieldName    = "name"
+	traceFieldPayload = "payload"
+)
+
+// TraceAction is one action that the c4f fuzzer applied to a test, as recorded in a trace.
+type TraceAction struct {
+	// Name is the fully qualified name of the action, such as 'mem.strengthen'.
+	Name id.ID `json:"name"`
+	// Payload is the action's payload, as a one-line S-expression.
+	// The fuzzer needs the payload to replay the action, but its shape depends on the action.
+	Payload string `json:"payload,omitempty"`
+}
+
+// String prints a into the form used in c4f traces.
+func (a TraceAction) String() string {
+	p := a.Payload
+	if p == "" {
+		p = "()"
+	}
+	return fmt.Sprintf("((%s %s) (%s %s))", traceFieldName, quoteAtom(a.Name.String()), traceFieldPayload, p)
+}
+
+// Trace is a c4f fuzzer trace: the list of actions, in order, that the fuzzer applied to a seed test.
+type Trace []TraceAction
+
+// ReadTrace reads a trace in c4f's S-expression format from r.
+func ReadTrace(r io.Reader) (Trace, error) {
+	bs, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	xs, err := parseSexps(string(bs))
+	if err != nil {
+		return nil, err
+	}
+	if len(xs) != 1 || !xs[0].isList {
+		return nil, fmt.Errorf("%w: trace should be a single list", ErrTraceParse)
+	}
+	t := make(Trace, len(xs[0].list))
+	for i, x := range xs[0].list {
+		if t[i], err = traceActionOfSexp(x); err != nil {
+			return nil, fmt.Errorf("action %d: %w", i, err)
+		}
+	}
+	return t, nil
+}
+
+// ReadTraceFile reads a trace from the file at path.
+func ReadTraceFile(path string) (Trace, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	t, rerr := ReadTrace(f)
+	cerr := f.Close()
+	return t, errhelp.FirstError(rerr, cerr)
+}
+
+// traceActionOfSexp interprets x as a trace action.
+// It accepts both the record form '((name N) (payload P))' and the shorthand '(N P)'.
+func traceActionOfSexp(x sexp) (TraceAction, error) {
+	if !x.isList || len(x.list) == 0 || 2 < len(x.list) {
+		return TraceAction{}, fmt.Errorf("%w: malformed action %s", ErrTraceParse, x)
+	}
+	if f, ok := traceField(x.list[0]); ok && f == traceFieldName {
+		return traceActionOfRecord(x.list)
+	}
+	return traceActionOfPair(x.list)
+}
+
+func traceActionOfRecord(fields []sexp) (TraceAction, error) {
+	var (
+		a   TraceAction
+		err error
+	)
+	for _, f := range fields {
+		name, _ := traceField(f)
+		if len(f.list) != 2 {
+			return TraceAction{}, fmt.Errorf("%w: malformed field %s", ErrTraceParse, f)
+		}
+		switch name {
+		case traceFieldName:
+			a.Name, err = traceActionName(f.list[1])
+		case traceFieldPayload:
+			a.Payload = f.list[1].String()
+		default:
+			err = fmt.Errorf("%w: unknown field %q", ErrTraceParse, name)
+		}
+		if err != nil {
+			return TraceAction{}, err
+		}
+	}
+	if a.Name.IsEmpty() {
+		return TraceAction{}, fmt.Errorf("%w: action has no name", ErrTraceParse)
+	}
+	return a, nil
+}
+
+func traceActionOfPair(xs []sexp) (TraceAction, error) {
+	n, err := traceActionName(xs[0])
+	if err != nil {
+		return TraceAction{}, err
+	}
+	a := TraceAction{Name: n}
+	if len(xs) == 2 {
+		a.Payload = xs[1].String()
+	}
+	return a, nil
+}
+
+// traceField gets the name of the record field x, if x looks like one.
+func traceField(x sexp) (string, bool) {
+	if !x.isList || len(x.list) == 0 || x.list[0].isList {
+		return "", false
+	}
+	return x.list[0].atom, true
+}
+
+// traceActionName interprets x as an action name, either as a dotted atom or a list of tags.
+func traceActionName(x sexp) (id.ID, error) {
+	if !x.isList {
+		return id.TryFromString(x.atom)
+	}
+	tags := make([]string, len(x.list))
+	for i, t := range x.list {
+		if t.isList {
+			return id.ID{}, fmt.Errorf("%w: malformed action name %s", ErrTraceParse, x)
+		}
+		tags[i] = t.atom
+	}
+	return id.New(tags...)
+}
+
+// Write writes t to w in c4f's S-expression format, one action per line.
+func (t Trace) Write(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("(")
+	for i, a := range t {
+		if i != 0 {
+			sb.WriteString("\n ")
+		}
+		sb.WriteString(a.String())
+	}
+	sb.WriteString(")\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// Truncate gets the trace consisting of the first n actions of t.
+// If n is negative or at least the length of t, Truncate returns t unchanged.
+func (t Trace) Truncate(n int) Trace {
+	if n < 0 || len(t) <= n {
+		return t
+	}
+	return t[:n]
+}
+
+// TraceSummary summarises the actions applied across a set of traces, such as those for a fuzzed corpus.
+type TraceSummary struct {
+	// Traces is the number of traces summarised.
+	Traces int `json:"traces"`
+	// Actions is the total number of actions across all traces.
+	Actions int `json:"actions"`
+	// Counts maps each action name to the number of times it appears across all traces.
+	Counts map[id.ID]int `json:"counts,omitempty"`
+}
+
+// Add adds the actions in t to the summary.
+func (s *TraceSummary) Add(t Trace) {
+	if s.Counts == nil {
+		s.Counts = map[id.ID]int{}
+	}
+	s.Traces++
+	s.Actions += len(t)
+	for _, a := range t {
+		s.Counts[a.Name]++
+	}
+}
+
+// Names gets the names of the actions in the summary, in order of descending count, then ascending name.
+func (s TraceSummary) Names() []id.ID {
+	ns := make([]id.ID, 0, len(s.Counts))
+	for n := range s.Counts {
+		ns = append(ns, n)
+	}
+	sort.Slice(ns, func(i, j int) bool {
+		ci, cj := s.Counts[ns[i]], s.Counts[ns[j]]
+		return ci > cj || (ci == cj && ns[i].Less(ns[j]))
+	})
+	return ns
+}
+
+// ReplayTrace wraps the c4f fuzzer's trace replayer, applying t to the seed test at in and writing the result to out.
+//
+// Replaying a truncated trace (see Trace.Truncate) onto a fuzzed subject's seed test gives a smaller fuzz output; this
+// lets us reduce flagged fuzz outputs at the level of fuzzer actions.
+func (a *Runner) ReplayTrace(ctx context.Context, t Trace, in, out string) error {
+	tf, err := makeTraceFile(t)
+	if err != nil {
+		return err
+	}
+	cs := CmdSpec{
+		Cmd:    BinC4fFuzz,
+		Subcmd: "replay",
+		Args:   []string{"-trace-input", tf, "-o", out, in},
+	}
+	rerr := a.Run(ctx, cs)
+	derr := os.Remove(tf)
+	return errhelp.FirstError(rerr, derr)
+}
+
+func makeTraceFile(t Trace) (string, error) {
+	tf, err := os.CreateTemp("", "c4f.*.trace")
+	if err != nil {
+		return "", fmt.Errorf("creating temporary trace file: %w", err)
+	}
+	werr := t.Write(tf)
+	cerr := tf.Close()
+	return tf.Name(), errhelp.FirstError(werr, cerr)
+}
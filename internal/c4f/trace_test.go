@@ -0,0 +1,133 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package c4f_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/c4f"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/mocks"
+)
+
+const testTrace = `; A trace with both action forms.
+(((name var.make) (payload ((basic_type int) (initial_value 27) (name "x 1"))))
+ ((name (mem strengthen)) (payload ((path (0 1)) (mo memory_order_seq_cst))))
+ (program.make.empty ())
+ ((name var.make) (payload ((basic_type bool)))))
+`
+
+// ExampleReadTrace is a runnable example for ReadTrace.
+func ExampleReadTrace() {
+	t, err := c4f.ReadTrace(strings.NewReader(testTrace))
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	for _, a := range t.Truncate(3) {
+		fmt.Println(a.Name, a.Payload)
+	}
+
+	// Output:
+	// var.make ((basic_type int) (initial_value 27) (name "x 1"))
+	// mem.strengthen ((path (0 1)) (mo memory_order_seq_cst))
+	// program.make.empty ()
+}
+
+// ExampleTraceSummary_Names is a runnable example for TraceSummary.Names.
+func ExampleTraceSummary_Names() {
+	var s c4f.TraceSummary
+	s.Add(c4f.Trace{{Name: id.FromString("mem.strengthen")}, {Name: id.FromString("var.make")}})
+	s.Add(c4f.Trace{{Name: id.FromString("var.make")}, {Name: id.FromString("dead.early-out")}})
+
+	fmt.Println(s.Traces, "traces,", s.Actions, "actions")
+	for _, n := range s.Names() {
+		fmt.Println(n, s.Counts[n])
+	}
+
+	// Output:
+	// 2 traces, 4 actions
+	// var.make 2
+	// dead.early-out 1
+	// mem.strengthen 1
+}
+
+// TestTrace_Write_roundTrip tests that writing a trace and reading it back gives the same trace.
+func TestTrace_Write_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	want, err := c4f.ReadTrace(strings.NewReader(testTrace))
+	require.NoError(t, err, "reading test trace")
+
+	var sb strings.Builder
+	require.NoError(t, want.Write(&sb), "writing test trace")
+	got, err := c4f.ReadTrace(strings.NewReader(sb.String()))
+	require.NoError(t, err, "reading written trace")
+	assert.Equal(t, want, got)
+}
+
+// TestReadTrace_bad tests that ReadTrace rejects various malformed traces.
+func TestReadTrace_bad(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"empty":         "",
+		"atom":          "var.make",
+		"two-lists":     "() ()",
+		"unclosed":      "(((name var.make) (payload ())",
+		"unopened":      "((name var.make) (payload ())))",
+		"unclosed-str":  `(((name var.make) (payload "foo)))`,
+		"no-name":       "(((payload ())))",
+		"unknown-field": "(((name var.make) (flavour strawberry)))",
+		"long-action":   "((var.make () ()))",
+	}
+	for name, in := range cases {
+		in := in
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := c4f.ReadTrace(strings.NewReader(in))
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestRunner_ReplayTrace tests the happy path of Runner.ReplayTrace using a mock command runner.
+func TestRunner_ReplayTrace(t *testing.T) {
+	tr := c4f.Trace{{Name: id.FromString("mem.strengthen"), Payload: "((path (0 1)))"}}
+
+	var tpath string
+	cr := new(mocks.Runner)
+	cr.Test(t)
+	cr.On("Run", mock.Anything, mock.MatchedBy(func(c service.RunInfo) bool {
+		if c.Cmd != c4f.BinC4fFuzz || len(c.Args) != 6 || c.Args[0] != "replay" || c.Args[1] != "-trace-input" {
+			return false
+		}
+		tpath = c.Args[2]
+		return c.Args[3] == "-o" && c.Args[4] == "out.litmus" && c.Args[5] == "in.litmus"
+	})).Return(func(context.Context, service.RunInfo) error {
+		// The trace file should exist, and contain the trace, while c4f is running.
+		got, err := c4f.ReadTraceFile(tpath)
+		require.NoError(t, err, "reading replayed trace")
+		assert.Equal(t, tr, got)
+		return nil
+	}).Once()
+
+	a := c4f.Runner{Base: cr}
+	require.NoError(t, a.ReplayTrace(context.Background(), tr, "in.litmus", "out.litmus"))
+	cr.AssertExpectations(t)
+
+	_, err := os.Stat(tpath)
+	assert.ErrorIs(t, err, os.ErrNotExist, "trace file should be removed after replay")
+}
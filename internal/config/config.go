@@ -17,6 +17,7 @@ import (
 	"github.com/c4-project/c4t/internal/model/service/fuzzer"
 	"github.com/c4-project/c4t/internal/quantity"
 	"github.com/c4-project/c4t/internal/remote"
+	"github.com/c4-project/c4t/internal/stage/analyser/saver"
 )
 
 // Config is a top-level tester config struct.
@@ -41,6 +42,9 @@ type Config struct {
 
 	// Paths contains path configuration for the config file.
 	Paths Pathset `toml:"paths,omitempty"`
+
+	// Save contains configuration for how the director saves failing runs.
+	Save saver.Config `toml:"save,omitempty"`
 }
 
 // Machines gets the checked, fully processed machine config map.
@@ -87,6 +91,21 @@ func (c *Config) DisableFuzz() {
 	c.Fuzz.Disabled = true
 }
 
+// Isolate points this config's output at outDir, and unsets every file and target through which a run would change
+// state shared with other runs: the suppression, warning, and timing databases, the promoted corpus, and the remote
+// save target and its retention policy.
+//
+// Replays use this so that they neither disturb the original run's output nor record themselves as part of it.
+func (c *Config) Isolate(outDir string) {
+	c.Paths.OutDir = outDir
+	c.Paths.SuppressionFile = ""
+	c.Paths.WarningFile = ""
+	c.Paths.TimingFile = ""
+	c.Paths.CorpusDir = ""
+	c.Save.Target = ""
+	c.Save.Retention = saver.Retention{}
+}
+
 // OverrideInputs is shorthand for setting this config's inputs to files, if non-empty.
 func (c *Config) OverrideInputs(files []string) error {
 	// TODO(@MattWindsor91): push this into pathset?
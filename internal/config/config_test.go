@@ -7,8 +7,15 @@ package config_test
 
 import (
 	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/stage/analyser/saver"
 
 	"github.com/c4-project/c4t/internal/config"
 )
@@ -61,3 +68,43 @@ func ExampleConfig_OverrideQuantities() {
 	// 20
 	// 8
 }
+
+// TestConfig_Isolate tests that an isolated config shares no files or targets with the original.
+func TestConfig_Isolate(t *testing.T) {
+	t.Parallel()
+
+	c := config.Config{
+		Paths: config.Pathset{
+			OutDir:          "orig",
+			Inputs:          []string{"in.litmus"},
+			FilterFile:      "filters.yaml",
+			SuppressionFile: "suppressions.yaml",
+			WarningFile:     "warnings.json",
+			TimingFile:      "timings.json",
+			CorpusDir:       "corpus",
+		},
+		Save: saver.Config{
+			Format:    saver.FormatZip,
+			Target:    "sftp://example.com/saved",
+			Retention: saver.Retention{MaxAge: time.Hour, MaxSize: 1024},
+		},
+	}
+	c.Isolate("replay")
+
+	assert.Equal(t, config.Pathset{
+		OutDir:     "replay",
+		Inputs:     []string{"in.litmus"},
+		FilterFile: "filters.yaml",
+	}, c.Paths, "only the output directory and read-only inputs should remain")
+	assert.Equal(t, saver.Config{Format: saver.FormatZip}, c.Save, "only the save format should remain")
+
+	for name, f := range map[string]func() (string, error){
+		"stats":   c.Paths.StatFile,
+		"history": c.Paths.HistoryFile,
+		"tuner":   c.Paths.TunerFile,
+	} {
+		path, err := f()
+		require.NoError(t, err, "getting", name, "file")
+		assert.Equal(t, "replay", filepath.Dir(path), "%s file should be in the isolated output directory", name)
+	}
+}
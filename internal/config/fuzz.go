@@ -0,0 +1,35 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/c4-project/c4t/internal/c4f"
+	fuzzimpl "github.com/c4-project/c4t/internal/serviceimpl/fuzzer"
+)
+
+// CheckFuzzParams checks the fuzzer parameters in this config against cat, as with c4f.CheckConfig.
+// If cat is nil, it checks only that the parameters are well-formed.
+//
+// It checks nothing if the config uses a fuzzer style other than c4f, as the parameters don't apply to it.
+func (c *Config) CheckFuzzParams(cat c4f.ParamCatalogue) error {
+	if !c.HasC4fParams() {
+		return nil
+	}
+	if err := c4f.CheckConfig(c.Fuzz, cat); err != nil {
+		return fmt.Errorf("checking fuzzer parameters: %w", err)
+	}
+	return nil
+}
+
+// HasC4fParams gets whether this config uses the c4f fuzzer and sets parameters for it.
+func (c *Config) HasC4fParams() bool {
+	if c.Fuzz == nil || c.Fuzz.Disabled || (len(c.Fuzz.Params) == 0 && c.Fuzz.Tune == nil) {
+		return false
+	}
+	return c.Fuzz.Style.IsEmpty() || c.Fuzz.Style.Equal(fuzzimpl.StyleC4f)
+}
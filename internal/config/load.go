@@ -54,6 +54,10 @@ func Load(override string) (*Config, error) {
 	if _, err := toml.DecodeFile(path, &c); err != nil {
 		return nil, err
 	}
+	// We can't check parameters against the fuzzer's own list here, as that means running the fuzzer.
+	if err := c.CheckFuzzParams(nil); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
 
 	return &c, nil
 }
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/c4-project/c4t/internal/c4f"
 	"github.com/c4-project/c4t/internal/config"
 	"github.com/stretchr/testify/assert"
 
@@ -33,3 +34,9 @@ func TestLoad_broken(t *testing.T) {
 	_, err := config.Load(filepath.Join("testdata", "not_toml.txt"))
 	require.Error(t, err, "should error")
 }
+
+// TestLoad_badParams tests config.Load with a file whose fuzzer parameters are malformed.
+func TestLoad_badParams(t *testing.T) {
+	_, err := config.Load(filepath.Join("testdata", "bad_params.toml"))
+	require.ErrorIs(t, err, c4f.ErrBadParam, "should reject malformed fuzzer parameter")
+}
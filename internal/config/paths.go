@@ -23,6 +23,22 @@ type Pathset struct {
 
 	// FilterFile is, if present, a path pointing to a YAML file containing analysis filter definitions.
 	FilterFile string `toml:"filter_file,omitempty,omitzero"`
+
+	// SuppressionFile is, if present, a path pointing to a YAML file containing known-bug suppressions.
+	// The director never writes to this file; it tracks suppression hits in a state file alongside it.
+	SuppressionFile string `toml:"suppression_file,omitempty,omitzero"`
+
+	// WarningFile is, if present, a path pointing to a JSON file in which the director tracks compiler warnings, so
+	// that it can report warnings that are new since previous cycles.
+	WarningFile string `toml:"warning_file,omitempty,omitzero"`
+
+	// TimingFile is, if present, a path pointing to a JSON file in which the director tracks per-subject compile and
+	// run times, so that it can report times that regress significantly against previous cycles.
+	TimingFile string `toml:"timing_file,omitempty,omitzero"`
+
+	// CorpusDir is, if present, a path pointing to a directory into which the director promotes fuzzer outputs that
+	// showed new behaviour, and from which it draws extra inputs on later cycles.
+	CorpusDir string `toml:"corpus_dir,omitempty,omitzero"`
 }
 
 // FallbackToInputs returns fs if non-empty, and the homedir-expanded version of Pathset.Inputs on p otherwise.
@@ -43,3 +59,13 @@ func (p Pathset) OutPath(file string) (string, error) {
 func (p Pathset) StatFile() (string, error) {
 	return p.OutPath("stats.json")
 }
+
+// HistoryFile is shorthand for getting the statistics history file path.
+func (p Pathset) HistoryFile() (string, error) {
+	return p.OutPath("history.jsonl")
+}
+
+// TunerFile is shorthand for getting the path of the file in which the fuzzer parameter tuner keeps its statistics.
+func (p Pathset) TunerFile() (string, error) {
+	return p.OutPath("tuner.json")
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package pretty
+
+import (
+	"github.com/c4-project/c4t/internal/c4f"
+	"github.com/c4-project/c4t/internal/config"
+	"github.com/c4-project/c4t/internal/tabulator"
+)
+
+// TabulateFuzzParams writes a human-readable table of the fuzzer parameters in cat to t.
+// If c is non-nil, the table also shows how c sets each parameter.
+func TabulateFuzzParams(t tabulator.Tabulator, cat c4f.ParamCatalogue, c *config.Config) error {
+	t.Header("Key", "Range", "Setting", "Summary")
+	for _, k := range cat.Keys() {
+		p := cat[k]
+		t.Cell(k).Cell(p.Range()).Cell(fuzzSetting(c, k)).Cell(p.Summary).EndRow()
+	}
+	return t.Flush()
+}
+
+func fuzzSetting(c *config.Config, key string) string {
+	if c == nil || c.Fuzz == nil {
+		return ""
+	}
+	if c.Fuzz.Tune != nil {
+		if _, ok := c.Fuzz.Tune.Params[key]; ok {
+			return "tuned"
+		}
+	}
+	return c.Fuzz.Params[key]
+}
@@ -33,6 +33,12 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/c4-project/c4t/internal/helper/iohelp"
+
+	"github.com/c4-project/c4t/internal/stage/analyser/promoter"
+	"github.com/c4-project/c4t/internal/stage/analyser/saver"
+
+	"github.com/c4-project/c4t/internal/stage/fuzzer/tuner"
+	"github.com/c4-project/c4t/internal/stage/perturber"
 )
 
 // Director contains the main state and configuration for the test director.
@@ -51,12 +57,28 @@ type Director struct {
 	ssh *remote.Config
 	// fcfg, if present, provides fuzzer configuration.
 	fcfg *fuzzer2.Config
+	// tuner, if non-nil, adaptively tunes fuzzer parameters across all instances.
+	tuner *tuner.Bandit
 	// quantities contains various tunable quantities for the director's stages.
 	quantities quantity.RootSet
 	// files is the input file set.
 	files []string
 	// filters is the set of compiled filter sets to use in analysis.
 	filters analysis.FilterSet
+	// suppressions, if non-nil, is the known-bug suppression database shared by all analyses.
+	suppressions *analysis.SuppressionDB
+	// warnings, if non-nil, is the compiler warning database shared by all analyses.
+	warnings *analysis.WarningDB
+	// timings, if non-nil, is the subject timing database shared by all analyses.
+	timings *analysis.TimingDB
+	// promoter, if non-nil, promotes fuzzer outputs with new behaviours into a corpus directory shared by all instances.
+	promoter *promoter.Promoter
+	// skipKnown is true if saving should skip subjects whose failures fall into already-saved clusters.
+	skipKnown bool
+	// yields, if non-nil, is the source of historical subject yields shared by all perturbers.
+	yields perturber.YieldSource
+	// saveConfig configures the format, target, and retention policy of saved runs.
+	saveConfig saver.Config
 }
 
 // New creates a new Director with driver set e, input paths files, machines ms, and options opt.
@@ -124,11 +146,30 @@ func (d *Director) initInstance(i int, mid id.ID, mc machine.Config) error {
 		Observers:    obs,
 		Machine:      &m,
 		Filters:      d.filters,
+		Suppressions: d.suppressions,
+		Warnings:     d.warnings,
+		Timings:      d.timings,
+		Promoter:     d.promoter,
+		CycleHooks:   d.cycleHooks(),
+		SkipKnown:    d.skipKnown,
+		Yields:       d.yields,
+		SaveConfig:   d.saveConfig,
 		FuzzerConfig: d.fcfg,
+		Tuner:        d.tuner,
 	}
 	return nil
 }
 
+// cycleHooks gets the hooks each instance should run before each cycle.
+func (d *Director) cycleHooks() []func(context.Context, *Instance) error {
+	if d.promoter == nil {
+		return nil
+	}
+	return []func(context.Context, *Instance) error{
+		func(ctx context.Context, i *Instance) error { return i.refreshCorpus(ctx, d.quantities.Plan) },
+	}
+}
+
 func (d *Director) machineQuantities(c *machine.Config) quantity.MachineSet {
 	if c.Quantities == nil {
 		return d.quantities.MachineSet
@@ -0,0 +1,65 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package director
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/c4-project/c4t/internal/quantity"
+
+	"github.com/c4-project/c4t/internal/stage/planner"
+)
+
+// refreshCorpus adds any files promoted into the instance's corpus directory since the last cycle to its initial plan,
+// so that the next cycle can sample and fuzz them.
+// It uses quantities qs to probe the new files.
+func (i *Instance) refreshCorpus(ctx context.Context, qs quantity.PlanSet) error {
+	files, err := planner.ExpandLitmusInputs([]string{i.Promoter.Dir()})
+	if err != nil {
+		return err
+	}
+	fresh := i.unplannedFiles(files)
+	if len(fresh) == 0 {
+		return nil
+	}
+
+	cp := planner.CorpusPlanner{
+		Files:      fresh,
+		Prober:     i.Env.Planner.SProbe,
+		Quantities: qs,
+	}
+	c, err := cp.Plan(ctx)
+	if err != nil {
+		return fmt.Errorf("probing promoted corpus: %w", err)
+	}
+
+	// Running cycles may still be looking at the old corpus, so we copy it rather than adding to it in place.
+	nc := i.Machine.InitialPlan.Corpus.Copy()
+	for name, s := range c {
+		if err := nc.Add(*s.AddName(name)); err != nil {
+			return err
+		}
+	}
+	i.Machine.InitialPlan.Corpus = nc
+	return nil
+}
+
+// unplannedFiles filters files down to those that aren't the source of any subject in the instance's initial plan.
+func (i *Instance) unplannedFiles(files []string) []string {
+	known := make(map[string]struct{}, len(i.Machine.InitialPlan.Corpus))
+	for _, s := range i.Machine.InitialPlan.Corpus {
+		known[filepath.Clean(s.Source.Filepath())] = struct{}{}
+	}
+	var fresh []string
+	for _, f := range files {
+		if _, ok := known[filepath.Clean(f)]; !ok {
+			fresh = append(fresh, f)
+		}
+	}
+	return fresh
+}
@@ -10,6 +10,8 @@ import (
 	"errors"
 	"time"
 
+	"github.com/c4-project/c4t/internal/helper/srvrun"
+
 	"github.com/c4-project/c4t/internal/mutation"
 
 	fuzzer2 "github.com/c4-project/c4t/internal/model/service/fuzzer"
@@ -31,10 +33,14 @@ import (
 	"github.com/c4-project/c4t/internal/stage/lifter"
 
 	"github.com/c4-project/c4t/internal/stage/fuzzer"
+	"github.com/c4-project/c4t/internal/stage/fuzzer/tuner"
 
 	"github.com/c4-project/c4t/internal/plan"
 
 	"github.com/c4-project/c4t/internal/helper/iohelp"
+
+	"github.com/c4-project/c4t/internal/stage/analyser/promoter"
+	"github.com/c4-project/c4t/internal/stage/analyser/saver"
 )
 
 // Instance contains the state necessary to run a single loop of a director.
@@ -51,14 +57,33 @@ type Instance struct {
 	SSHConfig *remote.Config
 	// Filters contains the precompiled filter set for this instance.
 	Filters analysis.FilterSet
+	// Suppressions, if non-nil, is the known-bug suppression database for this instance's analyses.
+	Suppressions *analysis.SuppressionDB
+	// Warnings, if non-nil, is the compiler warning database for this instance's analyses.
+	Warnings *analysis.WarningDB
+	// Timings, if non-nil, is the subject timing database for this instance's analyses.
+	Timings *analysis.TimingDB
+	// Promoter, if non-nil, promotes fuzzer outputs with new behaviours into a corpus directory.
+	Promoter *promoter.Promoter
+	// Yields, if non-nil, is the source of historical subject yields for the perturber's weighted corpus sampling.
+	Yields perturber.YieldSource
+	// SkipKnown is true if the analyser should skip saving subjects whose failures fall into already-saved clusters.
+	SkipKnown bool
+	// SaveConfig configures the format, target, and retention policy of saved runs.
+	SaveConfig saver.Config
 
 	// CycleHooks contains a number of callbacks that are executed before beginning a cycle.
-	CycleHooks []func(*Instance) error
+	CycleHooks []func(context.Context, *Instance) error
 
 	// TODO(@MattWindsor91): this configuration should ideally be per-machine, and then should be moved to Machine.
 
 	// FuzzerConfig contains the fuzzer config for this instance.
 	FuzzerConfig *fuzzer2.Config
+	// Tuner, if non-nil, adaptively tunes fuzzer parameters; it is shared with every other instance.
+	Tuner *tuner.Bandit
+
+	// replay, if non-nil, describes the past cycle that this instance is replaying.
+	replay *Replay
 
 	// mutantCh stores a channel that will receive mutations, if any.
 	mutantCh <-chan mutation.Mutant
@@ -119,7 +144,7 @@ func (i *Instance) cleanUp() error {
 func (m *Machine) cleanUp() error {
 	var err error
 	for _, r := range m.stages {
-		err = r.Close()
+		err = errhelp.FirstError(err, r.Close())
 	}
 	return err
 }
@@ -212,6 +237,11 @@ func (i *Instance) handleError(err error, res cycleResult) {
 func (i *Instance) launch(ctx context.Context) {
 	i.timeoutCh = nil
 
+	if err := i.runCycleHooks(ctx); err != nil {
+		i.handleError(err, cycleResult{cycle: i.nextCycle(), err: err})
+		return
+	}
+
 	c := i.makeCycleInstance()
 	OnCycle(CycleStartMessage(c.cycle), i.Observers...)
 
@@ -228,19 +258,33 @@ func (i *Instance) launch(ctx context.Context) {
 	i.cycleCh = ch
 }
 
+func (i *Instance) runCycleHooks(ctx context.Context) error {
+	for _, h := range i.CycleHooks {
+		if err := h(ctx, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (i *Instance) makeCycleInstance() cycleInstance {
 	return cycleInstance{
-		cycle: Cycle{
-			Instance:  i.Index,
-			MachineID: i.Machine.ID,
-			Iter:      i.Machine.cycle,
-			Start:     time.Now(),
-		},
+		cycle:  i.nextCycle(),
 		p:      i.plan(),
 		stages: i.Machine.stages,
 	}
 }
 
+// nextCycle gets information about the cycle this instance is about to launch.
+func (i *Instance) nextCycle() Cycle {
+	return Cycle{
+		Instance:  i.Index,
+		MachineID: i.Machine.ID,
+		Iter:      i.Machine.cycle,
+		Start:     time.Now(),
+	}
+}
+
 func (i *Instance) plan() *plan.Plan {
 	// Important to _copy_ the plan
 	pcopy := i.Machine.InitialPlan
@@ -269,7 +313,13 @@ func (i *Instance) makeStages() ([]plan.Runner, error) {
 	return stages, nil
 }
 
+// makeAnalyser makes a plan runner for the analyser stage.
+// When replaying, the analyser neither teaches the tuner nor promotes subjects, as the original cycle already did both.
 func (i *Instance) makeAnalyser() (plan.Runner, error) {
+	tun, prom := i.Tuner, i.Promoter
+	if i.replay != nil {
+		tun, prom = nil, nil
+	}
 	return analyser.New(
 		analyser.ObserveWith(LowerToAnalyser(i.Observers)...),
 		analyser.ObserveSaveWith(LowerToSaver(i.Observers)...),
@@ -278,6 +328,14 @@ func (i *Instance) makeAnalyser() (plan.Runner, error) {
 			analysis.WithFilters(i.Filters),
 		),
 		analyser.SaveToPathset(&i.Machine.Pathset.Saved),
+		analyser.SkipKnownClusters(i.SkipKnown),
+		analyser.SaveWith(i.SaveConfig, i.SSHConfig),
+		analyser.ReproduceWith(i.Env.CDriver),
+		analyser.Suppress(i.Suppressions),
+		analyser.TrackWarnings(i.Warnings),
+		analyser.TrackTimings(i.Timings),
+		analyser.TuneWith(tun),
+		analyser.PromoteTo(prom),
 	)
 }
 
@@ -287,6 +345,8 @@ func (i *Instance) makePerturber() (plan.Runner, error) {
 		perturber.ObserveWith(LowerToPerturber(i.Observers)...),
 		perturber.OverrideQuantities(i.Machine.Quantities.Perturb),
 		perturber.UseFullCompilerIDs(true),
+		perturber.UseYields(i.Yields),
+		perturber.UseSeed(i.seed()),
 	)
 }
 
@@ -297,15 +357,37 @@ func (i *Instance) makeFuzzer() (plan.Runner, error) {
 		return nil, nil
 	}
 
+	sr := i.Env.FRunner
+	if sr == nil {
+		sr = srvrun.NewExecRunner()
+	}
+	drv, err := fuzzer.ResolveDriver(i.Env.Fuzzer, i.FuzzerConfig, i.Env.FResolver, sr)
+	if err != nil {
+		return nil, err
+	}
+
 	return fuzzer.New(
-		i.Env.Fuzzer,
+		drv,
 		fuzzer.NewPathset(i.Machine.Pathset.Scratch.DirFuzz),
 		fuzzer.ObserveWith(LowerToBuilder(i.Observers)...),
 		fuzzer.OverrideQuantities(i.Machine.Quantities.Fuzz),
 		fuzzer.UseConfig(i.FuzzerConfig),
+		fuzzer.TuneWith(i.fuzzTuner()),
 	)
 }
 
+// fuzzTuner gets the instance's tuner as a fuzzer tuner, taking care not to wrap a nil tuner in an interface.
+// When replaying, the tuner instead fixes the parameters to those of the replayed cycle.
+func (i *Instance) fuzzTuner() fuzzer.Tuner {
+	if i.replay != nil {
+		return fuzzer.FixedParams(i.replay.Params)
+	}
+	if i.Tuner == nil {
+		return nil
+	}
+	return i.Tuner
+}
+
 func (i *Instance) makeLifter() (plan.Runner, error) {
 	return lifter.New(
 		i.Env.BResolver,
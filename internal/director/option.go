@@ -10,6 +10,7 @@ import (
 
 	"github.com/c4-project/c4t/internal/model/service/backend"
 
+	"github.com/c4-project/c4t/internal/model/service"
 	fuzzer2 "github.com/c4-project/c4t/internal/model/service/fuzzer"
 
 	"github.com/c4-project/c4t/internal/plan/analysis"
@@ -36,6 +37,13 @@ import (
 	"github.com/c4-project/c4t/internal/stage/planner"
 
 	"github.com/c4-project/c4t/internal/config"
+
+	"github.com/c4-project/c4t/internal/stage/analyser/promoter"
+	"github.com/c4-project/c4t/internal/stage/analyser/saver"
+
+	"github.com/c4-project/c4t/internal/stage/mach/interpreter"
+
+	"github.com/c4-project/c4t/internal/stage/fuzzer/tuner"
 )
 
 var (
@@ -129,6 +137,97 @@ func Filters(fs analysis.FilterSet) Option {
 	}
 }
 
+// SuppressionsFromFile opens a suppression database from path, if it is non-blank.
+func SuppressionsFromFile(path string) Option {
+	return func(d *Director) error {
+		if ystring.IsBlank(path) {
+			return nil
+		}
+		epath, err := homedir.Expand(path)
+		if err != nil {
+			return err
+		}
+		d.suppressions, err = analysis.OpenSuppressionDB(epath)
+		return err
+	}
+}
+
+// WarningsFromFile opens a warning database from path, if it is non-blank.
+// The file need not exist yet.
+func WarningsFromFile(path string) Option {
+	return func(d *Director) error {
+		if ystring.IsBlank(path) {
+			return nil
+		}
+		epath, err := homedir.Expand(path)
+		if err != nil {
+			return err
+		}
+		d.warnings, err = analysis.OpenWarningDB(epath)
+		return err
+	}
+}
+
+// TimingsFromFile opens a timing database from path, if it is non-blank.
+// The file need not exist yet.
+func TimingsFromFile(path string) Option {
+	return func(d *Director) error {
+		if ystring.IsBlank(path) {
+			return nil
+		}
+		epath, err := homedir.Expand(path)
+		if err != nil {
+			return err
+		}
+		d.timings, err = analysis.OpenTimingDB(epath)
+		return err
+	}
+}
+
+// EvolveCorpusIn makes the director promote fuzzer outputs that showed new behaviour into dir, if it is non-blank,
+// and use the files in dir as extra inputs.
+// The directory need not exist yet.
+func EvolveCorpusIn(dir string) Option {
+	return func(d *Director) error {
+		if ystring.IsBlank(dir) {
+			return nil
+		}
+		edir, err := homedir.Expand(dir)
+		if err != nil {
+			return err
+		}
+		if d.promoter, err = promoter.Open(edir); err != nil {
+			return err
+		}
+		d.files = append(d.files, edir)
+		return nil
+	}
+}
+
+// SaveConfig sets the format, target, and retention policy of the director's saved runs to cfg.
+func SaveConfig(cfg saver.Config) Option {
+	return func(d *Director) error {
+		d.saveConfig = cfg
+		return nil
+	}
+}
+
+// SkipKnownClusters sets whether the director's analysers skip saving subjects whose failures are already known.
+func SkipKnownClusters(on bool) Option {
+	return func(d *Director) error {
+		d.skipKnown = on
+		return nil
+	}
+}
+
+// YieldsFrom makes the director's perturbers read historical subject yields from ys when weighting corpus samples.
+func YieldsFrom(ys perturber.YieldSource) Option {
+	return func(d *Director) error {
+		d.yields = ys
+		return nil
+	}
+}
+
 // FiltersFromFile loads a filter set from path, if it is non-blank.
 func FiltersFromFile(path string) Option {
 	return func(d *Director) error {
@@ -144,10 +243,32 @@ func FiltersFromFile(path string) Option {
 }
 
 // FuzzerConfig sets the fuzzer configuration to cfg.
+// If cfg asks for parameter tuning, this also sets up a tuner shared by all instances.
 func FuzzerConfig(cfg *fuzzer2.Config) Option {
 	return func(d *Director) error {
 		d.fcfg = cfg
-		return nil
+		d.tuner = nil
+		if cfg == nil || cfg.Tune == nil {
+			return nil
+		}
+		var err error
+		d.tuner, err = tuner.New(cfg.Tune)
+		return err
+	}
+}
+
+// PersistTunerIn makes any tuner set up by FuzzerConfig load its statistics from, and save them to, the tuner file
+// in ps.
+func PersistTunerIn(ps config.Pathset) Option {
+	return func(d *Director) error {
+		if d.tuner == nil {
+			return nil
+		}
+		path, err := ps.TunerFile()
+		if err != nil {
+			return err
+		}
+		return d.tuner.PersistTo(path)
 	}
 }
 
@@ -160,9 +281,20 @@ type Env struct {
 	// BResolver is a backend resolver.
 	BResolver backend.Resolver
 
+	// FResolver, if non-nil, resolves any fuzzer style named in the fuzzer config into a fuzzer that replaces Fuzzer
+	// for fuzzing (but not statistics dumping).
+	FResolver fuzzer2.Resolver
+
+	// FRunner, if non-nil, runs the external services of any fuzzer that FResolver resolves.
+	// If nil, those services run with their standard error discarded.
+	FRunner service.Runner
+
 	// CInspector is the compiler inspector used for perturbing compiler optimisation levels.
 	CInspector compiler.Inspector
 
+	// CDriver, if non-nil, is the compiler driver used to work out compiler invocations for reproduction bundles.
+	CDriver interpreter.Driver
+
 	// Planner instructs any planners built for this director as to how to acquire information about compilers, etc.
 	Planner planner.Source
 }
@@ -186,9 +318,15 @@ func (e Env) Check() error {
 func ConfigFromGlobal(g *config.Config) Option {
 	return Options(
 		FiltersFromFile(g.Paths.FilterFile),
+		SuppressionsFromFile(g.Paths.SuppressionFile),
+		WarningsFromFile(g.Paths.WarningFile),
+		TimingsFromFile(g.Paths.TimingFile),
+		EvolveCorpusIn(g.Paths.CorpusDir),
+		SaveConfig(g.Save),
 		OutDir(g.Paths.OutDir),
 		OverrideQuantities(g.Quantities),
 		FuzzerConfig(g.Fuzz),
+		PersistTunerIn(g.Paths),
 		SSH(g.SSH),
 	)
 }
@@ -8,6 +8,7 @@
 package pathset
 
 import (
+	"path"
 	"path/filepath"
 
 	"github.com/c4-project/c4t/internal/stage/analyser/saver"
@@ -43,8 +44,10 @@ func (p Pathset) Instance(mid id.ID) *Instance {
 	saved := append([]string{p.DirSaved}, tags...)
 	scratch := append([]string{p.DirScratch}, tags...)
 	// TODO(@MattWindsor91): the pointer soup here needs simplifying
+	sp := saver.NewPathset(filepath.Join(saved...))
+	sp.TargetPrefix = path.Join(tags...)
 	return &Instance{
-		Saved:   *saver.NewPathset(filepath.Join(saved...)),
+		Saved:   *sp,
 		Scratch: *NewScratch(filepath.Join(scratch...)),
 	}
 }
@@ -52,6 +52,7 @@ func ExamplePathset_Instance() {
 	// saved/foo/bar/baz/compile_timeout
 	// saved/foo/bar/baz/run_fail
 	// saved/foo/bar/baz/run_timeout
+	// saved/foo/bar/baz/run_limit
 }
 
 // TestPathset_Prepare tests Scratch.Prepare.
@@ -0,0 +1,195 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package director
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/mutation"
+	"github.com/c4-project/c4t/internal/plan"
+)
+
+// ErrNoReplayMachine occurs when we try to replay a cycle on a machine the director doesn't have.
+var ErrNoReplayMachine = errors.New("no such machine to replay on")
+
+// Replay describes a past cycle for the director to replay.
+//
+// A cycle's perturbed compilers, sampled corpus, and fuzzer seeds all derive from its plan seed, so replaying the
+// cycle needs only that seed, the initial plan, and any fuzzer parameters that were adaptively tuned for the cycle.
+// Replays of cycles that sampled by yield, or that drew on an evolving corpus, are only exact if the yields and
+// corpus are as they were at the time.
+type Replay struct {
+	// Machine is the ID of the machine on which the cycle ran.
+	Machine id.ID `json:"machine"`
+	// Iter is the iteration number of the cycle; it serves only to label the replayed cycle.
+	Iter uint64 `json:"iter,omitempty"`
+	// Seed is the seed of the cycle's plan.
+	Seed int64 `json:"seed"`
+	// Mutant is the mutant that the cycle tested, if it was a mutation test.
+	Mutant mutation.Mutant `json:"mutant,omitempty"`
+	// Params contains the values of any adaptively tuned fuzzer parameters that the cycle used.
+	Params map[string]string `json:"params,omitempty"`
+	// Hashes maps the names of subjects that the cycle fuzzed to the hashes of their fuzzed files, where known.
+	Hashes map[string]string `json:"hashes,omitempty"`
+}
+
+// ReplayOfPlan gets a description of the cycle that produced the plan p, such as a plan saved by the analyser.
+func ReplayOfPlan(p *plan.Plan) Replay {
+	r := Replay{Machine: p.Machine.ID, Seed: p.Metadata.Seed, Mutant: p.Mutant()}
+	for _, n := range p.Corpus.Names() {
+		f := p.Corpus[n].Fuzz
+		if f == nil {
+			continue
+		}
+		// Every subject in a cycle shares the same tuned parameters.
+		if r.Params == nil {
+			r.Params = f.Params
+		}
+		if f.Hash != "" {
+			if r.Hashes == nil {
+				r.Hashes = map[string]string{}
+			}
+			r.Hashes[n] = f.Hash
+		}
+	}
+	return r
+}
+
+// Check compares the fuzzed subjects of the plan p, produced by replaying r, against those recorded in r.
+func (r Replay) Check(p *plan.Plan) ReplayReport {
+	var rep ReplayReport
+	names := make([]string, 0, len(r.Hashes))
+	for n := range r.Hashes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		s, ok := p.Corpus[n]
+		switch {
+		case !ok || s.Fuzz == nil:
+			rep.Missing = append(rep.Missing, n)
+		case s.Fuzz.Hash == r.Hashes[n]:
+			rep.Matched = append(rep.Matched, n)
+		default:
+			rep.Mismatched = append(rep.Mismatched, n)
+		}
+	}
+	return rep
+}
+
+// ReplayReport is the result of checking a replayed cycle against the original.
+type ReplayReport struct {
+	// Matched lists the fuzzed subjects whose replayed files hash identically to the originals.
+	Matched []string `json:"matched,omitempty"`
+	// Mismatched lists the fuzzed subjects whose replayed files differ from the originals.
+	Mismatched []string `json:"mismatched,omitempty"`
+	// Missing lists the fuzzed subjects that the replay didn't produce at all.
+	Missing []string `json:"missing,omitempty"`
+}
+
+// Unverified gets whether the report checked no fuzzed subjects at all, for instance because the original cycle's
+// fuzzer output hashes weren't recorded.
+//
+// An unverified report is vacuously OK, but says nothing about whether the replay was faithful.
+func (r ReplayReport) Unverified() bool {
+	return len(r.Matched) == 0 && len(r.Mismatched) == 0 && len(r.Missing) == 0
+}
+
+// OK gets whether the replay reproduced every fuzzed subject exactly.
+func (r ReplayReport) OK() bool {
+	return len(r.Mismatched) == 0 && len(r.Missing) == 0
+}
+
+// String summarises the report.
+func (r ReplayReport) String() string {
+	if r.Unverified() {
+		return "unverified (no fuzzer output hashes to check against)"
+	}
+	return fmt.Sprintf("%d matched, %d mismatched, %d missing", len(r.Matched), len(r.Mismatched), len(r.Missing))
+}
+
+// Replay plans the director's inputs as usual, then replays the cycle described by r on its machine, returning the
+// plan that the replayed cycle produced.
+func (d *Director) Replay(ctx context.Context, r Replay) (*plan.Plan, error) {
+	i, err := d.instance(r.Machine)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.prepare(ctx); err != nil {
+		return nil, err
+	}
+	pn, err := d.plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	i.Machine.InitialPlan = pn[r.Machine]
+	return i.Replay(ctx, r)
+}
+
+// instance gets the instance for the machine with ID mid.
+func (d *Director) instance(mid id.ID) (*Instance, error) {
+	for j := range d.instances {
+		if d.instances[j].Machine.ID.Equal(mid) {
+			return &d.instances[j], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNoReplayMachine, mid)
+}
+
+// Replay runs a single cycle of this instance exactly as described by r, returning the plan it produced.
+//
+// Unlike Run, Replay doesn't run cycle hooks or mutation automation; the cycle sees the initial plan as-is, with r's
+// mutant selected.
+func (i *Instance) Replay(ctx context.Context, r Replay) (*plan.Plan, error) {
+	i.replay = &r
+	p, err := i.replayInner(ctx, r)
+	cerr := i.cleanUp()
+	OnInstance(InstanceClosedMessage(), i.Observers...)
+	return p, errhelp.FirstError(err, cerr)
+}
+
+func (i *Instance) replayInner(ctx context.Context, r Replay) (*plan.Plan, error) {
+	if err := i.prepareReplay(r); err != nil {
+		return nil, err
+	}
+
+	c := i.makeCycleInstance()
+	OnCycle(CycleStartMessage(c.cycle), i.Observers...)
+	if err := c.run(ctx); err != nil {
+		OnCycle(CycleErrorMessage(c.cycle, err), i.Observers...)
+		return nil, err
+	}
+	OnCycle(CycleFinishMessage(c.cycle), i.Observers...)
+	return c.p, nil
+}
+
+func (i *Instance) prepareReplay(r Replay) error {
+	var err error
+	if err = i.check(); err != nil {
+		return err
+	}
+	if err = i.Machine.Pathset.Scratch.Prepare(); err != nil {
+		return err
+	}
+	i.Machine.cycle = r.Iter
+	i.Machine.InitialPlan.SetMutant(r.Mutant)
+	i.Machine.stages, err = i.makeStages()
+	return err
+}
+
+// seed gets the seed this instance's perturber should use.
+func (i *Instance) seed() int64 {
+	if i.replay == nil {
+		return plan.UseDateSeed
+	}
+	return i.replay.Seed
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package director_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c4-project/c4t/internal/director"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/subject"
+)
+
+// mockFuzzedPlan makes a mock plan in which subject foo was fuzzed to a file with the given hash.
+func mockFuzzedPlan(hash string) *plan.Plan {
+	p := plan.Mock()
+	s := p.Corpus["foo"]
+	s.Fuzz = &subject.Fuzz{Params: map[string]string{"int.action_cap_upper": "42"}, Hash: hash}
+	p.Corpus["foo"] = s
+	return p
+}
+
+// ExampleReplayOfPlan is a runnable example for ReplayOfPlan.
+func ExampleReplayOfPlan() {
+	r := director.ReplayOfPlan(mockFuzzedPlan("cafe"))
+	fmt.Println(r.Machine, r.Seed, r.Params, r.Hashes)
+
+	// Output:
+	// localhost 8675309 map[int.action_cap_upper:42] map[foo:cafe]
+}
+
+// TestReplay_Check tests Replay.Check on a selection of replayed plans.
+func TestReplay_Check(t *testing.T) {
+	t.Parallel()
+
+	r := director.Replay{Machine: id.FromString("localhost"), Hashes: map[string]string{"foo": "cafe"}}
+
+	cases := map[string]struct {
+		p    *plan.Plan
+		want director.ReplayReport
+	}{
+		"matched":    {p: mockFuzzedPlan("cafe"), want: director.ReplayReport{Matched: []string{"foo"}}},
+		"mismatched": {p: mockFuzzedPlan("beef"), want: director.ReplayReport{Mismatched: []string{"foo"}}},
+		"unfuzzed":   {p: plan.Mock(), want: director.ReplayReport{Missing: []string{"foo"}}},
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := r.Check(c.p)
+			assert.Equal(t, c.want, got)
+			assert.Equal(t, len(c.want.Matched) == 1, got.OK())
+		})
+	}
+}
+
+// TestReplay_Check_unverified tests that checking a replay with no recorded hashes gives an unverified report.
+func TestReplay_Check_unverified(t *testing.T) {
+	t.Parallel()
+
+	rep := director.Replay{Machine: id.FromString("localhost"), Seed: 8675309}.Check(mockFuzzedPlan("cafe"))
+	assert.True(t, rep.Unverified(), "report should be unverified")
+	assert.True(t, rep.OK(), "unverified reports are vacuously OK")
+	assert.Contains(t, rep.String(), "unverified")
+
+	rep = director.Replay{Hashes: map[string]string{"foo": "cafe"}}.Check(mockFuzzedPlan("cafe"))
+	assert.False(t, rep.Unverified(), "report should be verified")
+}
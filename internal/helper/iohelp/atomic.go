@@ -0,0 +1,64 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package iohelp
+
+import (
+	"encoding/json"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+)
+
+// WriteFileAtomic replaces the file at path with whatever write writes.
+//
+// It writes to a temporary file in the same directory, then renames that file over path, so that readers (and
+// crashes) never see a partially written file; if anything fails, path is left as it was.
+// The new file keeps the permissions of any file it replaces.
+func WriteFileAtomic(path string, write func(io.Writer) error) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+
+	werr := write(f)
+	if werr == nil {
+		werr = f.Sync()
+	}
+	if werr == nil {
+		werr = f.Chmod(replacedMode(path))
+	}
+	cerr := f.Close()
+	if err := errhelp.FirstError(werr, cerr); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// replacedMode gets the permissions of the file at path, or the usual permissions for new files if there isn't one.
+func replacedMode(path string) fs.FileMode {
+	if fi, err := os.Stat(path); err == nil {
+		return fi.Mode().Perm()
+	}
+	return 0o644
+}
+
+// WriteJSONFileAtomic replaces the file at path with v, encoded as tab-indented JSON, as with WriteFileAtomic.
+func WriteJSONFileAtomic(path string, v any) error {
+	return WriteFileAtomic(path, func(w io.Writer) error {
+		e := json.NewEncoder(w)
+		e.SetIndent("", "\t")
+		return e.Encode(v)
+	})
+}
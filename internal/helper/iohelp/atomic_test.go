@@ -0,0 +1,66 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package iohelp_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/helper/iohelp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWriteJSONFileAtomic tests that WriteJSONFileAtomic creates and replaces files, leaving nothing else behind.
+func TestWriteJSONFileAtomic(t *testing.T) {
+	t.Parallel()
+	td := t.TempDir()
+	path := filepath.Join(td, "db.json")
+
+	require.NoError(t, iohelp.WriteJSONFileAtomic(path, map[string]int{"foo": 1}), "creating file")
+	require.NoError(t, os.Chmod(path, 0o600), "changing permissions")
+	require.NoError(t, iohelp.WriteJSONFileAtomic(path, map[string]int{"bar": 2}), "replacing file")
+
+	got, err := os.ReadFile(path)
+	require.NoError(t, err, "reading file")
+	assert.Equal(t, "{\n\t\"bar\": 2\n}\n", string(got))
+
+	fi, err := os.Stat(path)
+	require.NoError(t, err, "statting file")
+	assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm(), "permissions should survive replacement")
+
+	es, err := os.ReadDir(td)
+	require.NoError(t, err, "reading directory")
+	assert.Len(t, es, 1, "temporary files left behind")
+}
+
+// TestWriteFileAtomic_fail tests that a failed WriteFileAtomic leaves the original file untouched.
+func TestWriteFileAtomic_fail(t *testing.T) {
+	t.Parallel()
+	td := t.TempDir()
+	path := filepath.Join(td, "db.json")
+	require.NoError(t, os.WriteFile(path, []byte("original"), 0o600), "writing original file")
+
+	werr := errors.New("oops")
+	err := iohelp.WriteFileAtomic(path, func(w io.Writer) error {
+		if _, err := io.WriteString(w, "partial"); err != nil {
+			return err
+		}
+		return werr
+	})
+	assert.ErrorIs(t, err, werr)
+
+	got, err := os.ReadFile(path)
+	require.NoError(t, err, "reading file")
+	assert.Equal(t, "original", string(got))
+
+	es, err := os.ReadDir(td)
+	require.NoError(t, err, "reading directory")
+	assert.Len(t, es, 1, "temporary files left behind")
+}
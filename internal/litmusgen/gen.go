@@ -0,0 +1,207 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package litmusgen generates C11 litmus tests from classic test shapes, without needing c4f or other external tools.
+//
+// Alongside each test, the generator records the statistics that c4f would otherwise have to work out when planning
+// the test.
+package litmusgen
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/litmus"
+)
+
+// Config configures the generator.
+type Config struct {
+	// Shapes contains the shapes to generate; if empty, the generator uses every shape in Shapes.
+	Shapes []Shape
+	// Types contains the atomic types to generate; if empty, the generator uses atomic_int only.
+	Types []Type
+	// Strengths contains the access strengths to combine; if empty, the generator uses AllStrengths.
+	Strengths []Strength
+	// Uniform, if true, makes every access in each test have the same strength, rather than enumerating every
+	// combination of strengths.
+	Uniform bool
+}
+
+// Generate enumerates every test described by this config.
+func (c Config) Generate() []Test {
+	shapes := c.Shapes
+	if len(shapes) == 0 {
+		shapes = Shapes
+	}
+	types := c.Types
+	if len(types) == 0 {
+		types = Types[:1]
+	}
+	strs := c.Strengths
+	if len(strs) == 0 {
+		strs = AllStrengths
+	}
+
+	var ts []Test
+	for _, s := range shapes {
+		for _, ty := range types {
+			for _, ss := range c.strengthCombos(s.NumAccesses(), strs) {
+				ts = append(ts, Test{Shape: s, Type: ty, Strengths: ss})
+			}
+		}
+	}
+	return ts
+}
+
+// strengthCombos enumerates the assignments of strengths in strs to n accesses.
+func (c Config) strengthCombos(n int, strs []Strength) [][]Strength {
+	if c.Uniform {
+		combos := make([][]Strength, len(strs))
+		for i, s := range strs {
+			combos[i] = make([]Strength, n)
+			for j := range combos[i] {
+				combos[i][j] = s
+			}
+		}
+		return combos
+	}
+
+	combos := [][]Strength{{}}
+	for i := 0; i < n; i++ {
+		next := make([][]Strength, 0, len(combos)*len(strs))
+		for _, prefix := range combos {
+			for _, s := range strs {
+				next = append(next, append(append(make([]Strength, 0, n), prefix...), s))
+			}
+		}
+		combos = next
+	}
+	return combos
+}
+
+// Test is a single generated litmus test.
+type Test struct {
+	// Shape is the shape of the test.
+	Shape Shape
+	// Type is the atomic type of the test's shared variables.
+	Type Type
+	// Strengths contains the strength of each access in the shape, in thread order and then program order.
+	Strengths []Strength
+}
+
+// Name gets the name of this test, which is also the base of its filename.
+func (t Test) Name() string {
+	parts := []string{t.Shape.Ident(), t.Type.Name}
+	i := 0
+	for _, th := range t.Shape.Threads {
+		for _, a := range th {
+			parts = append(parts, t.Strengths[i].Suffix(a))
+			i++
+		}
+	}
+	return strings.Join(parts, "_")
+}
+
+// WriteTo writes this test to w in C litmus format.
+func (t Test) WriteTo(w io.Writer) (int64, error) {
+	var sb strings.Builder
+	vars := t.Shape.Vars()
+
+	fmt.Fprintf(&sb, "C %s\n\n{", t.Name())
+	for _, v := range vars {
+		fmt.Fprintf(&sb, " %s = 0;", v)
+	}
+	sb.WriteString(" }\n")
+
+	params := make([]string, len(vars))
+	for i, v := range vars {
+		params[i] = fmt.Sprintf("%s *%s", t.Type.Atomic, v)
+	}
+
+	i := 0
+	for tid, th := range t.Shape.Threads {
+		fmt.Fprintf(&sb, "\nvoid\nP%d(%s)\n{\n", tid, strings.Join(params, ", "))
+		for _, a := range th {
+			if !a.Store {
+				fmt.Fprintf(&sb, "    %s %s = 0;\n", t.Type.Reg, a.Reg)
+			}
+		}
+		for _, a := range th {
+			t.writeAccess(&sb, a, t.Strengths[i])
+			i++
+		}
+		sb.WriteString("}\n")
+	}
+
+	fmt.Fprintf(&sb, "\nexists\n(%s)\n", t.Shape.Exists)
+
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
+
+func (t Test) writeAccess(sb *strings.Builder, a Access, s Strength) {
+	if a.Store {
+		fmt.Fprintf(sb, "    atomic_store_explicit(%s, %d, %s);\n", a.Var, a.Value, s.Order(a))
+		return
+	}
+	fmt.Fprintf(sb, "    %s = atomic_load_explicit(%s, %s);\n", a.Reg, a.Var, s.Order(a))
+}
+
+// Stats gets the statistics set that `c4f-c dump-stats` would report for this test.
+func (t Test) Stats() *litmus.Statset {
+	s := litmus.Statset{Threads: len(t.Shape.Threads)}
+	i := 0
+	for _, th := range t.Shape.Threads {
+		for _, a := range th {
+			// Stores are statements in their own right, but loads are expressions inside register assignments.
+			as, ty := &s.AtomicExpressions, "load"
+			if a.Store {
+				as, ty = &s.AtomicStatements, "store"
+			}
+			as.AddType(id.FromString(ty), 1)
+			as.AddMemOrder(id.FromString(t.Strengths[i].Order(a)), 1)
+			i++
+		}
+	}
+	return &s
+}
+
+// WriteFile writes this test into the directory dir, returning the path of the new file.
+//
+// It also records the test's statistics alongside it (see litmus.StatsFile), so that planning the test doesn't
+// need c4f.
+func (t Test) WriteFile(dir string) (string, error) {
+	path := filepath.Join(dir, t.Name()+".litmus")
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	_, werr := t.WriteTo(f)
+	cerr := f.Close()
+	if err := errhelp.FirstError(werr, cerr); err != nil {
+		return path, err
+	}
+	return path, t.Stats().WriteFile(litmus.StatsFile(path))
+}
+
+// WriteAll writes each test in ts into the directory dir, creating it if needed, and returns the paths of the files.
+func WriteAll(dir string, ts []Test) ([]string, error) {
+	if err := os.MkdirAll(dir, 0744); err != nil {
+		return nil, err
+	}
+	paths := make([]string, len(ts))
+	for i, t := range ts {
+		var err error
+		if paths[i], err = t.WriteFile(dir); err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
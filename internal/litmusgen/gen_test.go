@@ -0,0 +1,149 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package litmusgen_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/litmusgen"
+	"github.com/c4-project/c4t/internal/model/litmus"
+)
+
+// ExampleTest_WriteTo is a runnable example for Test.WriteTo.
+func ExampleTest_WriteTo() {
+	mp, _ := litmusgen.LookupShape("mp")
+	t := litmusgen.Test{
+		Shape:     mp,
+		Type:      litmusgen.Types[0],
+		Strengths: []litmusgen.Strength{litmusgen.Relaxed, litmusgen.AcqRel, litmusgen.AcqRel, litmusgen.Relaxed},
+	}
+	_, _ = t.WriteTo(os.Stdout)
+
+	// Output:
+	// C MP_int_rlx_rel_acq_rlx
+	//
+	// { x = 0; y = 0; }
+	//
+	// void
+	// P0(atomic_int *x, atomic_int *y)
+	// {
+	//     atomic_store_explicit(x, 1, memory_order_relaxed);
+	//     atomic_store_explicit(y, 1, memory_order_release);
+	// }
+	//
+	// void
+	// P1(atomic_int *x, atomic_int *y)
+	// {
+	//     int r0 = 0;
+	//     int r1 = 0;
+	//     r0 = atomic_load_explicit(y, memory_order_acquire);
+	//     r1 = atomic_load_explicit(x, memory_order_relaxed);
+	// }
+	//
+	// exists
+	// (1:r0 == 1 /\ 1:r1 == 0)
+}
+
+// TestConfig_Generate tests the number of tests the generator enumerates for various configurations.
+func TestConfig_Generate(t *testing.T) {
+	t.Parallel()
+
+	mp, err := litmusgen.LookupShape("MP")
+	require.NoError(t, err)
+	iriw, err := litmusgen.LookupShape("IRIW")
+	require.NoError(t, err)
+	long, err := litmusgen.LookupType("long")
+	require.NoError(t, err)
+
+	cases := map[string]struct {
+		cfg  litmusgen.Config
+		want int
+	}{
+		"mp-all":       {cfg: litmusgen.Config{Shapes: []litmusgen.Shape{mp}}, want: 81},
+		"mp-uniform":   {cfg: litmusgen.Config{Shapes: []litmusgen.Shape{mp}, Uniform: true}, want: 3},
+		"mp-two-types": {cfg: litmusgen.Config{Shapes: []litmusgen.Shape{mp}, Types: []litmusgen.Type{litmusgen.Types[0], long}}, want: 162},
+		"iriw-sc-rlx":  {cfg: litmusgen.Config{Shapes: []litmusgen.Shape{iriw}, Strengths: []litmusgen.Strength{litmusgen.Relaxed, litmusgen.SeqCst}}, want: 64},
+		"all-uniform":  {cfg: litmusgen.Config{Uniform: true}, want: 3 * len(litmusgen.Shapes)},
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ts := c.cfg.Generate()
+			assert.Len(t, ts, c.want)
+
+			names := make(map[string]bool, len(ts))
+			for _, tt := range ts {
+				assert.False(t, names[tt.Name()], "duplicate test name %s", tt.Name())
+				names[tt.Name()] = true
+			}
+		})
+	}
+}
+
+// TestWriteAll tests writing generated tests to a directory.
+func TestWriteAll(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "gen")
+	ts := litmusgen.Config{Uniform: true, Strengths: []litmusgen.Strength{litmusgen.SeqCst}}.Generate()
+	paths, err := litmusgen.WriteAll(dir, ts)
+	require.NoError(t, err, "writing tests")
+	require.Len(t, paths, len(litmusgen.Shapes))
+
+	for i, p := range paths {
+		bs, err := os.ReadFile(p)
+		require.NoError(t, err, "reading test")
+		assert.True(t, strings.HasPrefix(string(bs), "C "+ts[i].Name()+"\n"), "test %s should start with its header", p)
+		assert.NotContains(t, string(bs), "memory_order_relaxed", "test %s should be all seq_cst", p)
+
+		s, err := litmus.ReadStatsFile(litmus.StatsFile(p))
+		require.NoError(t, err, "reading stats for test %s", p)
+		assert.Equal(t, ts[i].Stats(), s, "test %s should record its stats", p)
+		assert.Equal(t, litmus.MemOrderClassSeqCst, s.MemOrderMix(), "test %s stats should be all seq_cst", p)
+	}
+}
+
+// ExampleTest_Stats is a runnable example for Test.Stats.
+func ExampleTest_Stats() {
+	mp, _ := litmusgen.LookupShape("mp")
+	t := litmusgen.Test{
+		Shape:     mp,
+		Type:      litmusgen.Types[0],
+		Strengths: []litmusgen.Strength{litmusgen.Relaxed, litmusgen.AcqRel, litmusgen.AcqRel, litmusgen.Relaxed},
+	}
+	s := t.Stats()
+	for _, f := range s.Features() {
+		fmt.Println(f)
+	}
+	fmt.Println(s.MemOrderMix())
+
+	// Output:
+	// expr-mo:memory_order_acquire
+	// expr-mo:memory_order_relaxed
+	// expr-type:load
+	// stmt-mo:memory_order_relaxed
+	// stmt-mo:memory_order_release
+	// stmt-type:store
+	// threads:2
+	// ra+rlx
+}
+
+// TestLookupShape_unknown tests that looking up an unknown shape fails properly.
+func TestLookupShape_unknown(t *testing.T) {
+	t.Parallel()
+
+	_, err := litmusgen.LookupShape("CoRR2")
+	assert.ErrorIs(t, err, litmusgen.ErrUnknownShape)
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package litmusgen
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrBadStrength occurs when we try to parse an unknown access strength.
+	ErrBadStrength = errors.New("unknown access strength")
+	// ErrBadType occurs when we try to parse an unknown atomic type.
+	ErrBadType = errors.New("unknown atomic type")
+)
+
+// Strength is the strength of a single access, which maps onto a C11 memory order depending on the kind of access.
+type Strength uint8
+
+const (
+	// Relaxed accesses use memory_order_relaxed.
+	Relaxed Strength = iota
+	// AcqRel accesses use memory_order_release if they are stores, and memory_order_acquire if they are loads.
+	AcqRel
+	// SeqCst accesses use memory_order_seq_cst.
+	SeqCst
+	// NumStrengths is the number of strengths.
+	NumStrengths
+)
+
+var strengthStrings = [NumStrengths]string{"rlx", "ra", "sc"}
+
+// StrengthOfString gets the strength whose short name is str.
+func StrengthOfString(str string) (Strength, error) {
+	for i, s := range strengthStrings {
+		if strings.EqualFold(s, str) {
+			return Strength(i), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", ErrBadStrength, str)
+}
+
+// String gets the short name of this strength.
+func (s Strength) String() string {
+	if NumStrengths <= s {
+		return "?"
+	}
+	return strengthStrings[s]
+}
+
+// Order gets the C11 memory order that this strength gives to the access a.
+func (s Strength) Order(a Access) string {
+	switch s {
+	case AcqRel:
+		if a.Store {
+			return "memory_order_release"
+		}
+		return "memory_order_acquire"
+	case SeqCst:
+		return "memory_order_seq_cst"
+	default:
+		return "memory_order_relaxed"
+	}
+}
+
+// Suffix gets a short name for the memory order that this strength gives to the access a, for use in test names.
+func (s Strength) Suffix(a Access) string {
+	switch s {
+	case AcqRel:
+		if a.Store {
+			return "rel"
+		}
+		return "acq"
+	default:
+		return s.String()
+	}
+}
+
+// AllStrengths lists every strength.
+var AllStrengths = []Strength{Relaxed, AcqRel, SeqCst}
+
+// Type is an atomic type that a generated test can use for its shared variables.
+type Type struct {
+	// Name is the short name of the type, used in test names.
+	Name string
+	// Atomic is the C11 atomic type of shared variables.
+	Atomic string
+	// Reg is the C type of registers.
+	Reg string
+}
+
+// Types lists every atomic type the generator supports.
+var Types = []Type{
+	{Name: "int", Atomic: "atomic_int", Reg: "int"},
+	{Name: "uint", Atomic: "atomic_uint", Reg: "unsigned int"},
+	{Name: "long", Atomic: "atomic_long", Reg: "long"},
+	{Name: "llong", Atomic: "atomic_llong", Reg: "long long"},
+}
+
+// LookupType gets the supported atomic type with the short name name.
+func LookupType(name string) (Type, error) {
+	for _, t := range Types {
+		if strings.EqualFold(t.Name, name) {
+			return t, nil
+		}
+	}
+	return Type{}, fmt.Errorf("%w: %q", ErrBadType, name)
+}
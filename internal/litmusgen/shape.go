@@ -0,0 +1,168 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package litmusgen
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrUnknownShape occurs when we ask for a shape that the generator doesn't know.
+var ErrUnknownShape = errors.New("unknown litmus shape")
+
+// Access is a single atomic access in a shape.
+type Access struct {
+	// Store is true if this access is a store, and false if it is a load.
+	Store bool
+	// Var is the shared variable being accessed.
+	Var string
+	// Value is the value stored, if this is a store.
+	Value int
+	// Reg is the register into which the value is loaded, if this is a load.
+	Reg string
+}
+
+// store constructs a store of value v to variable x.
+func store(x string, v int) Access {
+	return Access{Store: true, Var: x, Value: v}
+}
+
+// load constructs a load of variable x into register r.
+func load(r, x string) Access {
+	return Access{Var: x, Reg: r}
+}
+
+// Shape is a classic litmus test shape.
+type Shape struct {
+	// Name is the conventional name of the shape, such as MP or 2+2W.
+	Name string
+	// Threads contains the accesses of each thread, in program order.
+	Threads [][]Access
+	// Exists is the postcondition that witnesses the shape's weak behaviour, in C litmus syntax.
+	Exists string
+}
+
+// Ident gets a version of the shape's name that is safe to use in test names and filenames.
+func (s Shape) Ident() string {
+	return strings.ReplaceAll(s.Name, "+", "_")
+}
+
+// NumAccesses gets the total number of accesses across all threads of the shape.
+func (s Shape) NumAccesses() int {
+	n := 0
+	for _, t := range s.Threads {
+		n += len(t)
+	}
+	return n
+}
+
+// Vars gets the shared variables of the shape, in ascending order.
+func (s Shape) Vars() []string {
+	seen := map[string]bool{}
+	var vs []string
+	for _, t := range s.Threads {
+		for _, a := range t {
+			if !seen[a.Var] {
+				seen[a.Var] = true
+				vs = append(vs, a.Var)
+			}
+		}
+	}
+	sort.Strings(vs)
+	return vs
+}
+
+// Shapes contains every shape the generator knows, in the order in which it generates them.
+var Shapes = []Shape{
+	{
+		Name: "MP",
+		Threads: [][]Access{
+			{store("x", 1), store("y", 1)},
+			{load("r0", "y"), load("r1", "x")},
+		},
+		Exists: "1:r0 == 1 /\\ 1:r1 == 0",
+	},
+	{
+		Name: "SB",
+		Threads: [][]Access{
+			{store("x", 1), load("r0", "y")},
+			{store("y", 1), load("r0", "x")},
+		},
+		Exists: "0:r0 == 0 /\\ 1:r0 == 0",
+	},
+	{
+		Name: "LB",
+		Threads: [][]Access{
+			{load("r0", "x"), store("y", 1)},
+			{load("r0", "y"), store("x", 1)},
+		},
+		Exists: "0:r0 == 1 /\\ 1:r0 == 1",
+	},
+	{
+		Name: "IRIW",
+		Threads: [][]Access{
+			{store("x", 1)},
+			{store("y", 1)},
+			{load("r0", "x"), load("r1", "y")},
+			{load("r0", "y"), load("r1", "x")},
+		},
+		Exists: "2:r0 == 1 /\\ 2:r1 == 0 /\\ 3:r0 == 1 /\\ 3:r1 == 0",
+	},
+	{
+		Name: "2+2W",
+		Threads: [][]Access{
+			{store("x", 1), store("y", 2)},
+			{store("y", 1), store("x", 2)},
+		},
+		Exists: "x == 1 /\\ y == 1",
+	},
+	{
+		Name: "WRC",
+		Threads: [][]Access{
+			{store("x", 1)},
+			{load("r0", "x"), store("y", 1)},
+			{load("r0", "y"), load("r1", "x")},
+		},
+		Exists: "1:r0 == 1 /\\ 2:r0 == 1 /\\ 2:r1 == 0",
+	},
+	{
+		Name: "R",
+		Threads: [][]Access{
+			{store("x", 1), store("y", 1)},
+			{store("y", 2), load("r0", "x")},
+		},
+		Exists: "y == 2 /\\ 1:r0 == 0",
+	},
+	{
+		Name: "S",
+		Threads: [][]Access{
+			{store("x", 2), store("y", 1)},
+			{load("r0", "y"), store("x", 1)},
+		},
+		Exists: "x == 2 /\\ 1:r0 == 1",
+	},
+}
+
+// ShapeNames gets the names of every known shape.
+func ShapeNames() []string {
+	ns := make([]string, len(Shapes))
+	for i, s := range Shapes {
+		ns[i] = s.Name
+	}
+	return ns
+}
+
+// LookupShape gets the known shape with the given name, ignoring case.
+func LookupShape(name string) (Shape, error) {
+	for _, s := range Shapes {
+		if strings.EqualFold(s.Name, name) {
+			return s, nil
+		}
+	}
+	return Shape{}, fmt.Errorf("%w: %q", ErrUnknownShape, name)
+}
@@ -0,0 +1,448 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package litmus
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrBadFingerprintInput occurs when the canonicaliser can't make sense of a C litmus test.
+var ErrBadFingerprintInput = errors.New("can't canonicalise litmus test")
+
+const (
+	// fingerprintLen is the number of hex digits of the canonical form's hash kept in a fingerprint.
+	fingerprintLen = 32
+
+	// maxFingerprintPerms bounds the number of thread orders the canonicaliser tries when threads have the same shape.
+	maxFingerprintPerms = 5040
+)
+
+// PopulateFingerprintFromFile reads this litmus test's file, and sets its fingerprint.
+//
+// Only C litmus tests have fingerprints.  Tests that the canonicaliser can't make sense of are left without a
+// fingerprint, rather than causing an error.
+func (l *Litmus) PopulateFingerprintFromFile() error {
+	if !l.IsC() {
+		return nil
+	}
+	f, err := os.Open(l.Filepath())
+	if err != nil {
+		return err
+	}
+	fp, ferr := Fingerprint(f)
+	cerr := f.Close()
+	if cerr != nil {
+		return cerr
+	}
+	if errors.Is(ferr, ErrBadFingerprintInput) {
+		return nil
+	}
+	l.Fingerprint = fp
+	return ferr
+}
+
+// Fingerprint computes a fingerprint of the C litmus test read from r.
+//
+// The fingerprint is invariant under renaming the test itself, its shared variables, its threads (including
+// reordering them), and its registers, as well as under changes in whitespace and comments.
+func Fingerprint(r io.Reader) (string, error) {
+	cf, err := Canonicalise(r)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(cf))
+	return hex.EncodeToString(sum[:])[:fingerprintLen], nil
+}
+
+// Canonicalise gets a canonical form of the C litmus test read from r.
+// Two tests have the same canonical form if they differ only in the ways listed in Fingerprint.
+func Canonicalise(r io.Reader) (string, error) {
+	br := bufio.NewReader(r)
+	// The header line contains the test name, which we don't want in the canonical form.
+	if _, err := br.ReadString('\n'); err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	rest, err := io.ReadAll(br)
+	if err != nil {
+		return "", err
+	}
+	t, err := parseCanonTest(tokenise(string(rest)))
+	if err != nil {
+		return "", err
+	}
+	return t.canonicalise(), nil
+}
+
+// tokenise splits src into identifier, number, and punctuation tokens, dropping whitespace and C comments.
+func tokenise(src string) []string {
+	var toks []string
+	rs := []rune(src)
+	for i := 0; i < len(rs); {
+		c := rs[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '/' && i+1 < len(rs) && rs[i+1] == '/':
+			for i < len(rs) && rs[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(rs) && rs[i+1] == '*':
+			i += 2
+			for i+1 < len(rs) && !(rs[i] == '*' && rs[i+1] == '/') {
+				i++
+			}
+			i += 2
+		case isIdentRune(c):
+			j := i
+			for j < len(rs) && isIdentRune(rs[j]) {
+				j++
+			}
+			toks = append(toks, string(rs[i:j]))
+			i = j
+		default:
+			toks = append(toks, string(c))
+			i++
+		}
+	}
+	return toks
+}
+
+func isIdentRune(c rune) bool {
+	return c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c)
+}
+
+func isIdent(tok string) bool {
+	return tok != "" && !unicode.IsDigit([]rune(tok)[0]) && isIdentRune([]rune(tok)[0])
+}
+
+// canonKeywords contains identifiers that the canonicaliser never renames.
+var canonKeywords = map[string]bool{
+	"void": true, "int": true, "long": true, "short": true, "char": true, "unsigned": true, "signed": true,
+	"bool": true, "_Bool": true, "const": true, "volatile": true, "static": true, "struct": true,
+	"if": true, "else": true, "while": true, "for": true, "do": true, "goto": true, "return": true,
+	"break": true, "continue": true, "true": true, "false": true, "NULL": true,
+	"exists": true, "forall": true, "locations": true, "filter": true, "not": true,
+}
+
+func isFixedIdent(tok string) bool {
+	return canonKeywords[tok] ||
+		strings.HasPrefix(tok, "atomic_") ||
+		strings.HasPrefix(tok, "memory_order_") ||
+		strings.HasSuffix(tok, "_t")
+}
+
+// canonThread is a thread of a test being canonicalised.
+type canonThread struct {
+	// num is the thread number by which postconditions refer to the thread.
+	num int
+	// params maps each global parameter name to the tokens of its type.
+	params map[string][]string
+	// body contains the tokens of the thread's body.
+	body []string
+	// regs maps each local name to its canonical name, in order of first occurrence in the body.
+	regs map[string]string
+	// shape is the canonical form of the thread with globals renamed locally; threads with the same shape are
+	// interchangeable for ordering purposes.
+	shape string
+}
+
+// canonTest is a test being canonicalised.
+type canonTest struct {
+	init    []string
+	threads []*canonThread
+	post    []string
+}
+
+func parseCanonTest(toks []string) (*canonTest, error) {
+	var (
+		t   canonTest
+		err error
+	)
+	i := 0
+	if i < len(toks) && toks[i] == "{" {
+		var end int
+		if end, err = matchBrace(toks, i, "{", "}"); err != nil {
+			return nil, err
+		}
+		t.init, i = toks[i+1:end], end+1
+	}
+	for i < len(toks) {
+		j := i
+		if toks[j] == "void" {
+			j++
+		}
+		if j+1 >= len(toks) || !isIdent(toks[j]) || isFixedIdent(toks[j]) || toks[j+1] != "(" {
+			break
+		}
+		var th *canonThread
+		if th, i, err = parseCanonThread(toks, j, len(t.threads)); err != nil {
+			return nil, err
+		}
+		t.threads = append(t.threads, th)
+	}
+	if len(t.threads) == 0 {
+		return nil, fmt.Errorf("%w: no threads", ErrBadFingerprintInput)
+	}
+	t.post = toks[i:]
+	return &t, nil
+}
+
+// parseCanonThread parses the thread whose name is at toks[i], which is the index-th thread in the test.
+func parseCanonThread(toks []string, i, index int) (*canonThread, int, error) {
+	th := canonThread{num: index, params: map[string][]string{}, regs: map[string]string{}}
+	if n, ok := threadNumber(toks[i]); ok {
+		th.num = n
+	}
+	pend, err := matchBrace(toks, i+1, "(", ")")
+	if err != nil {
+		return nil, 0, err
+	}
+	th.parseParams(toks[i+2 : pend])
+
+	if pend+1 >= len(toks) || toks[pend+1] != "{" {
+		return nil, 0, fmt.Errorf("%w: thread %s has no body", ErrBadFingerprintInput, toks[i])
+	}
+	bend, err := matchBrace(toks, pend+1, "{", "}")
+	if err != nil {
+		return nil, 0, err
+	}
+	th.body = toks[pend+2 : bend]
+	th.nameRegs()
+	th.shape = th.render(th.localGlobals(), "T")
+	return &th, bend + 1, nil
+}
+
+// threadNumber gets the number n of a thread named Pn.
+func threadNumber(name string) (int, bool) {
+	if !strings.HasPrefix(name, "P") {
+		return 0, false
+	}
+	n, err := strconv.Atoi(name[1:])
+	return n, err == nil
+}
+
+// parseParams parses a comma-separated parameter list into parameter names and types.
+func (th *canonThread) parseParams(toks []string) {
+	var cur []string
+	flush := func() {
+		for k := len(cur) - 1; 0 <= k; k-- {
+			if isIdent(cur[k]) && !isFixedIdent(cur[k]) {
+				th.params[cur[k]] = append(append([]string(nil), cur[:k]...), cur[k+1:]...)
+				break
+			}
+		}
+		cur = nil
+	}
+	for _, tok := range toks {
+		if tok == "," {
+			flush()
+			continue
+		}
+		cur = append(cur, tok)
+	}
+	flush()
+}
+
+// isLocal gets whether the body token at index k is a local name, which we rename to a register.
+func (th *canonThread) isLocal(k int) bool {
+	tok := th.body[k]
+	if !isIdent(tok) || isFixedIdent(tok) {
+		return false
+	}
+	if _, ok := th.params[tok]; ok {
+		return false
+	}
+	// Function calls keep their names.
+	return k+1 >= len(th.body) || th.body[k+1] != "("
+}
+
+func (th *canonThread) nameRegs() {
+	for k, tok := range th.body {
+		if _, ok := th.regs[tok]; !ok && th.isLocal(k) {
+			th.regs[tok] = fmt.Sprintf("r%d", len(th.regs))
+		}
+	}
+}
+
+// localGlobals names this thread's globals by first occurrence in the thread alone.
+func (th *canonThread) localGlobals() map[string]string {
+	gs := map[string]string{}
+	th.nameGlobals(gs, "L")
+	return gs
+}
+
+// nameGlobals adds canonical names, with the given prefix, for any of this thread's globals missing from gs.
+func (th *canonThread) nameGlobals(gs map[string]string, prefix string) {
+	for _, tok := range th.body {
+		if _, ok := th.params[tok]; ok {
+			if _, named := gs[tok]; !named {
+				gs[tok] = fmt.Sprintf("%s%d", prefix, len(gs))
+			}
+		}
+	}
+	// Parameters the body never uses still count, in name order so as to be deterministic.
+	ps := make([]string, 0, len(th.params))
+	for p := range th.params {
+		ps = append(ps, p)
+	}
+	sort.Strings(ps)
+	for _, p := range ps {
+		if _, named := gs[p]; !named {
+			gs[p] = fmt.Sprintf("%s%d", prefix, len(gs))
+		}
+	}
+}
+
+// render renders this thread under the global naming gs, calling it name.
+func (th *canonThread) render(gs map[string]string, name string) string {
+	ps := make([]string, 0, len(th.params))
+	for p, ty := range th.params {
+		ps = append(ps, strings.Join(append(append([]string(nil), ty...), gs[p]), " "))
+	}
+	sort.Strings(ps)
+
+	body := make([]string, len(th.body))
+	for k, tok := range th.body {
+		switch {
+		case th.params[tok] != nil:
+			body[k] = gs[tok]
+		case th.isLocal(k):
+			body[k] = th.regs[tok]
+		default:
+			body[k] = tok
+		}
+	}
+	return fmt.Sprintf("%s(%s){%s}", name, strings.Join(ps, ","), strings.Join(body, " "))
+}
+
+// canonicalise gets the lexicographically least rendering of the test over all orderings of its threads that are
+// consistent with sorting them by shape.
+func (t *canonTest) canonicalise() string {
+	sort.SliceStable(t.threads, func(i, j int) bool { return t.threads[i].shape < t.threads[j].shape })
+
+	best := ""
+	first := true
+	n := 0
+	permuteTies(t.threads, 0, func(order []*canonThread) bool {
+		if s := t.render(order); first || s < best {
+			best, first = s, false
+		}
+		n++
+		return n < maxFingerprintPerms
+	})
+	return best
+}
+
+// permuteTies calls f on each permutation of ths, from index i, that only swaps threads with the same shape.
+// It stops early if f returns false, and returns whether it didn't stop.
+func permuteTies(ths []*canonThread, i int, f func([]*canonThread) bool) bool {
+	if i == len(ths) {
+		return f(ths)
+	}
+	for j := i; j < len(ths) && ths[j].shape == ths[i].shape; j++ {
+		ths[i], ths[j] = ths[j], ths[i]
+		ok := permuteTies(ths, i+1, f)
+		ths[i], ths[j] = ths[j], ths[i]
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// render renders the test with its threads in the given order.
+func (t *canonTest) render(order []*canonThread) string {
+	gs := map[string]string{}
+	tnums := make(map[int]int, len(order))
+	for k, th := range order {
+		th.nameGlobals(gs, "g")
+		tnums[th.num] = k
+	}
+
+	var sb strings.Builder
+	sb.WriteString(t.renderInit(gs, order, tnums))
+	for k, th := range order {
+		sb.WriteString(th.render(gs, fmt.Sprintf("T%d", k)))
+	}
+	sb.WriteString(renderCond(t.post, gs, order, tnums))
+	return sb.String()
+}
+
+// renderInit renders the initialiser block, sorting its statements so that their order doesn't matter.
+func (t *canonTest) renderInit(gs map[string]string, order []*canonThread, tnums map[int]int) string {
+	var (
+		stmts []string
+		cur   []string
+	)
+	for _, tok := range t.init {
+		if tok == ";" {
+			stmts = append(stmts, renderCond(cur, gs, order, tnums))
+			cur = nil
+			continue
+		}
+		cur = append(cur, tok)
+	}
+	if len(cur) != 0 {
+		stmts = append(stmts, renderCond(cur, gs, order, tnums))
+	}
+	sort.Strings(stmts)
+	return "{" + strings.Join(stmts, ";") + "}"
+}
+
+// renderCond renders tokens from an initialiser or postcondition, where n:r refers to register r of thread n and
+// any other non-keyword identifier refers to a global.
+func renderCond(toks []string, gs map[string]string, order []*canonThread, tnums map[int]int) string {
+	out := make([]string, 0, len(toks))
+	for k := 0; k < len(toks); k++ {
+		tok := toks[k]
+		if n, err := strconv.Atoi(tok); err == nil && k+2 < len(toks) && toks[k+1] == ":" && isIdent(toks[k+2]) {
+			if tn, ok := tnums[n]; ok {
+				reg := toks[k+2]
+				if r, ok := order[tn].regs[reg]; ok {
+					reg = r
+				}
+				out = append(out, fmt.Sprintf("%d:%s", tn, reg))
+				k += 2
+				continue
+			}
+		}
+		if isIdent(tok) && !isFixedIdent(tok) {
+			if _, ok := gs[tok]; !ok {
+				gs[tok] = fmt.Sprintf("g%d", len(gs))
+			}
+			tok = gs[tok]
+		}
+		out = append(out, tok)
+	}
+	return strings.Join(out, " ")
+}
+
+// matchBrace finds the index of the close bracket matching the open bracket at toks[i].
+func matchBrace(toks []string, i int, open, close string) (int, error) {
+	depth := 0
+	for j := i; j < len(toks); j++ {
+		switch toks[j] {
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return j, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("%w: unbalanced %s", ErrBadFingerprintInput, open)
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package litmus_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/litmus"
+)
+
+// sbLitmus is a store-buffering test used as the base for fingerprint tests.
+const sbLitmus = `C SB
+
+{ x = 0; y = 0; }
+
+void
+P0(atomic_int *x, atomic_int *y)
+{
+    int r0 = 0;
+    atomic_store_explicit(x, 1, memory_order_relaxed);
+    r0 = atomic_load_explicit(y, memory_order_relaxed);
+}
+
+void
+P1(atomic_int *x, atomic_int *y)
+{
+    int r0 = 0;
+    atomic_store_explicit(y, 1, memory_order_relaxed);
+    r0 = atomic_load_explicit(x, memory_order_relaxed);
+}
+
+exists (0:r0 == 0 /\ 1:r0 == 0)
+`
+
+// TestFingerprint tests that Fingerprint is invariant under renaming and whitespace, but not under real changes.
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	base := fingerprint(t, sbLitmus)
+
+	cases := map[string]struct {
+		in   string
+		same bool
+	}{
+		"renamed test": {
+			in:   strings.Replace(sbLitmus, "C SB", "C SB_renamed", 1),
+			same: true,
+		},
+		"whitespace and comments": {
+			in: `C SB
+{x=0;y=0;}
+void P0(atomic_int *x, atomic_int *y) { int r0 = 0; /* store */
+  atomic_store_explicit(x,1,memory_order_relaxed); r0 = atomic_load_explicit(y,memory_order_relaxed); }
+// the other thread
+void P1(atomic_int *x, atomic_int *y) { int r0 = 0; atomic_store_explicit(y,1,memory_order_relaxed);
+  r0 = atomic_load_explicit(x,memory_order_relaxed); }
+exists (0:r0 == 0 /\ 1:r0 == 0)`,
+			same: true,
+		},
+		"renamed variables and registers": {
+			in: strings.NewReplacer("*x", "*foo", "(x", "(foo", "*y", "*bar", "(y", "(bar", "x = 0; y = 0;",
+				"foo = 0; bar = 0;", "r0", "baz", "P0", "T1", "P1", "T2").Replace(sbLitmus),
+			same: true,
+		},
+		"swapped threads and conjuncts": {
+			in: `C SB
+{ y = 0; x = 0; }
+void P0(atomic_int *x, atomic_int *y) { int a = 0; atomic_store_explicit(y, 1, memory_order_relaxed); a = atomic_load_explicit(x, memory_order_relaxed); }
+void P1(atomic_int *x, atomic_int *y) { int b = 0; atomic_store_explicit(x, 1, memory_order_relaxed); b = atomic_load_explicit(y, memory_order_relaxed); }
+exists (1:b == 0 /\ 0:a == 0)`,
+			same: true,
+		},
+		"swapped threads": {
+			in: `C SB
+{ y = 0; x = 0; }
+void P0(atomic_int *x, atomic_int *y) { int a = 0; atomic_store_explicit(y, 1, memory_order_relaxed); a = atomic_load_explicit(x, memory_order_relaxed); }
+void P1(atomic_int *x, atomic_int *y) { int b = 0; atomic_store_explicit(x, 1, memory_order_relaxed); b = atomic_load_explicit(y, memory_order_relaxed); }
+exists (0:a == 0 /\ 1:b == 0)`,
+			same: true,
+		},
+		"different memory order": {
+			in:   strings.Replace(sbLitmus, "memory_order_relaxed", "memory_order_seq_cst", 1),
+			same: false,
+		},
+		"different postcondition": {
+			in:   strings.Replace(sbLitmus, "1:r0 == 0)", "1:r0 == 1)", 1),
+			same: false,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := fingerprint(t, c.in)
+			if c.same {
+				assert.Equal(t, base, got, "fingerprints should match")
+			} else {
+				assert.NotEqual(t, base, got, "fingerprints shouldn't match")
+			}
+		})
+	}
+}
+
+// TestFingerprint_bad tests that Fingerprint rejects input with no threads.
+func TestFingerprint_bad(t *testing.T) {
+	t.Parallel()
+
+	_, err := litmus.Fingerprint(strings.NewReader("C bad\n{ x = 0; }\nexists (x == 0)\n"))
+	assert.ErrorIs(t, err, litmus.ErrBadFingerprintInput)
+}
+
+// TestLitmus_PopulateFingerprintFromFile tests populating fingerprints for C and non-C litmus tests.
+func TestLitmus_PopulateFingerprintFromFile(t *testing.T) {
+	t.Parallel()
+
+	c, err := litmus.New(filepath.ToSlash(filepath.Join("testdata", "c.litmus")),
+		litmus.WithArch(id.ArchC), litmus.ReadFingerprintFromFile())
+	require.NoError(t, err, "fingerprinting C test")
+	assert.Len(t, c.Fingerprint, 32, "C test should have a fingerprint")
+
+	a, err := litmus.New(filepath.ToSlash(filepath.Join("testdata", "aarch64.litmus")),
+		litmus.WithArch(id.ArchAArch64), litmus.ReadFingerprintFromFile())
+	require.NoError(t, err, "fingerprinting AArch64 test")
+	assert.Empty(t, a.Fingerprint, "AArch64 test shouldn't have a fingerprint")
+}
+
+func fingerprint(t *testing.T, in string) string {
+	t.Helper()
+	fp, err := litmus.Fingerprint(strings.NewReader(in))
+	require.NoError(t, err, "fingerprinting")
+	return fp
+}
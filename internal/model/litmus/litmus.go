@@ -31,6 +31,10 @@ type Litmus struct {
 
 	// Stats contains, if available, the statistics set for this litmus test.
 	Stats *Statset `json:"stats,omitempty"`
+
+	// Fingerprint contains, if available, a fingerprint of the test that is invariant under renaming and whitespace.
+	// Tests with the same fingerprint are semantically the same test.
+	Fingerprint string `json:"fingerprint,omitempty"`
 }
 
 // New constructs a litmus record for slashpath path and options os.
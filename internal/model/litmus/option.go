@@ -39,6 +39,22 @@ func ReadArchFromFile() Option {
 	return (*Litmus).PopulateArchFromFile
 }
 
+// ReadFingerprintFromFile is an option that causes the litmus test to populate its fingerprint from its given file.
+//
+// This should come after any options that set the test's architecture.
+func ReadFingerprintFromFile() Option {
+	return (*Litmus).PopulateFingerprintFromFile
+}
+
+// ReadStatsFromFile is an option that causes the litmus test to populate its statistics set from its statistics file.
+// See StatsFile.
+func ReadStatsFromFile() Option {
+	return func(l *Litmus) (err error) {
+		l.Stats, err = ReadStatsFile(StatsFile(l.Filepath()))
+		return err
+	}
+}
+
 // WithArch is an option that forces the litmus test's architecture to be id.
 func WithArch(id id.ID) Option {
 	return func(l *Litmus) error {
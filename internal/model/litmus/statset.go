@@ -7,7 +7,15 @@ package litmus
 
 import (
 	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/helper/iohelp"
 	"github.com/c4-project/c4t/internal/id"
 )
 
@@ -60,3 +68,147 @@ type Statset struct {
 	// AtomicStatements gives information about atomic statements.
 	AtomicStatements AtomicStatset `json:"atomic_statements,omitempty"`
 }
+
+// StatsFileSuffix is the suffix of the files in which litmus tests can record their statistics sets; see StatsFile.
+const StatsFileSuffix = ".stats.json"
+
+// StatsFile gets the filepath at which the litmus test at filepath path records its statistics set, if it does.
+//
+// Tools that generate litmus tests, such as c4t-gen, can record statistics there so that probing the test doesn't
+// need `c4f-c dump-stats`.
+func StatsFile(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + StatsFileSuffix
+}
+
+// WriteFile writes this statistics set, as JSON, to the filepath path.
+func (s *Statset) WriteFile(path string) error {
+	return iohelp.WriteJSONFileAtomic(path, s)
+}
+
+// ReadStatsFile reads a statistics set, as JSON, from the filepath path.
+func ReadStatsFile(path string) (*Statset, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	var s Statset
+	derr := json.NewDecoder(f).Decode(&s)
+	cerr := f.Close()
+	return &s, errhelp.FirstError(derr, cerr)
+}
+
+// Feature name prefixes used by Statset.Features.
+const (
+	// FeatureThreads prefixes the feature recording the number of threads.
+	FeatureThreads = "threads:"
+	// FeatureReturns is the feature recording that a test contains return statements.
+	FeatureReturns = "returns"
+	// FeatureLiteralBools is the feature recording that a test contains Boolean literals.
+	FeatureLiteralBools = "literal-bools"
+	// FeatureStatementType prefixes features recording the types of atomic statements.
+	FeatureStatementType = "stmt-type:"
+	// FeatureStatementMemOrder prefixes features recording the memory orders of atomic statements.
+	FeatureStatementMemOrder = "stmt-mo:"
+	// FeatureExpressionType prefixes features recording the types of atomic expressions.
+	FeatureExpressionType = "expr-type:"
+	// FeatureExpressionMemOrder prefixes features recording the memory orders of atomic expressions.
+	FeatureExpressionMemOrder = "expr-mo:"
+)
+
+// Features summarises this statistics set as a sorted list of the features present in the test.
+//
+// Features record the presence, rather than the frequency, of each statistic; for instance, a test with any
+// sequentially consistent atomic statements has the feature `stmt-mo:` followed by the ID of that memory order.
+func (s *Statset) Features() []string {
+	if s == nil {
+		return nil
+	}
+	var fs []string
+	if 0 < s.Threads {
+		fs = append(fs, FeatureThreads+strconv.Itoa(s.Threads))
+	}
+	if 0 < s.Returns {
+		fs = append(fs, FeatureReturns)
+	}
+	if 0 < s.LiteralBools {
+		fs = append(fs, FeatureLiteralBools)
+	}
+	fs = appendFeatures(fs, FeatureStatementType, s.AtomicStatements.Types)
+	fs = appendFeatures(fs, FeatureStatementMemOrder, s.AtomicStatements.MemOrders)
+	fs = appendFeatures(fs, FeatureExpressionType, s.AtomicExpressions.Types)
+	fs = appendFeatures(fs, FeatureExpressionMemOrder, s.AtomicExpressions.MemOrders)
+	sort.Strings(fs)
+	return fs
+}
+
+func appendFeatures(fs []string, prefix string, counts map[id.ID]int) []string {
+	for k, n := range counts {
+		if 0 < n {
+			fs = append(fs, prefix+k.String())
+		}
+	}
+	return fs
+}
+
+// rmwTypes contains the IDs of atomic types that are read-modify-write operations.
+var rmwTypes = []id.ID{id.FromString("cmpxchg"), id.FromString("fetch"), id.FromString("xchg")}
+
+// HasRMW gets whether the test contains any atomic read-modify-write expressions or statements.
+func (s *Statset) HasRMW() bool {
+	if s == nil {
+		return false
+	}
+	for _, t := range rmwTypes {
+		if 0 < s.AtomicExpressions.Types[t] || 0 < s.AtomicStatements.Types[t] {
+			return true
+		}
+	}
+	return false
+}
+
+// Memory-order classes used by Statset.MemOrderMix.
+const (
+	// MemOrderClassRelaxed covers memory_order_relaxed.
+	MemOrderClassRelaxed = "rlx"
+	// MemOrderClassAcqRel covers the acquire, release, consume, and acquire-release memory orders.
+	MemOrderClassAcqRel = "ra"
+	// MemOrderClassSeqCst covers memory_order_seq_cst.
+	MemOrderClassSeqCst = "sc"
+	// MemOrderMixNone is the mix of a test that uses no memory orders.
+	MemOrderMixNone = "none"
+)
+
+// memOrderClasses maps each memory order ID to its class.
+var memOrderClasses = map[string]string{
+	"memory_order_relaxed": MemOrderClassRelaxed,
+	"memory_order_consume": MemOrderClassAcqRel,
+	"memory_order_acquire": MemOrderClassAcqRel,
+	"memory_order_release": MemOrderClassAcqRel,
+	"memory_order_acq_rel": MemOrderClassAcqRel,
+	"memory_order_seq_cst": MemOrderClassSeqCst,
+}
+
+// MemOrderMix summarises the memory orders the test uses as a sorted, plus-separated list of classes, such as
+// "ra+rlx"; it is MemOrderMixNone if the test uses no memory orders.
+func (s *Statset) MemOrderMix() string {
+	if s == nil {
+		return MemOrderMixNone
+	}
+	seen := map[string]bool{}
+	for _, mos := range []map[id.ID]int{s.AtomicExpressions.MemOrders, s.AtomicStatements.MemOrders} {
+		for mo, n := range mos {
+			if c, ok := memOrderClasses[mo.String()]; ok && 0 < n {
+				seen[c] = true
+			}
+		}
+	}
+	if len(seen) == 0 {
+		return MemOrderMixNone
+	}
+	cs := make([]string, 0, len(seen))
+	for c := range seen {
+		cs = append(cs, c)
+	}
+	sort.Strings(cs)
+	return strings.Join(cs, "+")
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package litmus_test
+
+import (
+	"fmt"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/litmus"
+)
+
+// ExampleStatset_Features is a runnable example for Statset.Features.
+func ExampleStatset_Features() {
+	s := litmus.Statset{Threads: 2, Returns: 1}
+	s.AtomicStatements.AddType(id.FromString("store"), 2)
+	s.AtomicStatements.AddMemOrder(id.FromString("memory_order_relaxed"), 3)
+	s.AtomicStatements.AddMemOrder(id.FromString("memory_order_seq_cst"), 0)
+	s.AtomicExpressions.AddMemOrder(id.FromString("memory_order_acquire"), 1)
+
+	for _, f := range s.Features() {
+		fmt.Println(f)
+	}
+
+	// Output:
+	// expr-mo:memory_order_acquire
+	// returns
+	// stmt-mo:memory_order_relaxed
+	// stmt-type:store
+	// threads:2
+}
+
+// ExampleStatset_MemOrderMix is a runnable example for Statset.MemOrderMix.
+func ExampleStatset_MemOrderMix() {
+	var s litmus.Statset
+	fmt.Println(s.MemOrderMix())
+
+	s.AtomicStatements.AddMemOrder(id.FromString("memory_order_relaxed"), 3)
+	s.AtomicStatements.AddMemOrder(id.FromString("memory_order_seq_cst"), 0)
+	fmt.Println(s.MemOrderMix())
+
+	s.AtomicExpressions.AddMemOrder(id.FromString("memory_order_acquire"), 1)
+	fmt.Println(s.MemOrderMix())
+
+	// Output:
+	// none
+	// rlx
+	// ra+rlx
+}
+
+// ExampleStatset_HasRMW is a runnable example for Statset.HasRMW.
+func ExampleStatset_HasRMW() {
+	var s litmus.Statset
+	s.AtomicStatements.AddType(id.FromString("store"), 2)
+	s.AtomicExpressions.AddType(id.FromString("cmpxchg"), 0)
+	fmt.Println(s.HasRMW())
+
+	s.AtomicExpressions.AddType(id.FromString("fetch"), 1)
+	fmt.Println(s.HasRMW())
+
+	// Output:
+	// false
+	// true
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/c4-project/c4t/internal/model/recipe"
 
 	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/subject/compilation"
 	"github.com/c4-project/c4t/internal/subject/obs"
 
 	"github.com/c4-project/c4t/internal/id"
@@ -81,6 +82,13 @@ type ObsParser interface {
 	ParseObs(ctx context.Context, r io.Reader, o *obs.Obs) error
 }
 
+// IterationArger is the interface of backends whose executables can be told how many test iterations to perform.
+type IterationArger interface {
+	// IterationArgs gets the arguments to pass to an executable produced by this backend so that it performs the
+	// iterations described by it.
+	IterationArgs(it compilation.Iterations) []string
+}
+
 /*
 // RunExeAndParse runs the program described by r, parses its output with p, and emits the observations into j.
 // It does not yet support the stubbing-out of the runner used.
@@ -0,0 +1,115 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package compiler
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Severity is the severity of a compiler diagnostic, as reported by GCC and Clang.
+type Severity string
+
+const (
+	// SeverityError is the severity of compiler errors (including fatal errors).
+	SeverityError Severity = "error"
+	// SeverityWarning is the severity of compiler warnings.
+	SeverityWarning Severity = "warning"
+	// SeverityNote is the severity of notes attached to other diagnostics.
+	SeverityNote Severity = "note"
+)
+
+// Diagnostic is a structured GCC/Clang-style diagnostic parsed out of a compiler log.
+type Diagnostic struct {
+	// File is the file to which the diagnostic refers.
+	File string `json:"file"`
+	// Line is the line number to which the diagnostic refers, or 0 if not known.
+	Line int `json:"line,omitempty"`
+	// Column is the column number to which the diagnostic refers, or 0 if not known.
+	Column int `json:"column,omitempty"`
+	// Severity is the severity of the diagnostic.
+	Severity Severity `json:"severity"`
+	// Message is the diagnostic message, without any trailing flag.
+	Message string `json:"message"`
+	// Flag is the flag (such as `-Wmaybe-uninitialized`) that enabled the diagnostic, if the compiler reported one.
+	Flag string `json:"flag,omitempty"`
+}
+
+// diagnosticRe matches diagnostics of the form `file:line:col: severity: message [-Wflag]`.
+// The line and column are optional, to cater for diagnostics attached to whole files.
+var diagnosticRe = regexp.MustCompile(
+	`^(.+?):(?:(\d+):(?:(\d+):)?)? *(fatal error|error|warning|note): (.*?)(?: \[(-W[^\]]+)\])?$`,
+)
+
+// MaxDiagnosticLine is the length, in bytes, of the longest compiler log line that ParseDiagnostics accepts.
+//
+// Compilers can emit very long lines (for instance, when quoting macro expansions), so this is well above the
+// default limit of bufio.Scanner.
+const MaxDiagnosticLine = 1 << 20
+
+// ParseDiagnostics parses every GCC/Clang-style diagnostic out of the compiler log log.
+// Lines that don't look like diagnostics, such as source excerpts and caret lines, are skipped.
+//
+// If log has a line longer than MaxDiagnosticLine, ParseDiagnostics returns the diagnostics before it and an error.
+func ParseDiagnostics(log string) ([]Diagnostic, error) {
+	var ds []Diagnostic
+	s := bufio.NewScanner(strings.NewReader(log))
+	s.Buffer(nil, MaxDiagnosticLine)
+	for s.Scan() {
+		if d, ok := ParseDiagnostic(s.Text()); ok {
+			ds = append(ds, d)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return ds, fmt.Errorf("parsing compiler diagnostics: %w", err)
+	}
+	return ds, nil
+}
+
+// ParseDiagnostic tries to parse line as a GCC/Clang-style diagnostic.
+func ParseDiagnostic(line string) (Diagnostic, bool) {
+	m := diagnosticRe.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return Diagnostic{}, false
+	}
+	d := Diagnostic{
+		File:     m[1],
+		Severity: parseSeverity(m[4]),
+		Message:  m[5],
+		Flag:     m[6],
+	}
+	// The regexp guarantees these are either empty or well-formed integers.
+	d.Line, _ = strconv.Atoi(m[2])
+	d.Column, _ = strconv.Atoi(m[3])
+	return d, true
+}
+
+func parseSeverity(s string) Severity {
+	if s == "fatal error" {
+		return SeverityError
+	}
+	return Severity(s)
+}
+
+// WarningFlag gets the warning flag that enabled this diagnostic, in a canonical form.
+//
+// Flags for warnings promoted to errors (`-Werror=foo`) become the flag for the original warning (`-Wfoo`), and
+// any trailing `=` (as in GCC's `-Wformat=`) is removed.  If the diagnostic had no flag, WarningFlag returns the empty
+// string.
+func (d Diagnostic) WarningFlag() string {
+	return CanonicalWarningFlag(d.Flag)
+}
+
+// CanonicalWarningFlag puts the warning flag flag into the canonical form used by Diagnostic.WarningFlag.
+func CanonicalWarningFlag(flag string) string {
+	if rest := strings.TrimPrefix(flag, "-Werror="); rest != flag {
+		flag = "-W" + rest
+	}
+	return strings.TrimSuffix(flag, "=")
+}
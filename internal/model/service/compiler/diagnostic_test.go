@@ -0,0 +1,61 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package compiler_test
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+)
+
+// ExampleParseDiagnostics is a runnable example for ParseDiagnostics.
+func ExampleParseDiagnostics() {
+	log := `foo.c: In function 'P0':
+foo.c:12:7: warning: 'r0' may be used uninitialized [-Wmaybe-uninitialized]
+   12 |   int r0;
+      |       ^~
+foo.c:20: error: expected ';' before '}' token
+bar.c:3:10: fatal error: 'stdatomic.h' file not found
+bar.c:4:1: note: previous definition is here`
+
+	ds, err := compiler.ParseDiagnostics(log)
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	for _, d := range ds {
+		fmt.Printf("%s %d:%d %s [%s] %q\n", d.File, d.Line, d.Column, d.Severity, d.Flag, d.Message)
+	}
+
+	// Output:
+	// foo.c 12:7 warning [-Wmaybe-uninitialized] "'r0' may be used uninitialized"
+	// foo.c 20:0 error [] "expected ';' before '}' token"
+	// bar.c 3:10 error [] "'stdatomic.h' file not found"
+	// bar.c 4:1 note [] "previous definition is here"
+}
+
+// TestParseDiagnostics_longLine tests that ParseDiagnostics copes with lines longer than bufio.Scanner's default
+// limit, and reports lines longer than MaxDiagnosticLine rather than dropping them.
+func TestParseDiagnostics_longLine(t *testing.T) {
+	t.Parallel()
+
+	long := "foo.c:1:2: warning: " + strings.Repeat("x", 100*1024) + " [-Wfoo]\nfoo.c:3:4: error: bad"
+	ds, err := compiler.ParseDiagnostics(long)
+	require.NoError(t, err, "long line within limit")
+	require.Len(t, ds, 2, "both diagnostics should parse")
+	assert.Equal(t, "-Wfoo", ds[0].Flag)
+
+	tooLong := "foo.c:1:2: error: bad\n" + strings.Repeat("x", compiler.MaxDiagnosticLine+1)
+	ds, err = compiler.ParseDiagnostics(tooLong)
+	assert.ErrorIs(t, err, bufio.ErrTooLong, "overlong line should be an error")
+	assert.Len(t, ds, 1, "diagnostics before the overlong line should survive")
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package fuzzer
+
+import (
+	"context"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/service"
+)
+
+// Fuzzer is the interface of things that can run single fuzzer jobs.
+type Fuzzer interface {
+	// Fuzz carries out the given fuzzing job.
+	Fuzz(ctx context.Context, j Job) error
+}
+
+// Class is the interface of fuzzer classes: things that can make fuzzers of a particular style.
+type Class interface {
+	// Instantiate makes a fuzzer from the configuration c, which uses sr to run any external services.
+	Instantiate(c Config, sr service.Runner) (Fuzzer, error)
+}
+
+// Resolver is the interface of things that can resolve fuzzer styles.
+type Resolver interface {
+	// Resolve tries to resolve the style ID style into a fuzzer class.
+	Resolve(style id.ID) (Class, error)
+}
+
+// ResolveAndInstantiate uses r to resolve c's style, then uses c and sr to instantiate the resulting class.
+func ResolveAndInstantiate(c Config, r Resolver, sr service.Runner) (Fuzzer, error) {
+	cls, err := r.Resolve(c.Style)
+	if err != nil {
+		return nil, err
+	}
+	return cls.Instantiate(c, sr)
+}
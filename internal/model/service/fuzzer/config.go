@@ -5,15 +5,31 @@
 
 package fuzzer
 
+import (
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/service"
+)
+
 // Config configures the fuzzer.
 type Config struct {
 	// Disabled, if set true, disables the fuzzer stage in the main tester.
 	Disabled bool `toml:"disabled,omitempty"`
 
+	// Style, if non-empty, names the style of fuzzer to use in place of the default, c4f.
+	Style id.ID `toml:"style,omitzero"`
+
+	// Run tells fuzzers that run external commands, such as the 'external' style, how to run them.
+	// Its arguments and environment can refer to the fuzzer job with ${input}, ${output}, ${trace}, and ${seed}.
+	Run *service.RunInfo `toml:"run,omitempty"`
+
 	// FuzzesPerSubject specifies the default number of times the fuzzer will be invoked per subject.
 	// If zero, the default number is used.
 	FuzzesPerSubject int `toml:"fuzzes_per_subject,omitempty"`
 
 	// Params contains a low-level key-value map of parameters to pass to the fuzzer.
 	Params map[string]string `toml:"params,omitempty"`
+
+	// Tune, if present, makes the director vary some parameters from cycle to cycle, favouring settings that produce
+	// interesting outcomes.
+	Tune *TuneConfig `toml:"tune,omitempty"`
 }
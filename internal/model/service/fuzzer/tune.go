@@ -0,0 +1,121 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package fuzzer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultTuneEpsilon is the exploration rate used when a tuning configuration doesn't specify one.
+	DefaultTuneEpsilon = 0.2
+
+	// MaxTuneArms is the largest number of values an integer range in a tuning space may expand into.
+	// The tuner keeps statistics for, and must try, every value, so larger ranges need a coarser step.
+	MaxTuneArms = 1000
+)
+
+// ErrBadTuneSpace occurs when a tunable parameter has no usable values.
+var ErrBadTuneSpace = errors.New("bad tuning space")
+
+// TuneConfig configures adaptive tuning of fuzzer parameters.
+type TuneConfig struct {
+	// Epsilon is the proportion of cycles on which each parameter is picked at random, rather than according to
+	// its best setting so far.
+	// If zero, DefaultTuneEpsilon is used.
+	Epsilon float64 `toml:"epsilon,omitzero"`
+
+	// Params maps parameter keys, as in Config.Params, to the values over which they can be tuned.
+	// Tuned parameters override any fixed setting in Config.Params.
+	Params map[string]TuneSpace `toml:"params,omitempty"`
+}
+
+// EpsilonOrDefault gets Epsilon, or DefaultTuneEpsilon if it is zero.
+func (c *TuneConfig) EpsilonOrDefault() float64 {
+	if c.Epsilon == 0 {
+		return DefaultTuneEpsilon
+	}
+	return c.Epsilon
+}
+
+// Keys gets the tuned parameter keys in ascending order.
+func (c *TuneConfig) Keys() []string {
+	ks := make([]string, 0, len(c.Params))
+	for k := range c.Params {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	return ks
+}
+
+// TuneSpace is the set of values over which the tuner can vary one fuzzer parameter.
+//
+// Either Values lists the candidate values directly, or Min, Max, and Step describe an integer range; the latter is
+// useful for `int.` parameters and `action.` weights.  `bool.` parameters with neither default to true and false.
+type TuneSpace struct {
+	// Values lists the candidate values, in the same format as Config.Params.
+	Values []string `toml:"values,omitempty"`
+	// Min is the lowest value of an integer range.
+	Min int `toml:"min,omitzero"`
+	// Max is the highest value of an integer range.
+	Max int `toml:"max,omitzero"`
+	// Step is the step of an integer range; if zero, it is 1.
+	Step int `toml:"step,omitzero"`
+}
+
+// Arms expands this space, for the parameter key, into its list of candidate values.
+func (s TuneSpace) Arms(key string) ([]string, error) {
+	if len(s.Values) != 0 {
+		return s.Values, nil
+	}
+	if s.Min == 0 && s.Max == 0 && strings.HasPrefix(key, "bool.") {
+		return []string{"true", "false"}, nil
+	}
+	step := s.Step
+	if step == 0 {
+		step = 1
+	}
+	if step < 0 || s.Max < s.Min {
+		return nil, fmt.Errorf("%w: %s ranges from %d to %d in steps of %d", ErrBadTuneSpace, key, s.Min, s.Max, step)
+	}
+	// Max-Min can overflow an int, but is always correct as an unsigned difference.
+	n := uint64(s.Max-s.Min)/uint64(step) + 1
+	if MaxTuneArms < n {
+		return nil, fmt.Errorf("%w: %s ranges from %d to %d in steps of %d, giving %d values; the most allowed is %d",
+			ErrBadTuneSpace, key, s.Min, s.Max, step, n, MaxTuneArms)
+	}
+	arms := make([]string, n)
+	for i := range arms {
+		arms[i] = strconv.Itoa(s.Min + i*step)
+	}
+	return arms, nil
+}
+
+// WithParams gets a copy of this config with the parameters in ps overriding those in Params.
+// If ps is empty, it returns this config unchanged.
+func (c *Config) WithParams(ps map[string]string) *Config {
+	if len(ps) == 0 {
+		return c
+	}
+	var nc Config
+	if c != nil {
+		nc = *c
+	}
+	nc.Params = make(map[string]string, len(nc.Params)+len(ps))
+	if c != nil {
+		for k, v := range c.Params {
+			nc.Params[k] = v
+		}
+	}
+	for k, v := range ps {
+		nc.Params[k] = v
+	}
+	return &nc
+}
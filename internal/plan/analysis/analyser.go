@@ -54,16 +54,32 @@ type analyser struct {
 
 	// filters is the set of filters to use when filtering compiler results.
 	filters FilterSet
+
+	// suppressions is the set of suppressions to use when filtering bad outcomes.
+	suppressions SuppressionSet
+
+	// warnings, if non-nil, is the history against which we check for new warnings.
+	warnings WarningHistory
+
+	// timings, if non-nil, is the history against which we check for timing regressions.
+	timings TimingHistory
+
+	// regressions is the policy used to decide whether a timing is a regression.
+	regressions RegressionPolicy
 }
 
 // analyse runs the analyser with context ctx.
 func (a *analyser) analyse(ctx context.Context) (*Analysis, error) {
+	a.analysis.Suppressions = newSuppressionReports(a.suppressions)
 	if err := a.analyseCorpus(ctx); err != nil {
 		return nil, err
 	}
+	finishSuppressionReports(a.analysis.Suppressions, a.suppressions)
 	if err := a.analyseCompilers(ctx); err != nil {
 		return nil, err
 	}
+	a.analyseNewWarnings()
+	a.analyseRegressions()
 
 	return a.analysis, nil
 }
@@ -82,6 +98,25 @@ func (a *analyser) analyseCompilers(ctx context.Context) error {
 	return nil
 }
 
+func (a *analyser) analyseNewWarnings() {
+	if a.warnings == nil {
+		return
+	}
+	a.analysis.NewWarnings = []WarningKey{}
+	for _, k := range a.analysis.WarningKeys() {
+		if !a.warnings.Has(k) {
+			a.analysis.NewWarnings = append(a.analysis.NewWarnings, k)
+		}
+	}
+}
+
+func (a *analyser) analyseRegressions() {
+	if a.timings == nil {
+		return
+	}
+	a.analysis.Regressions = a.regressions.Regressions(a.analysis.TimingSamples(), a.timings)
+}
+
 func (a *analyser) analyseCorpus(ctx context.Context) error {
 	ch := make(chan subjectAnalysis)
 	err := a.corpus.Par(ctx, a.nworkers,
@@ -108,6 +143,7 @@ func newAnalyser(p *plan.Plan, opts ...Option) (*analyser, error) {
 		corpus:        p.Corpus,
 		compilerTimes: make(map[id.ID][]time.Duration, lc),
 		runTimes:      make(map[id.ID][]time.Duration, lc),
+		regressions:   DefaultRegressionPolicy,
 	}
 	if err := Options(opts...)(&a); err != nil {
 		return nil, err
@@ -125,7 +161,17 @@ func checkPlan(p *plan.Plan) error {
 
 func (a *analyser) initCompilers(cs compiler.InstanceMap) error {
 	for cn, c := range cs {
-		a.analysis.Compilers[cn] = Compiler{Counts: map[status.Status]int{}, Logs: map[string]string{}, Info: c}
+		a.analysis.Compilers[cn] = Compiler{
+			Counts:   map[status.Status]int{},
+			Logs:     map[string]string{},
+			Statuses: map[string]status.Status{},
+			Warnings: WarningSet{},
+			Features: FeatureSet{},
+			Info:     c,
+
+			SubjectTimes:    map[string]time.Duration{},
+			SubjectRunTimes: map[string]time.Duration{},
+		}
 		a.compilerTimes[cn] = []time.Duration{}
 		a.runTimes[cn] = []time.Duration{}
 	}
@@ -156,6 +202,8 @@ func (a *analyser) apply(r subjectAnalysis) {
 	a.applyCompilers(r)
 	a.applyTimes(r)
 	a.applyMutants(r)
+	a.applyClusters(r)
+	a.applySuppressions(r)
 
 	for i := status.Ok; i <= status.Last; i++ {
 		a.applyByStatus(i, r)
@@ -169,6 +217,11 @@ func (a *analyser) applyCompilers(r subjectAnalysis) {
 			continue
 		}
 		a.analysis.Compilers[cid].Logs[r.sub.Name] = r.clogs[cid]
+		a.analysis.Compilers[cid].Statuses[r.sub.Name] = cflag.Status()
+		for _, w := range r.cwarns[cid] {
+			a.analysis.Compilers[cid].Warnings.Add(r.sub.Name, w)
+		}
+		a.analysis.Compilers[cid].Features.Add(cflag.Status(), r.features...)
 
 		for i := status.Ok; i <= status.Last; i++ {
 			a.applyCompilerStatusCount(i, cflag, cid)
@@ -176,6 +229,19 @@ func (a *analyser) applyCompilers(r subjectAnalysis) {
 	}
 }
 
+func (a *analyser) applyClusters(r subjectAnalysis) {
+	for cid, sig := range r.sigs {
+		a.analysis.Clusters.Add(sig, compilation.Name{SubjectName: r.sub.Name, CompilerID: cid})
+	}
+}
+
+func (a *analyser) applySuppressions(r subjectAnalysis) {
+	for cid, i := range r.suppressed {
+		rep := &a.analysis.Suppressions[i]
+		rep.Hits = insertName(rep.Hits, compilation.Name{SubjectName: r.sub.Name, CompilerID: cid})
+	}
+}
+
 func (a *analyser) applyByStatus(s status.Status, r subjectAnalysis) {
 	if !r.flags.MatchesStatus(s) {
 		return
@@ -196,10 +262,24 @@ func (a *analyser) applyCompilerStatusCount(s status.Status, cf status.Flag, cid
 func (a *analyser) applyTimes(r subjectAnalysis) {
 	for cstr, ts := range r.ctimes {
 		a.compilerTimes[cstr] = append(a.compilerTimes[cstr], ts...)
+		if c, ok := a.analysis.Compilers[cstr]; ok && len(ts) != 0 {
+			c.SubjectTimes[r.sub.Name] = sumDurations(ts)
+		}
 	}
 	for cstr, ts := range r.rtimes {
 		a.runTimes[cstr] = append(a.runTimes[cstr], ts...)
+		if c, ok := a.analysis.Compilers[cstr]; ok && len(ts) != 0 {
+			c.SubjectRunTimes[r.sub.Name] = sumDurations(ts)
+		}
+	}
+}
+
+func sumDurations(ts []time.Duration) time.Duration {
+	var sum time.Duration
+	for _, t := range ts {
+		sum += t
 	}
+	return sum
 }
 
 func (a *analyser) applyMutants(r subjectAnalysis) {
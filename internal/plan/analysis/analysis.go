@@ -8,6 +8,7 @@ package analysis
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/c4-project/c4t/internal/id"
 
@@ -33,6 +34,21 @@ type Analysis struct {
 	// Compilers maps each compiler ID (or full-ID, depending on configuration) to an analysis of that compiler.
 	Compilers map[id.ID]Compiler
 
+	// Clusters groups the bad compilations in the plan by their failure signatures.
+	Clusters ClusterSet
+
+	// Suppressions reports, for each suppression in effect, which compilations it suppressed.
+	Suppressions []SuppressionReport
+
+	// NewWarnings lists, in ascending order, the warnings raised in this analysis that weren't in the warning history.
+	// It is nil if the analysis wasn't given a warning history.
+	NewWarnings []WarningKey
+
+	// Regressions lists, in ascending key order, the subject compile and run times in this analysis that were
+	// significantly longer than their historical medians.
+	// It is nil if the analysis wasn't given a timing history.
+	Regressions []Regression
+
 	// Flags aggregates all flags found during the analysis.
 	Flags status.Flag
 
@@ -51,6 +67,15 @@ type Compiler struct {
 	// Logs maps each subject name to its compiler log.
 	Logs map[string]string
 
+	// Statuses maps each subject name to the final status of its compilation (and run) with this compiler.
+	Statuses map[string]status.Status
+
+	// Warnings aggregates the warnings this compiler raised across the corpus.
+	Warnings WarningSet
+
+	// Features correlates the test features of the corpus with this compiler's outcomes on them.
+	Features FeatureSet
+
 	// Time gathers statistics about how long, on average, this compiler took to compile corpus subjects.
 	// It doesn't contain information about failed compilations.
 	Time *TimeSet
@@ -58,6 +83,13 @@ type Compiler struct {
 	// RunTime gathers statistics about how long, on average, this compiler's compiled subjects took to run.
 	// It doesn't contain information about failed compilations or runs (flagged runs are counted).
 	RunTime *TimeSet
+
+	// SubjectTimes maps each subject name to how long this compiler took to compile it.
+	// Like Time, it doesn't contain information about failed compilations.
+	SubjectTimes map[string]time.Duration
+
+	// SubjectRunTimes maps each subject name to how long its compiled binary took to run.
+	SubjectRunTimes map[string]time.Duration
 }
 
 func newAnalysis(p *plan.Plan) *Analysis {
@@ -65,6 +97,7 @@ func newAnalysis(p *plan.Plan) *Analysis {
 		Plan:      p,
 		ByStatus:  make(map[status.Status]corpus.Corpus, status.Last),
 		Compilers: make(map[id.ID]Compiler, len(p.Compilers)),
+		Clusters:  make(ClusterSet),
 		Mutation:  make(mutation.Analysis),
 	}
 }
@@ -92,6 +125,17 @@ func (a *Analysis) String() string {
 	return sb.String()
 }
 
+// StaleSuppressions gets the reports of any suppressions in this analysis that appear to be stale.
+func (a *Analysis) StaleSuppressions() []SuppressionReport {
+	var rs []SuppressionReport
+	for _, r := range a.Suppressions {
+		if r.Stale {
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
+
 // HasFlagged tests whether an analysis has flagged cases.
 func (a *Analysis) HasFlagged() bool {
 	return a.Flags.MatchesStatus(status.Flagged)
@@ -0,0 +1,234 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package analysis
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/obs"
+	"github.com/c4-project/c4t/internal/subject/status"
+)
+
+// Signature fingerprints a bad compilation, so that failures likely to share an underlying cause compare equal.
+type Signature struct {
+	// Style is the style of the compiler that produced the failure.
+	Style id.ID `json:"style"`
+	// Arch is the architecture targeted by the compiler.
+	Arch id.ID `json:"arch,omitempty"`
+	// Command is the compiler command, which is the closest thing plans record to a compiler version.
+	Command string `json:"command,omitempty"`
+	// Opt is the name of the selected optimisation level, if any.
+	Opt string `json:"opt,omitempty"`
+	// Status is the status of the failure.
+	Status status.Status `json:"status"`
+	// Detail is the normalised compiler error message for compile failures, or the unexpected states for flagged runs.
+	// For runs flagged as having undefined behaviour, which have no unexpected states as such, it is every state.
+	Detail string `json:"detail,omitempty"`
+}
+
+// NewSignature makes the signature of a compilation by compiler ci with status s, compile log clog, and observation o.
+//
+// o may be nil if the compilation did not run.
+func NewSignature(ci compiler.Instance, s status.Status, clog string, o *obs.Obs) Signature {
+	sig := Signature{
+		Style:  ci.Style,
+		Arch:   ci.Arch,
+		Opt:    ci.SelectedOptName(),
+		Status: s,
+	}
+	if ci.Run != nil {
+		sig.Command = ci.Run.Cmd
+	}
+	switch {
+	case s == status.CompileFail:
+		sig.Detail = NormaliseLog(clog)
+	case s == status.Flagged && o != nil:
+		sig.Detail = flaggedStates(*o)
+	}
+	return sig
+}
+
+// Fingerprint gets a short, stable hash of this signature.
+func (s Signature) Fingerprint() string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%s\x00%s", s.Style, s.Arch, s.Command, s.Opt, s.Status, s.Detail)
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// String summarises this signature in a human-readable manner.
+func (s Signature) String() string {
+	var sb strings.Builder
+	sb.WriteString(s.Status.String())
+	if !s.Style.IsEmpty() {
+		_, _ = fmt.Fprintf(&sb, " %s@%s", s.Style, s.Arch)
+	}
+	if s.Opt != "" {
+		_, _ = fmt.Fprintf(&sb, " opt %q", s.Opt)
+	}
+	if s.Detail != "" {
+		_, _ = fmt.Fprintf(&sb, ": %s", firstLine(s.Detail))
+	}
+	return sb.String()
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); 0 <= i {
+		return s[:i] + " [...]"
+	}
+	return s
+}
+
+var (
+	// logPathRe matches file paths in compiler logs.
+	logPathRe = regexp.MustCompile(`(?:[\w.~-]*/)+[\w.-]+`)
+	// logNumberRe matches numbers, including line/column positions and hex addresses, in compiler logs.
+	logNumberRe = regexp.MustCompile(`\b(?:0x[[:xdigit:]]+|\d+)\b`)
+	// logErrorRe matches compiler log lines that report errors.
+	logErrorRe = regexp.MustCompile(`(?i)\b(?:error|fatal|internal compiler error)\b`)
+)
+
+// NormaliseLog reduces the compiler log clog to a form suitable for comparing against other logs.
+//
+// If the log contains error lines, we keep only those; in any case, we replace paths and numbers with placeholders,
+// and collapse whitespace, so that two logs for the same error on different subjects compare equal.
+func NormaliseLog(clog string) string {
+	lines := strings.Split(clog, "\n")
+	if errs := errorLines(lines); len(errs) != 0 {
+		lines = errs
+	}
+
+	norm := make([]string, 0, len(lines))
+	for _, l := range lines {
+		l = logPathRe.ReplaceAllString(l, "<path>")
+		l = logNumberRe.ReplaceAllString(l, "N")
+		if l = strings.Join(strings.Fields(l), " "); l != "" {
+			norm = append(norm, l)
+		}
+	}
+	return strings.Join(norm, "\n")
+}
+
+func errorLines(lines []string) []string {
+	var errs []string
+	for _, l := range lines {
+		if logErrorRe.MatchString(l) {
+			errs = append(errs, l)
+		}
+	}
+	return errs
+}
+
+// flaggedStates gets a canonical, sorted rendering of the states that made o flagged.
+//
+// These are the unexpected states if there are any; otherwise, if o has undefined behaviour, they are all of o's
+// states, marked as such.
+func flaggedStates(o obs.Obs) string {
+	ss := o.Unexpected()
+	if len(ss) == 0 && o.Flags&obs.Undef == obs.Undef {
+		return "undef\n" + stateKeys(o.States)
+	}
+	return stateKeys(ss)
+}
+
+// stateKeys gets a canonical, sorted rendering of the states ss.
+func stateKeys(ss []obs.State) string {
+	keys := make([]string, len(ss))
+	for i, s := range ss {
+		keys[i] = s.Key()
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, "\n")
+}
+
+// Cluster groups together bad compilations that share a signature.
+type Cluster struct {
+	// Signature is the signature shared by every member of the cluster.
+	Signature Signature `json:"signature"`
+	// Representative is the member chosen to stand for the whole cluster.
+	Representative compilation.Name `json:"representative"`
+	// Members lists every compilation in the cluster, in ascending order.
+	Members []compilation.Name `json:"members,omitempty"`
+}
+
+// add adds the compilation name to this cluster, keeping the representative as the first member in order.
+func (c *Cluster) add(name compilation.Name) {
+	c.Members = insertName(c.Members, name)
+	c.Representative = c.Members[0]
+}
+
+// insertName inserts name into the ascending list names.
+func insertName(names []compilation.Name, name compilation.Name) []compilation.Name {
+	i := sort.Search(len(names), func(i int) bool { return !names[i].Less(name) })
+	names = append(names, compilation.Name{})
+	copy(names[i+1:], names[i:])
+	names[i] = name
+	return names
+}
+
+// ClusterSet maps signature fingerprints to clusters.
+type ClusterSet map[string]Cluster
+
+// Add files the compilation name under the signature sig.
+func (s ClusterSet) Add(sig Signature, name compilation.Name) {
+	fp := sig.Fingerprint()
+	c, ok := s[fp]
+	if !ok {
+		c = Cluster{Signature: sig}
+	}
+	c.add(name)
+	s[fp] = c
+}
+
+// Fingerprints gets the fingerprints of each cluster in this set, largest cluster first.
+//
+// Clusters of equal size appear in fingerprint order.
+func (s ClusterSet) Fingerprints() []string {
+	fps := make([]string, 0, len(s))
+	for fp := range s {
+		fps = append(fps, fp)
+	}
+	sort.Slice(fps, func(i, j int) bool {
+		ni, nj := len(s[fps[i]].Members), len(s[fps[j]].Members)
+		if ni != nj {
+			return ni > nj
+		}
+		return fps[i] < fps[j]
+	})
+	return fps
+}
+
+// Sorted gets the clusters in this set, largest cluster first.
+func (s ClusterSet) Sorted() []Cluster {
+	fps := s.Fingerprints()
+	cs := make([]Cluster, len(fps))
+	for i, fp := range fps {
+		cs[i] = s[fp]
+	}
+	return cs
+}
+
+// OfSubject gets the fingerprints of every cluster in this set containing a compilation of the subject sname.
+func (s ClusterSet) OfSubject(sname string) []string {
+	var fps []string
+	for fp, c := range s {
+		for _, m := range c.Members {
+			if m.SubjectName == sname {
+				fps = append(fps, fp)
+				break
+			}
+		}
+	}
+	sort.Strings(fps)
+	return fps
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package analysis_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/obs"
+	"github.com/c4-project/c4t/internal/subject/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ExampleNormaliseLog is a runnable example for NormaliseLog.
+func ExampleNormaliseLog() {
+	fmt.Println(analysis.NormaliseLog(`/tmp/c4t/foo/main.c: In function 'P0':
+/tmp/c4t/foo/main.c:12:5: error: invalid memory model for '__atomic_load'
+   12 |     atomic_load_explicit(x, 4);
+`))
+
+	// Output:
+	// <path>:N:N: error: invalid memory model for '__atomic_load'
+}
+
+// TestNewSignature tests that signatures of equivalent failures on different subjects agree.
+func TestNewSignature(t *testing.T) {
+	t.Parallel()
+
+	gcc := compiler.Instance{Compiler: compiler.Compiler{Style: id.CStyleGCC, Arch: id.ArchX8664}}
+	witness := func(x string) *obs.Obs {
+		return &obs.Obs{
+			Flags:  obs.Sat | obs.Exist,
+			States: []obs.State{{Tag: obs.TagWitness, Values: obs.Valuation{"x": x}}},
+		}
+	}
+
+	undef := func(x string) *obs.Obs {
+		return &obs.Obs{Flags: obs.Undef, States: []obs.State{{Values: obs.Valuation{"x": x}}}}
+	}
+
+	cases := map[string]struct {
+		x, y  analysis.Signature
+		equal bool
+	}{
+		"same-error-different-paths": {
+			x:     analysis.NewSignature(gcc, status.CompileFail, "a/foo.c:1:2: error: bad", nil),
+			y:     analysis.NewSignature(gcc, status.CompileFail, "b/bar.c:3:4: error: bad", nil),
+			equal: true,
+		},
+		"different-errors": {
+			x:     analysis.NewSignature(gcc, status.CompileFail, "foo.c:1:2: error: bad", nil),
+			y:     analysis.NewSignature(gcc, status.CompileFail, "foo.c:1:2: error: worse", nil),
+			equal: false,
+		},
+		"same-states": {
+			x:     analysis.NewSignature(gcc, status.Flagged, "", witness("1")),
+			y:     analysis.NewSignature(gcc, status.Flagged, "warning: unrelated", witness("1")),
+			equal: true,
+		},
+		"different-states": {
+			x:     analysis.NewSignature(gcc, status.Flagged, "", witness("1")),
+			y:     analysis.NewSignature(gcc, status.Flagged, "", witness("2")),
+			equal: false,
+		},
+		"same-undef-states": {
+			x:     analysis.NewSignature(gcc, status.Flagged, "", undef("1")),
+			y:     analysis.NewSignature(gcc, status.Flagged, "", undef("1")),
+			equal: true,
+		},
+		"different-undef-states": {
+			x:     analysis.NewSignature(gcc, status.Flagged, "", undef("1")),
+			y:     analysis.NewSignature(gcc, status.Flagged, "", undef("2")),
+			equal: false,
+		},
+		"different-status": {
+			x:     analysis.NewSignature(gcc, status.RunFail, "", nil),
+			y:     analysis.NewSignature(gcc, status.RunTimeout, "", nil),
+			equal: false,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if c.equal {
+				assert.Equal(t, c.x.Fingerprint(), c.y.Fingerprint(), "fingerprints should agree")
+			} else {
+				assert.NotEqual(t, c.x.Fingerprint(), c.y.Fingerprint(), "fingerprints should differ")
+			}
+		})
+	}
+}
+
+// TestClusterSet_Add tests that adding to a cluster set keeps representatives and ordering deterministic.
+func TestClusterSet_Add(t *testing.T) {
+	t.Parallel()
+
+	sig := analysis.Signature{Style: id.CStyleGCC, Status: status.RunFail}
+	other := analysis.Signature{Style: id.CStyleGCC, Status: status.RunTimeout}
+	gcc := id.FromString("gcc")
+
+	cs := make(analysis.ClusterSet)
+	cs.Add(sig, compilation.Name{SubjectName: "foo", CompilerID: gcc})
+	cs.Add(other, compilation.Name{SubjectName: "baz", CompilerID: gcc})
+	cs.Add(sig, compilation.Name{SubjectName: "bar", CompilerID: gcc})
+
+	got := cs.Sorted()
+	require.Len(t, got, 2, "wrong number of clusters")
+	assert.Equal(t, sig, got[0].Signature, "largest cluster should come first")
+	assert.Equal(t, compilation.Name{SubjectName: "bar", CompilerID: gcc}, got[0].Representative, "wrong representative")
+	assert.Len(t, got[0].Members, 2, "wrong number of members")
+	assert.Equal(t, []string{other.Fingerprint()}, cs.OfSubject("baz"), "wrong clusters for subject")
+}
+
+// TestAnalyse_clusters tests that analysing the mock plan clusters its bad outcomes.
+func TestAnalyse_clusters(t *testing.T) {
+	t.Parallel()
+
+	crp, err := analysis.Analyse(context.Background(), plan.Mock())
+	require.NoError(t, err, "unexpected error analysing")
+
+	got := make(map[status.Status][]string)
+	for _, c := range crp.Clusters.Sorted() {
+		got[c.Signature.Status] = append(got[c.Signature.Status], c.Representative.SubjectName)
+	}
+	want := map[status.Status][]string{
+		status.Flagged:     {"baz", "baz"},
+		status.CompileFail: {"bar"},
+		status.RunTimeout:  {"barbaz"},
+	}
+	assert.Equal(t, want, got, "wrong cluster representatives")
+}
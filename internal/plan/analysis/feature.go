@@ -0,0 +1,109 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package analysis
+
+import (
+	"sort"
+
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/status"
+)
+
+// FeatureOutcomes counts the outcomes of compilations of subjects with a particular test feature.
+type FeatureOutcomes struct {
+	// Feature is the feature, as reported by litmus.Statset.Features.
+	Feature string
+	// Total is the number of compilations of subjects with the feature.
+	Total int
+	// Counts maps each status to the number of compilations of subjects with the feature that had that status.
+	Counts map[status.Status]int
+}
+
+// Rate gets the proportion, between 0 and 1, of compilations with this feature that had status s.
+func (f FeatureOutcomes) Rate(s status.Status) float64 {
+	if f.Total == 0 {
+		return 0
+	}
+	return float64(f.Counts[s]) / float64(f.Total)
+}
+
+// BadRate gets the proportion, between 0 and 1, of compilations with this feature that had a bad status.
+func (f FeatureOutcomes) BadRate() float64 {
+	if f.Total == 0 {
+		return 0
+	}
+	bad := 0
+	for s := status.FirstBad; s <= status.Last; s++ {
+		bad += f.Counts[s]
+	}
+	return float64(bad) / float64(f.Total)
+}
+
+// FeatureSet maps test features to the outcomes of compilations of subjects with those features.
+type FeatureSet map[string]FeatureOutcomes
+
+// Add records that a compilation of a subject with features fs had status s.
+func (fs FeatureSet) Add(s status.Status, features ...string) {
+	for _, f := range features {
+		fo, ok := fs[f]
+		if !ok {
+			fo = FeatureOutcomes{Feature: f, Counts: map[status.Status]int{}}
+		}
+		fo.Total++
+		fo.Counts[s]++
+		fs[f] = fo
+	}
+}
+
+// Merge adds every outcome in fs2 into this set.
+func (fs FeatureSet) Merge(fs2 FeatureSet) {
+	for f, fo2 := range fs2 {
+		fo, ok := fs[f]
+		if !ok {
+			fo = FeatureOutcomes{Feature: f, Counts: map[status.Status]int{}}
+		}
+		fo.Total += fo2.Total
+		for s, n := range fo2.Counts {
+			fo.Counts[s] += n
+		}
+		fs[f] = fo
+	}
+}
+
+// Sorted gets the outcomes in this set in descending order of bad-outcome rate, then ascending order of feature.
+// This puts the features that seem to be finding the most bugs first.
+func (fs FeatureSet) Sorted() []FeatureOutcomes {
+	sorted := make([]FeatureOutcomes, 0, len(fs))
+	for _, fo := range fs {
+		sorted = append(sorted, fo)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		ri, rj := sorted[i].BadRate(), sorted[j].BadRate()
+		if ri != rj {
+			return ri > rj
+		}
+		return sorted[i].Feature < sorted[j].Feature
+	})
+	return sorted
+}
+
+// Features aggregates the feature outcomes of every compiler in the analysis.
+func (a *Analysis) Features() FeatureSet {
+	fs := FeatureSet{}
+	for _, c := range a.Compilers {
+		fs.Merge(c.Features)
+	}
+	return fs
+}
+
+// subjectFeatures gets the test features of s, preferring the statistics of its fuzzed test if it has any.
+// If there are no statistics, s has no features.
+func subjectFeatures(s subject.Subject) []string {
+	if s.HasFuzzFile() && s.Fuzz.Litmus.Stats != nil {
+		return s.Fuzz.Litmus.Stats.Features()
+	}
+	return s.Source.Stats.Features()
+}
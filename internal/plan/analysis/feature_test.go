@@ -0,0 +1,59 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package analysis_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/subject/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// featurePlan makes a mock plan where baz and bar both contain seq_cst atomic stores.
+func featurePlan() *plan.Plan {
+	m := plan.Mock()
+	for _, sn := range []string{"bar", "baz"} {
+		s := m.Corpus[sn]
+		s.Source.Stats.AtomicStatements.AddType(id.FromString("store"), 1)
+		s.Source.Stats.AtomicStatements.AddMemOrder(id.FromString("seq_cst"), 1)
+		m.Corpus[sn] = s
+	}
+	return m
+}
+
+// TestAnalyse_features tests that the analyser correlates subject features with outcomes.
+func TestAnalyse_features(t *testing.T) {
+	t.Parallel()
+
+	crp, err := analysis.Analyse(context.Background(), featurePlan())
+	require.NoError(t, err, "unexpected error analysing")
+
+	gcc := crp.Compilers[id.FromString("gcc")].Features
+	mo, ok := gcc["stmt-mo:seq_cst"]
+	require.True(t, ok, "gcc should have seen seq_cst statements")
+	assert.Equal(t, 2, mo.Total)
+	assert.Equal(t, map[status.Status]int{status.Flagged: 1, status.CompileFail: 1}, mo.Counts)
+	assert.Equal(t, 1.0, mo.BadRate())
+	assert.Equal(t, 0.5, mo.Rate(status.Flagged))
+
+	clang := crp.Compilers[id.FromString("clang")].Features
+	assert.Equal(t, 0.0, clang["stmt-mo:seq_cst"].BadRate(), "clang compiled bar fine")
+
+	fs := crp.Features()
+	assert.Equal(t, 3, fs["stmt-mo:seq_cst"].Total, "features should merge across compilers")
+
+	sorted := fs.Sorted()
+	require.NotEmpty(t, sorted)
+	assert.Equal(t, "threads:2", sorted[0].Feature, "baz's features should be worst")
+	for i := 1; i < len(sorted); i++ {
+		assert.GreaterOrEqual(t, sorted[i-1].BadRate(), sorted[i].BadRate(), "not sorted by bad rate")
+	}
+}
@@ -31,6 +31,9 @@ type Filter struct {
 	MajorVersionBelow int `yaml:"major_version_below,omitempty"`
 	// ErrorPattern is an uncompiled regexp that selects a particular phrase in a compiler error.
 	ErrorPattern string `yaml:"error_pattern,omitempty"`
+	// WarningFlag, if non-empty, selects compilations whose logs contain a diagnostic raised by this warning flag
+	// (for instance, `-Wmaybe-uninitialized`).  Promoted flags such as `-Werror=maybe-uninitialized` also match.
+	WarningFlag string `yaml:"warning_flag,omitempty"`
 	// compiledPattern is the compiled version of ErrorPattern.
 	compiledPattern *regexp.Regexp
 }
@@ -102,9 +105,26 @@ func (f Filter) Filter(ci compiler.Instance, log string) (bool, error) {
 		return false, err
 	}
 	// TODO(@MattWindsor91): compiler versions
+	if ok, err := f.filterWarningFlag(log); !ok || err != nil {
+		return false, err
+	}
 	return f.filterCompilerLog(log)
 }
 
+func (f Filter) filterWarningFlag(log string) (bool, error) {
+	if f.WarningFlag == "" {
+		return true, nil
+	}
+	want := compiler.CanonicalWarningFlag(f.WarningFlag)
+	ws, err := ParseWarnings(log)
+	for _, w := range ws {
+		if w.WarningFlag() == want {
+			return true, nil
+		}
+	}
+	return false, err
+}
+
 func (f Filter) filterCompilerLog(log string) (bool, error) {
 	if f.compiledPattern == nil {
 		return false, errors.New("filter was not compiled")
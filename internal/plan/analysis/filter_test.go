@@ -83,6 +83,20 @@ func TestFilterSet_FilteredStatus(t *testing.T) {
 			inStatus:   status.Ok,
 			want:       status.Ok,
 		},
+		"filtering by warning flag": {
+			fsOverride: mustCompile(t, analysis.FilterSet{{Style: id.FromString("gcc"), WarningFlag: "-Wmaybe-uninitialized"}}),
+			inComp:     compiler.MockX86Gcc(),
+			inLog:      "foo.c:1:2: error: 'x' may be used uninitialized [-Werror=maybe-uninitialized]",
+			inStatus:   status.CompileFail,
+			want:       status.Filtered,
+		},
+		"filtering by warning flag, absent": {
+			fsOverride: mustCompile(t, analysis.FilterSet{{Style: id.FromString("gcc"), WarningFlag: "-Wmaybe-uninitialized"}}),
+			inComp:     compiler.MockX86Gcc(),
+			inLog:      "foo.c:1:2: warning: unused variable 'x' [-Wunused-variable]",
+			inStatus:   status.CompileFail,
+			want:       status.CompileFail,
+		},
 		"filtering with a broken filter set": {
 			fsOverride: analysis.FilterSet{
 				{
@@ -117,3 +131,10 @@ func TestFilterSet_FilteredStatus(t *testing.T) {
 		})
 	}
 }
+
+func mustCompile(t *testing.T, fs analysis.FilterSet) analysis.FilterSet {
+	t.Helper()
+	fs, err := analysis.Compile(fs)
+	require.NoError(t, err, "compiling filter set should not error")
+	return fs
+}
@@ -52,3 +52,35 @@ func WithFiltersFromFile(path string) Option {
 		return WithFilters(fs)(a)
 	}
 }
+
+// WithSuppressions sets the suppression set to ss.
+func WithSuppressions(ss SuppressionSet) Option {
+	return func(a *analyser) error {
+		a.suppressions = ss
+		return nil
+	}
+}
+
+// WithWarningHistory makes the analyser report which warnings aren't present in the history h.
+func WithWarningHistory(h WarningHistory) Option {
+	return func(a *analyser) error {
+		a.warnings = h
+		return nil
+	}
+}
+
+// WithTimingHistory makes the analyser report which subject timings regress against the history h.
+func WithTimingHistory(h TimingHistory) Option {
+	return func(a *analyser) error {
+		a.timings = h
+		return nil
+	}
+}
+
+// WithRegressionPolicy sets the policy the analyser uses to decide whether a timing is a regression.
+func WithRegressionPolicy(p RegressionPolicy) Option {
+	return func(a *analyser) error {
+		a.regressions = p
+		return nil
+	}
+}
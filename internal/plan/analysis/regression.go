@@ -0,0 +1,280 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package analysis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/helper/iohelp"
+)
+
+const (
+	// TimingCompile is the kind of timing key that refers to compile times.
+	TimingCompile = "compile"
+	// TimingRun is the kind of timing key that refers to run times.
+	TimingRun = "run"
+
+	// MaxTimingSamples is the number of most recent samples a timing history keeps for each key.
+	MaxTimingSamples = 20
+
+	// madScale scales a median absolute deviation so that it estimates the standard deviation of normal data.
+	madScale = 1.4826
+)
+
+// TimingKey identifies the compile or run time of one subject on one compiler, at a particular optimisation level.
+type TimingKey struct {
+	// Compiler is the ID of the compiler, as a string.
+	Compiler string `json:"compiler"`
+	// Opt is the name of the optimisation level, if any.
+	Opt string `json:"opt,omitempty"`
+	// Subject is the name of the subject.
+	Subject string `json:"subject"`
+	// Kind is the kind of timing: either TimingCompile or TimingRun.
+	Kind string `json:"kind"`
+}
+
+// String gets a human-readable representation of this key.
+func (k TimingKey) String() string {
+	if k.Opt == "" {
+		return fmt.Sprintf("%s: %s %s", k.Compiler, k.Subject, k.Kind)
+	}
+	return fmt.Sprintf("%s (opt %q): %s %s", k.Compiler, k.Opt, k.Subject, k.Kind)
+}
+
+// less orders timing keys by compiler, then opt, then subject, then kind.
+func (k TimingKey) less(k2 TimingKey) bool {
+	if k.Compiler != k2.Compiler {
+		return k.Compiler < k2.Compiler
+	}
+	if k.Opt != k2.Opt {
+		return k.Opt < k2.Opt
+	}
+	if k.Subject != k2.Subject {
+		return k.Subject < k2.Subject
+	}
+	return k.Kind < k2.Kind
+}
+
+// TimingSample is a single compile or run time observed in an analysis.
+type TimingSample struct {
+	TimingKey
+	// Time is the observed time.
+	Time time.Duration
+}
+
+// TimingSamples gets the per-subject compile and run times in the analysis, in ascending key order.
+func (a *Analysis) TimingSamples() []TimingSample {
+	var ss []TimingSample
+	for cid, c := range a.Compilers {
+		k := TimingKey{Compiler: cid.String(), Opt: c.Info.SelectedOptName()}
+		ss = appendTimingSamples(ss, k, TimingCompile, c.SubjectTimes)
+		ss = appendTimingSamples(ss, k, TimingRun, c.SubjectRunTimes)
+	}
+	sort.Slice(ss, func(i, j int) bool { return ss[i].less(ss[j].TimingKey) })
+	return ss
+}
+
+func appendTimingSamples(ss []TimingSample, k TimingKey, kind string, ts map[string]time.Duration) []TimingSample {
+	k.Kind = kind
+	for sname, t := range ts {
+		k.Subject = sname
+		ss = append(ss, TimingSample{TimingKey: k, Time: t})
+	}
+	return ss
+}
+
+// TimingRecord records the most recent times seen for a timing key.
+type TimingRecord struct {
+	TimingKey
+	// Samples contains up to MaxTimingSamples of the most recent times, oldest first.
+	Samples []time.Duration `json:"samples"`
+	// LastSeen is the creation time of the last plan in which the key was seen.
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// TimingHistory is a history of the per-subject compile and run times seen across analyses.
+type TimingHistory []TimingRecord
+
+// Samples gets the historical times for the key k, oldest first.
+func (h TimingHistory) Samples(k TimingKey) []time.Duration {
+	for _, r := range h {
+		if r.TimingKey == k {
+			return r.Samples
+		}
+	}
+	return nil
+}
+
+// Record records the samples ss as having been seen in a plan created at time t, returning the new history.
+func (h TimingHistory) Record(ss []TimingSample, t time.Time) TimingHistory {
+	idx := make(map[TimingKey]int, len(h))
+	for i, r := range h {
+		idx[r.TimingKey] = i
+	}
+	for _, s := range ss {
+		i, ok := idx[s.TimingKey]
+		if !ok {
+			i = len(h)
+			idx[s.TimingKey] = i
+			h = append(h, TimingRecord{TimingKey: s.TimingKey})
+		}
+		h[i].Samples = append(h[i].Samples, s.Time)
+		if n := len(h[i].Samples); MaxTimingSamples < n {
+			h[i].Samples = h[i].Samples[n-MaxTimingSamples:]
+		}
+		h[i].LastSeen = t
+	}
+	sort.Slice(h, func(i, j int) bool { return h[i].less(h[j].TimingKey) })
+	return h
+}
+
+// LoadTimingHistory loads a timing history from the JSON file at path.
+// If the file doesn't exist, the history is empty.
+func LoadTimingHistory(path string) (TimingHistory, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return TimingHistory{}, nil
+		}
+		return nil, err
+	}
+	var h TimingHistory
+	derr := json.NewDecoder(f).Decode(&h)
+	cerr := f.Close()
+	return h, errhelp.FirstError(derr, cerr)
+}
+
+// Write atomically replaces the JSON file at path with this timing history.
+func (h TimingHistory) Write(path string) error {
+	return iohelp.WriteJSONFileAtomic(path, h)
+}
+
+// TimingDB is a timing history persisted to a file, which can safely be shared between concurrent analyses.
+type TimingDB struct {
+	mu      sync.Mutex
+	path    string
+	history TimingHistory
+}
+
+// OpenTimingDB opens the timing database at path, which need not yet exist.
+func OpenTimingDB(path string) (*TimingDB, error) {
+	h, err := LoadTimingHistory(path)
+	if err != nil {
+		return nil, err
+	}
+	return &TimingDB{path: path, history: h}, nil
+}
+
+// History gets a copy of the current timing history.
+func (d *TimingDB) History() TimingHistory {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append(make(TimingHistory, 0, len(d.history)), d.history...)
+}
+
+// Record records the samples ss, from a plan created at time t, and writes the database back to disk.
+func (d *TimingDB) Record(ss []TimingSample, t time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.history = d.history.Record(ss, t)
+	return d.history.Write(d.path)
+}
+
+// RegressionPolicy decides when a time counts as a regression against its history.
+type RegressionPolicy struct {
+	// MinSamples is the number of historical samples needed before we check a key for regressions.
+	MinSamples int
+	// MinRatio is the minimum ratio of a time to its historical median for it to count as a regression.
+	MinRatio float64
+	// MinTime is the minimum time that can count as a regression; shorter times are too noisy to judge.
+	MinTime time.Duration
+	// Deviations is the number of (estimated) standard deviations above the historical median a time must be to count
+	// as a regression, where the standard deviation is estimated from the median absolute deviation.
+	Deviations float64
+}
+
+// DefaultRegressionPolicy is the regression policy used if none is given.
+var DefaultRegressionPolicy = RegressionPolicy{
+	MinSamples: 5,
+	MinRatio:   10,
+	MinTime:    100 * time.Millisecond,
+	Deviations: 3,
+}
+
+// Regression is a compile or run time that was significantly longer than its historical median.
+type Regression struct {
+	TimingKey
+	// Time is the time seen in this analysis.
+	Time time.Duration `json:"time"`
+	// Median is the historical median time.
+	Median time.Duration `json:"median"`
+	// Ratio is the ratio of Time to Median.
+	Ratio float64 `json:"ratio"`
+}
+
+// String gets a human-readable representation of this regression.
+func (r Regression) String() string {
+	return fmt.Sprintf("%s took %s, %.1fx its median of %s", r.TimingKey, r.Time, r.Ratio, r.Median)
+}
+
+// Check checks whether the sample s regresses against the historical times hist under this policy.
+func (p RegressionPolicy) Check(s TimingSample, hist []time.Duration) (Regression, bool) {
+	if len(hist) == 0 || len(hist) < p.MinSamples || s.Time < p.MinTime {
+		return Regression{}, false
+	}
+	med := medianDuration(hist)
+	if med <= 0 {
+		return Regression{}, false
+	}
+	ratio := float64(s.Time) / float64(med)
+	if ratio < p.MinRatio {
+		return Regression{}, false
+	}
+	devs := make([]time.Duration, len(hist))
+	for i, h := range hist {
+		devs[i] = absDuration(h - med)
+	}
+	if float64(s.Time-med) <= p.Deviations*madScale*float64(medianDuration(devs)) {
+		return Regression{}, false
+	}
+	return Regression{TimingKey: s.TimingKey, Time: s.Time, Median: med, Ratio: ratio}, true
+}
+
+// Regressions checks each sample in ss against the history h under this policy, returning any regressions.
+func (p RegressionPolicy) Regressions(ss []TimingSample, h TimingHistory) []Regression {
+	rs := []Regression{}
+	for _, s := range ss {
+		if r, ok := p.Check(s, h.Samples(s.TimingKey)); ok {
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
+
+func medianDuration(ds []time.Duration) time.Duration {
+	sorted := append([]time.Duration(nil), ds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package analysis_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// timingHistory makes a history in which every key in ks has been seen n times, taking d each time.
+func timingHistory(n int, d time.Duration, ks ...analysis.TimingKey) analysis.TimingHistory {
+	var h analysis.TimingHistory
+	t := time.Date(2011, time.November, 10, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		ss := make([]analysis.TimingSample, len(ks))
+		for j, k := range ks {
+			ss[j] = analysis.TimingSample{TimingKey: k, Time: d}
+		}
+		h = h.Record(ss, t.Add(time.Duration(i)*time.Hour))
+	}
+	return h
+}
+
+// TestAnalysis_TimingSamples tests that the analyser records per-subject compile times.
+func TestAnalysis_TimingSamples(t *testing.T) {
+	t.Parallel()
+
+	crp, err := analysis.Analyse(context.Background(), plan.Mock())
+	require.NoError(t, err, "unexpected error analysing")
+
+	assert.Nil(t, crp.Regressions, "no history, so no regressions")
+	assert.Equal(t,
+		[]analysis.TimingSample{
+			{TimingKey: analysis.TimingKey{Compiler: "clang", Subject: "bar", Kind: analysis.TimingCompile}, Time: 200 * time.Second},
+			{TimingKey: analysis.TimingKey{Compiler: "gcc", Subject: "baz", Kind: analysis.TimingCompile}, Time: 200 * time.Second},
+		},
+		crp.TimingSamples(),
+		"wrong timing samples")
+}
+
+// TestAnalyse_regressions tests that the analyser reports timings that regress against its timing history.
+func TestAnalyse_regressions(t *testing.T) {
+	t.Parallel()
+
+	kbar := analysis.TimingKey{Compiler: "clang", Subject: "bar", Kind: analysis.TimingCompile}
+	kbaz := analysis.TimingKey{Compiler: "gcc", Subject: "baz", Kind: analysis.TimingCompile}
+	h := timingHistory(5, 10*time.Second, kbar).Record(
+		[]analysis.TimingSample{{TimingKey: kbaz, Time: 150 * time.Second}},
+		time.Date(2011, time.November, 11, 0, 0, 0, 0, time.UTC),
+	)
+
+	crp, err := analysis.Analyse(context.Background(), plan.Mock(), analysis.WithTimingHistory(h))
+	require.NoError(t, err, "unexpected error analysing")
+
+	assert.Equal(t,
+		[]analysis.Regression{{TimingKey: kbar, Time: 200 * time.Second, Median: 10 * time.Second, Ratio: 20}},
+		crp.Regressions,
+		"only bar has enough history to regress")
+
+	t.Run("ratio", func(t *testing.T) {
+		t.Parallel()
+		p := analysis.DefaultRegressionPolicy
+		p.MinRatio = 50
+		crp, err := analysis.Analyse(context.Background(), plan.Mock(),
+			analysis.WithTimingHistory(h), analysis.WithRegressionPolicy(p))
+		require.NoError(t, err, "unexpected error analysing")
+		assert.Empty(t, crp.Regressions, "20x shouldn't count at a minimum ratio of 50x")
+	})
+}
+
+// TestRegressionPolicy_Check tests RegressionPolicy.Check on the default policy.
+func TestRegressionPolicy_Check(t *testing.T) {
+	t.Parallel()
+
+	sec := func(ns ...int) []time.Duration {
+		ds := make([]time.Duration, len(ns))
+		for i, n := range ns {
+			ds[i] = time.Duration(n) * time.Second
+		}
+		return ds
+	}
+
+	// The deviation check only matters at ratios low enough for noise to reach them.
+	loose := analysis.DefaultRegressionPolicy
+	loose.MinRatio = 2
+
+	cases := map[string]struct {
+		policy *analysis.RegressionPolicy
+		time   time.Duration
+		hist   []time.Duration
+		want   bool
+	}{
+		"no-history":    {time: 100 * time.Second, hist: nil, want: false},
+		"few-samples":   {time: 100 * time.Second, hist: sec(1, 1, 1, 1), want: false},
+		"steady":        {time: 1 * time.Second, hist: sec(1, 1, 1, 1, 1), want: false},
+		"blowup":        {time: 100 * time.Second, hist: sec(1, 1, 1, 1, 1), want: true},
+		"small-ratio":   {time: 9 * time.Second, hist: sec(1, 1, 1, 1, 1), want: false},
+		"too-short":     {time: 50 * time.Millisecond, hist: []time.Duration{1, 1, 1, 1, 1}, want: false},
+		"noisy":         {policy: &loose, time: 30 * time.Second, hist: sec(2, 5, 10, 20, 40), want: false},
+		"noisy-blowup":  {policy: &loose, time: 60 * time.Second, hist: sec(2, 5, 10, 20, 40), want: true},
+		"zero-median":   {time: 100 * time.Second, hist: []time.Duration{0, 0, 0, 0, 0}, want: false},
+		"median-of-six": {time: 100 * time.Second, hist: sec(1, 1, 1, 2, 2, 2), want: true},
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s := analysis.TimingSample{TimingKey: analysis.TimingKey{Compiler: "gcc", Subject: "foo"}, Time: c.time}
+			p := analysis.DefaultRegressionPolicy
+			if c.policy != nil {
+				p = *c.policy
+			}
+			r, ok := p.Check(s, c.hist)
+			assert.Equal(t, c.want, ok, "wrong regression verdict")
+			if ok {
+				assert.Equal(t, s.TimingKey, r.TimingKey, "regression should keep the sample's key")
+				assert.Equal(t, float64(c.time)/float64(r.Median), r.Ratio, "ratio should be against median")
+			}
+		})
+	}
+}
+
+// TestTimingDB_Record tests that the timing database persists, and bounds, its history.
+func TestTimingDB_Record(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "timings.json")
+	db, err := analysis.OpenTimingDB(path)
+	require.NoError(t, err, "opening nonexistent database should create an empty one")
+	assert.Empty(t, db.History())
+
+	k := analysis.TimingKey{Compiler: "gcc", Opt: "3", Subject: "foo", Kind: analysis.TimingRun}
+	t0 := time.Date(2011, time.November, 11, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < analysis.MaxTimingSamples+5; i++ {
+		s := analysis.TimingSample{TimingKey: k, Time: time.Duration(i) * time.Second}
+		require.NoError(t, db.Record([]analysis.TimingSample{s}, t0.Add(time.Duration(i)*time.Hour)))
+	}
+
+	db2, err := analysis.OpenTimingDB(path)
+	require.NoError(t, err, "reopening database")
+	h := db2.History()
+	require.Len(t, h, 1)
+	ss := h.Samples(k)
+	require.Len(t, ss, analysis.MaxTimingSamples, "history should keep only the most recent samples")
+	assert.Equal(t, 5*time.Second, ss[0], "oldest samples should be dropped first")
+	assert.True(t, h[0].LastSeen.Equal(t0.Add(time.Duration(analysis.MaxTimingSamples+4)*time.Hour)))
+}
+
+// ExampleRegression_String is a runnable example for Regression.String.
+func ExampleRegression_String() {
+	r := analysis.Regression{
+		TimingKey: analysis.TimingKey{Compiler: "gcc", Opt: "3", Subject: "foo", Kind: analysis.TimingCompile},
+		Time:      12 * time.Second,
+		Median:    time.Second,
+		Ratio:     12,
+	}
+	fmt.Println(r)
+
+	// Output:
+	// gcc (opt "3"): foo compile took 12s, 12.0x its median of 1s
+}
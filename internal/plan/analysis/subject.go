@@ -17,6 +17,7 @@ import (
 
 	"github.com/c4-project/c4t/internal/subject/compilation"
 
+	"github.com/c4-project/c4t/internal/subject/obs"
 	"github.com/c4-project/c4t/internal/subject/status"
 
 	"github.com/c4-project/c4t/internal/subject"
@@ -29,29 +30,37 @@ type subjectAnalysis struct {
 	cflags       map[id.ID]status.Flag
 	ctimes       map[id.ID][]time.Duration
 	clogs        map[id.ID]string
+	cwarns       map[id.ID][]compiler.Diagnostic
+	sigs         map[id.ID]Signature
+	suppressed   map[id.ID]int
+	features     []string
 	rtimes       map[id.ID][]time.Duration
 	cspan, rspan timing.Span
 }
 
 func newSubjectAnalysis(s subject.Named) subjectAnalysis {
 	return subjectAnalysis{
-		flags:  0,
-		cflags: map[id.ID]status.Flag{},
-		clogs:  map[id.ID]string{},
-		ctimes: map[id.ID][]time.Duration{},
-		rtimes: map[id.ID][]time.Duration{},
-		sub:    s,
+		flags:      0,
+		cflags:     map[id.ID]status.Flag{},
+		clogs:      map[id.ID]string{},
+		cwarns:     map[id.ID][]compiler.Diagnostic{},
+		sigs:       map[id.ID]Signature{},
+		suppressed: map[id.ID]int{},
+		ctimes:     map[id.ID][]time.Duration{},
+		rtimes:     map[id.ID][]time.Duration{},
+		sub:        s,
 	}
 }
 
 // analyseSubject analyses the named subject s, using the compiler information ccs.
 func (a *analyser) analyseSubject(s subject.Named) subjectAnalysis {
 	c := newSubjectAnalysis(s)
-	c.classifyCompilations(s.Compilations, a.analysis.Plan.Compilers, a.filters)
+	c.features = subjectFeatures(s.Subject)
+	c.classifyCompilations(s.Compilations, a.analysis.Plan.Compilers, a.filters, a.suppressions)
 	return c
 }
 
-func (c *subjectAnalysis) classifyCompilations(crs compilation.Map, ccs compiler.InstanceMap, fs FilterSet) {
+func (c *subjectAnalysis) classifyCompilations(crs compilation.Map, ccs compiler.InstanceMap, fs FilterSet, ss SuppressionSet) {
 	for cid, cm := range crs {
 		conf := ccs[cid]
 
@@ -61,11 +70,47 @@ func (c *subjectAnalysis) classifyCompilations(crs compilation.Map, ccs compiler
 		if cm.Run != nil {
 			c.classifyRun(cid, cm.Run)
 		}
+		c.sign(cid, cm, conf, ss)
+	}
+}
+
+// sign records the failure signature of the compilation cm by compiler conf, if it had a bad outcome.
+// If the signature matches a suppression in ss, we instead move the compilation into the filtered status.
+func (c *subjectAnalysis) sign(cid id.ID, cm compilation.Compilation, conf compiler.Instance, ss SuppressionSet) {
+	st := c.cflags[cid].Status()
+	if !st.IsBad() {
+		return
+	}
+	var o *obs.Obs
+	if cm.Run != nil {
+		o = cm.Run.Obs
+	}
+	sig := NewSignature(conf, st, c.clogs[cid], o)
+	if i := ss.Match(sig); 0 <= i {
+		c.suppress(cid, i)
+		return
+	}
+	c.sigs[cid] = sig
+}
+
+// suppress moves the compilation with compiler ID cid into the filtered status, crediting suppression i.
+func (c *subjectAnalysis) suppress(cid id.ID, i int) {
+	c.suppressed[cid] = i
+	c.cflags[cid] = status.FlagFiltered
+	c.flags = 0
+	for _, f := range c.cflags {
+		c.flags |= f
 	}
 }
 
 func (c *subjectAnalysis) classifyCompiler(cid id.ID, cm *compilation.CompileResult, conf compiler.Instance, fs FilterSet) {
 	c.clogs[cid] = c.compileLog(cm)
+	ws, err := ParseWarnings(c.clogs[cid])
+	if err != nil {
+		// Keep the warnings before the problem, but make sure the log shows that there may be more.
+		c.clogs[cid] += fmt.Sprintf("\n(ERROR PARSING COMPILE LOG: %s)", err)
+	}
+	c.cwarns[cid] = ws
 	st, err := fs.FilteredStatus(cm.Status, conf, c.clogs[cid])
 	if err != nil {
 		// TODO(@MattWindsor91): do something about this!!
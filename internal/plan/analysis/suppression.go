@@ -0,0 +1,259 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package analysis
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/helper/iohelp"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNoFingerprint occurs when a suppression doesn't name the fingerprint of the failure it suppresses.
+var ErrNoFingerprint = errors.New("suppression has no fingerprint")
+
+// Suppression records a known bug, and suppresses failures whose signature matches it.
+//
+// Unlike a Filter, which only matches compiler errors, a suppression can match any bad outcome that has a signature,
+// including flagged observations, timeouts, and run failures.  Matching compilations are moved into the 'filtered'
+// status.
+type Suppression struct {
+	// Fingerprint is the fingerprint of the failure signature being suppressed.
+	Fingerprint string `yaml:"fingerprint"`
+	// Bug is a reference to the bug-tracker entry for the underlying bug.
+	Bug string `yaml:"bug,omitempty"`
+	// Date is the date on which the suppression was added.
+	Date time.Time `yaml:"date,omitempty"`
+	// Note is a free-form description of the suppression.
+	Note string `yaml:"note,omitempty"`
+	// LastHit is the creation time of the last plan in which the suppression matched a failure.
+	// c4t maintains this in a separate state file (see SuppressionStatePath); a value here only seeds that file.
+	LastHit time.Time `yaml:"last_hit,omitempty"`
+	// Misses is the number of analyses since the suppression last matched a failure.
+	// c4t maintains this in a separate state file (see SuppressionStatePath); a value here only seeds that file.
+	Misses int `yaml:"misses,omitempty"`
+}
+
+// State gets the hit tracking state of this suppression.
+func (s Suppression) State() SuppressionState {
+	return SuppressionState{LastHit: s.LastHit, Misses: s.Misses}
+}
+
+// SuppressionState is c4t's record of how recently a suppression matched a failure.
+type SuppressionState struct {
+	// LastHit is the creation time of the last plan in which the suppression matched a failure.
+	LastHit time.Time `json:"last_hit"`
+	// Misses is the number of analyses since the suppression last matched a failure.
+	Misses int `json:"misses,omitempty"`
+}
+
+// SuppressionStates maps suppression fingerprints to their hit tracking states.
+type SuppressionStates map[string]SuppressionState
+
+// SuppressionStatePath gets the path of the state file in which c4t tracks hits of the suppressions in the file at
+// path.
+//
+// c4t never writes to suppression files themselves, as they are hand-written and may contain comments.
+func SuppressionStatePath(path string) string {
+	return path + ".state.json"
+}
+
+// LoadSuppressionStates loads suppression states from the JSON file at path.
+// If the file doesn't exist, we return an empty map.
+func LoadSuppressionStates(path string) (SuppressionStates, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return SuppressionStates{}, nil
+		}
+		return nil, err
+	}
+	var st SuppressionStates
+	derr := json.NewDecoder(f).Decode(&st)
+	cerr := f.Close()
+	if st == nil {
+		st = SuppressionStates{}
+	}
+	return st, errhelp.FirstError(derr, cerr)
+}
+
+// Write atomically replaces the JSON file at path with these suppression states.
+func (st SuppressionStates) Write(path string) error {
+	return iohelp.WriteJSONFileAtomic(path, st)
+}
+
+// SuppressionSet is a set of suppressions, along with the criterion for considering them stale.
+type SuppressionSet struct {
+	// StaleAfter is the number of consecutive analyses after which a suppression that has not matched any failure is
+	// reported as stale.  If zero, suppressions are never stale.
+	StaleAfter int `yaml:"stale_after,omitempty"`
+	// Suppressions is the list of suppressions in this set.
+	Suppressions []Suppression `yaml:"suppressions"`
+}
+
+// ReadSuppressionSet reads a suppression set from the YAML reader r.
+func ReadSuppressionSet(r io.Reader) (SuppressionSet, error) {
+	var ss SuppressionSet
+	if err := yaml.NewDecoder(r).Decode(&ss); err != nil && !errors.Is(err, io.EOF) {
+		return ss, err
+	}
+	for _, s := range ss.Suppressions {
+		if s.Fingerprint == "" {
+			return ss, ErrNoFingerprint
+		}
+	}
+	return ss, nil
+}
+
+// LoadSuppressionSet loads a suppression set from the filepath fpath.
+func LoadSuppressionSet(fpath string) (SuppressionSet, error) {
+	f, err := os.Open(fpath)
+	if err != nil {
+		return SuppressionSet{}, err
+	}
+	ss, rerr := ReadSuppressionSet(f)
+	cerr := f.Close()
+	return ss, errhelp.FirstError(rerr, cerr)
+}
+
+// Write writes this suppression set as YAML to w.
+func (s SuppressionSet) Write(w io.Writer) error {
+	e := yaml.NewEncoder(w)
+	e.SetIndent(2)
+	eerr := e.Encode(s)
+	cerr := e.Close()
+	return errhelp.FirstError(eerr, cerr)
+}
+
+// Match gets the index of the suppression in this set that matches sig, or -1 if there isn't one.
+func (s SuppressionSet) Match(sig Signature) int {
+	fp := sig.Fingerprint()
+	for i, sup := range s.Suppressions {
+		if sup.Fingerprint == fp {
+			return i
+		}
+	}
+	return -1
+}
+
+// ApplyStates replaces the hit tracking of each suppression in this set that has a state in st.
+func (s SuppressionSet) ApplyStates(st SuppressionStates) {
+	for i := range s.Suppressions {
+		sup := &s.Suppressions[i]
+		if state, ok := st[sup.Fingerprint]; ok {
+			sup.LastHit = state.LastHit
+			sup.Misses = state.Misses
+		}
+	}
+}
+
+// States gets the hit tracking state of each suppression in this set.
+func (s SuppressionSet) States() SuppressionStates {
+	st := make(SuppressionStates, len(s.Suppressions))
+	for _, sup := range s.Suppressions {
+		st[sup.Fingerprint] = sup.State()
+	}
+	return st
+}
+
+// Record updates the hit tracking of this set's suppressions given the reports rs for an analysis of a plan
+// created at time t.
+func (s SuppressionSet) Record(rs []SuppressionReport, t time.Time) {
+	hits := make(map[string]int, len(rs))
+	for _, r := range rs {
+		hits[r.Fingerprint] += len(r.Hits)
+	}
+	for i := range s.Suppressions {
+		sup := &s.Suppressions[i]
+		nhits, ok := hits[sup.Fingerprint]
+		switch {
+		case !ok:
+			// This suppression wasn't in effect for the analysis.
+		case nhits == 0:
+			sup.Misses++
+		default:
+			sup.Misses = 0
+			sup.LastHit = t
+		}
+	}
+}
+
+// SuppressionReport reports the effect of a suppression on an analysis.
+type SuppressionReport struct {
+	Suppression
+
+	// Hits lists the compilations suppressed, in ascending order.
+	Hits []compilation.Name
+
+	// Stale is true if the suppression hasn't matched any failure in its set's staleness period.
+	Stale bool
+}
+
+// newSuppressionReports makes empty reports for each suppression in ss.
+func newSuppressionReports(ss SuppressionSet) []SuppressionReport {
+	rs := make([]SuppressionReport, len(ss.Suppressions))
+	for i, s := range ss.Suppressions {
+		rs[i].Suppression = s
+	}
+	return rs
+}
+
+// finishSuppressionReports works out staleness for the reports rs, given the criterion in ss.
+func finishSuppressionReports(rs []SuppressionReport, ss SuppressionSet) {
+	for i := range rs {
+		rs[i].Stale = 0 < ss.StaleAfter && len(rs[i].Hits) == 0 && ss.StaleAfter <= rs[i].Misses+1
+	}
+}
+
+// SuppressionDB is a suppression set loaded from a file, along with hit tracking persisted to a separate state file.
+// It can safely be shared between concurrent analyses.
+type SuppressionDB struct {
+	mu        sync.Mutex
+	statePath string
+	set       SuppressionSet
+}
+
+// OpenSuppressionDB opens the suppression database at path, along with its state file, which need not yet exist.
+func OpenSuppressionDB(path string) (*SuppressionDB, error) {
+	ss, err := LoadSuppressionSet(path)
+	if err != nil {
+		return nil, err
+	}
+	statePath := SuppressionStatePath(path)
+	st, err := LoadSuppressionStates(statePath)
+	if err != nil {
+		return nil, err
+	}
+	ss.ApplyStates(st)
+	return &SuppressionDB{statePath: statePath, set: ss}, nil
+}
+
+// Set gets a copy of the current suppression set.
+func (d *SuppressionDB) Set() SuppressionSet {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	ss := d.set
+	ss.Suppressions = append([]Suppression(nil), d.set.Suppressions...)
+	return ss
+}
+
+// Record records the suppression reports rs, from a plan created at time t, and writes the hit tracking back to the
+// database's state file.
+//
+// The suppression file itself is never written.
+func (d *SuppressionDB) Record(rs []SuppressionReport, t time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.set.Record(rs, t)
+	return d.set.States().Write(d.statePath)
+}
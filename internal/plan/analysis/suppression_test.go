@@ -0,0 +1,100 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package analysis_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/c4-project/c4t/internal/helper/testhelp"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAnalyse_suppressions tests that suppressions move matching failures into the filtered status.
+func TestAnalyse_suppressions(t *testing.T) {
+	t.Parallel()
+
+	ss, err := analysis.LoadSuppressionSet(filepath.Join("testdata", "suppressions.yaml"))
+	require.NoError(t, err, "loading suppressions")
+
+	crp, err := analysis.Analyse(context.Background(), plan.Mock(), analysis.WithSuppressions(ss))
+	require.NoError(t, err, "unexpected error analysing")
+
+	assert.Contains(t, crp.ByStatus[status.Filtered], "bar", "bar should have been suppressed")
+	assert.NotContains(t, crp.ByStatus[status.CompileFail], "bar", "bar should have been suppressed out of compilefail")
+	assert.Contains(t, crp.ByStatus[status.Flagged], "baz", "baz should still be flagged on gcc")
+	assert.Len(t, crp.Clusters, 2, "suppressed failures shouldn't be clustered")
+
+	require.Len(t, crp.Suppressions, 3, "wrong number of suppression reports")
+	assert.Equal(t,
+		[]compilation.Name{{SubjectName: "bar", CompilerID: id.FromString("gcc")}},
+		crp.Suppressions[0].Hits,
+		"wrong hits for first suppression")
+	assert.Equal(t,
+		[]compilation.Name{{SubjectName: "baz", CompilerID: id.FromString("icc")}},
+		crp.Suppressions[1].Hits,
+		"wrong hits for second suppression")
+
+	stale := crp.StaleSuppressions()
+	require.Len(t, stale, 1, "wrong number of stale suppressions")
+	assert.Equal(t, "0123456789abcdef", stale[0].Fingerprint, "wrong stale suppression")
+}
+
+// TestReadSuppressionSet_noFingerprint tests that suppressions without fingerprints are rejected.
+func TestReadSuppressionSet_noFingerprint(t *testing.T) {
+	t.Parallel()
+
+	_, err := analysis.ReadSuppressionSet(strings.NewReader("suppressions:\n  - bug: https://example.com/bugs/1\n"))
+	testhelp.ExpectErrorIs(t, err, analysis.ErrNoFingerprint, "reading suppression set")
+}
+
+// TestSuppressionDB_Record tests that recording an analysis updates and persists hit tracking, without touching the
+// suppression file.
+func TestSuppressionDB_Record(t *testing.T) {
+	t.Parallel()
+
+	src, err := os.ReadFile(filepath.Join("testdata", "suppressions.yaml"))
+	require.NoError(t, err, "reading suppressions")
+	path := filepath.Join(t.TempDir(), "suppressions.yaml")
+	require.NoError(t, os.WriteFile(path, src, 0o644), "copying suppressions")
+
+	p := plan.Mock()
+	for i := 0; i < 2; i++ {
+		// Reopening the database each time checks that the hit tracking persists.
+		db, err := analysis.OpenSuppressionDB(path)
+		require.NoError(t, err, "opening suppression database")
+		crp, err := analysis.Analyse(context.Background(), p, analysis.WithSuppressions(db.Set()))
+		require.NoError(t, err, "unexpected error analysing")
+		require.NoError(t, db.Record(crp.Suppressions, p.Metadata.Creation), "recording suppressions")
+	}
+
+	got, err := os.ReadFile(path)
+	require.NoError(t, err, "rereading suppressions")
+	assert.Equal(t, string(src), string(got), "suppression file should be untouched")
+
+	db, err := analysis.OpenSuppressionDB(path)
+	require.NoError(t, err, "reopening suppression database")
+	ss := db.Set()
+	require.Len(t, ss.Suppressions, 3, "wrong number of suppressions")
+	assert.True(t, p.Metadata.Creation.Equal(ss.Suppressions[0].LastHit), "last hit not recorded")
+	assert.Zero(t, ss.Suppressions[0].Misses, "hit suppression should have no misses")
+	assert.Equal(t, 6, ss.Suppressions[2].Misses, "misses not recorded")
+	assert.True(t, ss.Suppressions[2].LastHit.Equal(time.Time{}), "missed suppression shouldn't have a last hit")
+
+	st, err := analysis.LoadSuppressionStates(analysis.SuppressionStatePath(path))
+	require.NoError(t, err, "loading suppression states")
+	assert.Equal(t, 6, st["0123456789abcdef"].Misses, "state file has wrong misses")
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package analysis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/helper/iohelp"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+)
+
+// ParseWarnings parses the warnings out of the compiler log clog.
+//
+// This includes any errors that were promoted from warnings with `-Werror`.
+// As with compiler.ParseDiagnostics, ParseWarnings returns the warnings parsed so far alongside any error.
+func ParseWarnings(clog string) ([]compiler.Diagnostic, error) {
+	ds, err := compiler.ParseDiagnostics(clog)
+	var ws []compiler.Diagnostic
+	for _, d := range ds {
+		if d.Severity == compiler.SeverityWarning || d.WarningFlag() != "" {
+			ws = append(ws, d)
+		}
+	}
+	return ws, err
+}
+
+// WarningKind gets the kind of the warning d: its canonical flag if it has one, or its normalised message otherwise.
+func WarningKind(d compiler.Diagnostic) string {
+	if f := d.WarningFlag(); f != "" {
+		return f
+	}
+	return NormaliseLog(d.Message)
+}
+
+// Warning aggregates the occurrences of one kind of warning from one compiler.
+type Warning struct {
+	// Kind is the kind of warning, as returned by WarningKind.
+	Kind string
+	// Count is the number of times the warning was raised.
+	Count int
+	// Subjects lists, in ascending order, the subjects whose compilations raised the warning.
+	Subjects []string
+	// Example is one occurrence of the warning.
+	Example compiler.Diagnostic
+}
+
+// WarningSet maps warning kinds to aggregated warnings.
+type WarningSet map[string]Warning
+
+// Add adds the warning d, raised by the compilation of subject sname, to this set.
+func (ws WarningSet) Add(sname string, d compiler.Diagnostic) {
+	k := WarningKind(d)
+	w, ok := ws[k]
+	if !ok {
+		w = Warning{Kind: k, Example: d}
+	}
+	w.Count++
+	if i := sort.SearchStrings(w.Subjects, sname); i == len(w.Subjects) || w.Subjects[i] != sname {
+		w.Subjects = append(w.Subjects, "")
+		copy(w.Subjects[i+1:], w.Subjects[i:])
+		w.Subjects[i] = sname
+	}
+	ws[k] = w
+}
+
+// Sorted gets the warnings in this set in descending order of count, then ascending order of kind.
+func (ws WarningSet) Sorted() []Warning {
+	sorted := make([]Warning, 0, len(ws))
+	for _, w := range ws {
+		sorted = append(sorted, w)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Kind < sorted[j].Kind
+	})
+	return sorted
+}
+
+// WarningKey identifies a kind of warning raised by a compiler at a particular optimisation level.
+type WarningKey struct {
+	// Compiler is the ID of the compiler, as a string.
+	Compiler string `json:"compiler"`
+	// Opt is the name of the optimisation level, if any.
+	Opt string `json:"opt,omitempty"`
+	// Kind is the kind of warning, as returned by WarningKind.
+	Kind string `json:"kind"`
+}
+
+// String gets a human-readable representation of this key.
+func (k WarningKey) String() string {
+	if k.Opt == "" {
+		return fmt.Sprintf("%s: %s", k.Compiler, k.Kind)
+	}
+	return fmt.Sprintf("%s (opt %q): %s", k.Compiler, k.Opt, k.Kind)
+}
+
+// less orders warning keys by compiler, then opt, then kind.
+func (k WarningKey) less(k2 WarningKey) bool {
+	if k.Compiler != k2.Compiler {
+		return k.Compiler < k2.Compiler
+	}
+	if k.Opt != k2.Opt {
+		return k.Opt < k2.Opt
+	}
+	return k.Kind < k2.Kind
+}
+
+// WarningKeys gets the keys of every warning in the analysis, in ascending order.
+func (a *Analysis) WarningKeys() []WarningKey {
+	var ks []WarningKey
+	for cid, c := range a.Compilers {
+		for k := range c.Warnings {
+			ks = append(ks, WarningKey{Compiler: cid.String(), Opt: c.Info.SelectedOptName(), Kind: k})
+		}
+	}
+	sortWarningKeys(ks)
+	return ks
+}
+
+func sortWarningKeys(ks []WarningKey) {
+	sort.Slice(ks, func(i, j int) bool { return ks[i].less(ks[j]) })
+}
+
+// WarningRecord records when a kind of warning was first and last seen.
+type WarningRecord struct {
+	WarningKey
+	// FirstSeen is the creation time of the first plan in which the warning was seen.
+	FirstSeen time.Time `json:"first_seen"`
+	// LastSeen is the creation time of the last plan in which the warning was seen.
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// WarningHistory is a history of the kinds of warning seen across analyses.
+type WarningHistory []WarningRecord
+
+// Has gets whether the warning key k is in this history.
+func (h WarningHistory) Has(k WarningKey) bool {
+	for _, r := range h {
+		if r.WarningKey == k {
+			return true
+		}
+	}
+	return false
+}
+
+// Record records the warning keys ks as having been seen in a plan created at time t, returning the new history.
+func (h WarningHistory) Record(ks []WarningKey, t time.Time) WarningHistory {
+	idx := make(map[WarningKey]int, len(h))
+	for i, r := range h {
+		idx[r.WarningKey] = i
+	}
+	for _, k := range ks {
+		if i, ok := idx[k]; ok {
+			h[i].LastSeen = t
+			continue
+		}
+		idx[k] = len(h)
+		h = append(h, WarningRecord{WarningKey: k, FirstSeen: t, LastSeen: t})
+	}
+	sort.Slice(h, func(i, j int) bool { return h[i].less(h[j].WarningKey) })
+	return h
+}
+
+// LoadWarningHistory loads a warning history from the JSON file at path.
+// If the file doesn't exist, the history is empty.
+func LoadWarningHistory(path string) (WarningHistory, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return WarningHistory{}, nil
+		}
+		return nil, err
+	}
+	var h WarningHistory
+	derr := json.NewDecoder(f).Decode(&h)
+	cerr := f.Close()
+	return h, errhelp.FirstError(derr, cerr)
+}
+
+// Write atomically replaces the JSON file at path with this warning history.
+func (h WarningHistory) Write(path string) error {
+	return iohelp.WriteJSONFileAtomic(path, h)
+}
+
+// WarningDB is a warning history persisted to a file, which can safely be shared between concurrent analyses.
+type WarningDB struct {
+	mu      sync.Mutex
+	path    string
+	history WarningHistory
+}
+
+// OpenWarningDB opens the warning database at path, which need not yet exist.
+func OpenWarningDB(path string) (*WarningDB, error) {
+	h, err := LoadWarningHistory(path)
+	if err != nil {
+		return nil, err
+	}
+	return &WarningDB{path: path, history: h}, nil
+}
+
+// History gets a copy of the current warning history.
+func (d *WarningDB) History() WarningHistory {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append(make(WarningHistory, 0, len(d.history)), d.history...)
+}
+
+// Record records the warning keys ks, from a plan created at time t, and writes the database back to disk.
+func (d *WarningDB) Record(ks []WarningKey, t time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.history = d.history.Record(ks, t)
+	return d.history.Write(d.path)
+}
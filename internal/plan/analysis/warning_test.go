@@ -0,0 +1,91 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package analysis_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// warningPlan makes a mock plan where gcc raises the warnings in testdata/warnings.log on bar.
+func warningPlan() *plan.Plan {
+	m := plan.Mock()
+	cgcc := m.Corpus["bar"].Compilations[id.FromString("gcc")]
+	cgcc.Compile.Files.Log = filepath.Join("testdata", "warnings.log")
+	m.Corpus["bar"].Compilations[id.FromString("gcc")] = cgcc
+	return m
+}
+
+// TestAnalyse_warnings tests that the analyser aggregates warnings per compiler.
+func TestAnalyse_warnings(t *testing.T) {
+	t.Parallel()
+
+	crp, err := analysis.Analyse(context.Background(), warningPlan())
+	require.NoError(t, err, "unexpected error analysing")
+
+	assert.Nil(t, crp.NewWarnings, "no history, so no new warnings")
+
+	ws := crp.Compilers[id.FromString("gcc")].Warnings.Sorted()
+	require.Len(t, ws, 2, "wrong number of warning kinds")
+	assert.Equal(t, "-Wmaybe-uninitialized", ws[0].Kind)
+	assert.Equal(t, 2, ws[0].Count)
+	assert.Equal(t, []string{"bar"}, ws[0].Subjects)
+	assert.Equal(t, 12, ws[0].Example.Line)
+	assert.Equal(t, "-Wunused-variable", ws[1].Kind)
+	assert.Equal(t, 1, ws[1].Count)
+
+	assert.Empty(t, crp.Compilers[id.FromString("clang")].Warnings, "clang raised no warnings")
+}
+
+// TestAnalyse_newWarnings tests that the analyser reports warnings not in its warning history.
+func TestAnalyse_newWarnings(t *testing.T) {
+	t.Parallel()
+
+	h := analysis.WarningHistory{}.Record(
+		[]analysis.WarningKey{{Compiler: "gcc", Kind: "-Wunused-variable"}},
+		time.Date(2011, time.November, 10, 0, 0, 0, 0, time.UTC),
+	)
+	crp, err := analysis.Analyse(context.Background(), warningPlan(), analysis.WithWarningHistory(h))
+	require.NoError(t, err, "unexpected error analysing")
+
+	assert.Equal(t,
+		[]analysis.WarningKey{{Compiler: "gcc", Kind: "-Wmaybe-uninitialized"}},
+		crp.NewWarnings,
+		"wrong new warnings")
+}
+
+// TestWarningDB_Record tests that the warning database persists its history.
+func TestWarningDB_Record(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "warnings.json")
+	db, err := analysis.OpenWarningDB(path)
+	require.NoError(t, err, "opening nonexistent database should create an empty one")
+	assert.Empty(t, db.History())
+
+	t1 := time.Date(2011, time.November, 11, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+	k1 := analysis.WarningKey{Compiler: "gcc", Opt: "3", Kind: "-Wunused-variable"}
+	k2 := analysis.WarningKey{Compiler: "clang", Kind: "-Wunused-value"}
+	require.NoError(t, db.Record([]analysis.WarningKey{k1}, t1))
+	require.NoError(t, db.Record([]analysis.WarningKey{k1, k2}, t2))
+
+	db2, err := analysis.OpenWarningDB(path)
+	require.NoError(t, err, "reopening database")
+	h := db2.History()
+	require.Len(t, h, 2)
+	assert.Equal(t, k2, h[0].WarningKey, "history should be sorted by compiler")
+	assert.True(t, h[1].FirstSeen.Equal(t1), "first seen time should be kept")
+	assert.True(t, h[1].LastSeen.Equal(t2), "last seen time should be updated")
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package quantity
+
+import (
+	"log"
+
+	"github.com/c4-project/c4t/internal/helper/stringhelp"
+)
+
+// LimitSet contains resource limits to apply to each binary executed by a runner.
+//
+// Zero values disable the corresponding limit.
+// Limits only apply on Linux, where the runner needs util-linux's prlimit(1) to apply them.
+// Only binaries that exceed the CPU time limit get the RunLimit status; exceeding the others makes system calls fail
+// inside the binary, which then usually shows up as a failed run.
+type LimitSet struct {
+	// AddressSpaceMiB is the maximum address space size of each binary, in mebibytes.
+	AddressSpaceMiB int `toml:"address_space_mib,omitzero" json:"address_space_mib,omitempty"`
+
+	// CPUTime is the maximum CPU time each binary may consume.
+	CPUTime Timeout `toml:"cpu_time,omitzero" json:"cpu_time,omitempty"`
+
+	// OpenFiles is the maximum number of files each binary may have open at once.
+	OpenFiles int `toml:"open_files,omitzero" json:"open_files,omitempty"`
+
+	// Processes is the maximum number of processes the running user may have while the binary forks.
+	// As with `ulimit -u`, this counts all processes owned by the user, not just the binary's children.
+	Processes int `toml:"processes,omitzero" json:"processes,omitempty"`
+}
+
+// IsActive checks whether any limit in this set is enabled.
+func (q *LimitSet) IsActive() bool {
+	return 0 < q.AddressSpaceMiB || q.CPUTime.IsActive() || 0 < q.OpenFiles || 0 < q.Processes
+}
+
+// Log logs this limit set to l.
+func (q *LimitSet) Log(l *log.Logger) {
+	if 0 < q.AddressSpaceMiB {
+		l.Printf("address space limited to %d MiB", q.AddressSpaceMiB)
+	}
+	if q.CPUTime.IsActive() {
+		l.Printf("CPU time limited to %s", q.CPUTime)
+	}
+	if 0 < q.OpenFiles {
+		l.Println("limited to", stringhelp.PluralQuantity(q.OpenFiles, "open file", "", "s"))
+	}
+	if 0 < q.Processes {
+		l.Println("limited to", stringhelp.PluralQuantity(q.Processes, "process", "", "es"))
+	}
+}
+
+// Override substitutes any non-zero limits in new for those in this set, in-place.
+func (q *LimitSet) Override(new LimitSet) {
+	GenericOverride(q, new)
+}
@@ -7,6 +7,8 @@ package quantity
 
 import (
 	"log"
+
+	"github.com/c4-project/c4t/internal/helper/stringhelp"
 )
 
 // MachNodeSet contains the tunable quantities for both batch-compiler and batch-runner.
@@ -35,23 +37,61 @@ func (q *MachNodeSet) Override(new MachNodeSet) {
 type BatchSet struct {
 	// Timeout is the timeout for each runner.
 	// Non-positive values disable the timeout.
+	// For the runner, this applies to each run separately, so a subject can take up to NRuns times this long.
 	Timeout Timeout `toml:"timeout,omitzero" json:"timeout,omitempty"`
 
 	// NWorkers is the number of parallel run workers that should be spawned.
 	// Anything less than or equal to 1 will sequentialise the run.
 	NWorkers int `toml:"workers,omitzero" json:"workers,omitempty"`
+
+	// Budget is the target wall-clock time for each run of a binary.
+	// If active, the invoker adapts each binary's iteration parameters to fit, based on the timings of past runs.
+	// This is only meaningful for the runner.
+	Budget Timeout `toml:"budget,omitzero" json:"budget,omitempty"`
+
+	// NRuns is the number of times each binary should be run, with observations aggregated across runs.
+	// Anything less than or equal to 1 runs each binary once.
+	// Each run gets the full Timeout.
+	// This is only meaningful for the runner.
+	NRuns int `toml:"runs,omitzero" json:"runs,omitempty"`
+
+	// PinCPUs, if true, pins each running binary to its own disjoint set of CPUs.
+	// This is only meaningful for the runner, and is only enforced on Linux.
+	PinCPUs bool `toml:"pin_cpus,omitzero" json:"pin_cpus,omitempty"`
+
+	// Limits contains resource limits to apply to each binary.
+	// This is only meaningful for the runner, and is only enforced on Linux.
+	Limits LimitSet `toml:"limits,omitzero" json:"limits,omitempty"`
 }
 
 // Log logs this quantity set to l.
 func (q *BatchSet) Log(l *log.Logger) {
 	LogWorkers(l, q.NWorkers)
 	q.Timeout.Log(l)
+	if q.Budget.IsActive() {
+		l.Printf("budgeting %s per run", q.Budget)
+	}
+	if 1 < q.NRuns {
+		l.Println("running each binary", stringhelp.PluralQuantity(q.NRuns, "time", "", "s"), "(each with its own timeout)")
+	}
+	if q.PinCPUs {
+		l.Println("pinning each worker to its own CPUs")
+	}
+	q.Limits.Log(l)
 }
 
 // Override substitutes any non-zero quantities in new for those in this quantity set, in-place.
 func (q *BatchSet) Override(new BatchSet) {
 	q.Timeout.Override(new.Timeout)
+	q.Budget.Override(new.Budget)
 	if new.NWorkers != 0 {
 		q.NWorkers = new.NWorkers
 	}
+	if new.NRuns != 0 {
+		q.NRuns = new.NRuns
+	}
+	if new.PinCPUs {
+		q.PinCPUs = true
+	}
+	q.Limits.Override(new.Limits)
 }
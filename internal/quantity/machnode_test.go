@@ -94,6 +94,38 @@ func TestMachNodeSet_Override(t *testing.T) {
 				},
 			},
 		},
+		"pin-cpus": {
+			old: quantity.MachNodeSet{
+				Runner: quantity.BatchSet{NWorkers: 8},
+			},
+			new: quantity.MachNodeSet{
+				Runner: quantity.BatchSet{PinCPUs: true},
+			},
+			want: quantity.MachNodeSet{
+				Runner: quantity.BatchSet{NWorkers: 8, PinCPUs: true},
+			},
+		},
+		"limits": {
+			old: quantity.MachNodeSet{
+				Runner: quantity.BatchSet{
+					Limits: quantity.LimitSet{AddressSpaceMiB: 1024, OpenFiles: 32},
+				},
+			},
+			new: quantity.MachNodeSet{
+				Runner: quantity.BatchSet{
+					Limits: quantity.LimitSet{CPUTime: quantity.Timeout(time.Minute), OpenFiles: 64},
+				},
+			},
+			want: quantity.MachNodeSet{
+				Runner: quantity.BatchSet{
+					Limits: quantity.LimitSet{
+						AddressSpaceMiB: 1024,
+						CPUTime:         quantity.Timeout(time.Minute),
+						OpenFiles:       64,
+					},
+				},
+			},
+		},
 	}
 
 	for name, c := range cases {
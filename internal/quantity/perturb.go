@@ -7,6 +7,7 @@ package quantity
 
 import (
 	"log"
+	"sort"
 
 	"github.com/c4-project/c4t/internal/helper/stringhelp"
 )
@@ -18,6 +19,19 @@ type PerturbSet struct {
 	// If nonzero, the corpus will be sampled if larger than the size, and an error occurs if the final size is below
 	// that requested.
 	CorpusSize int `toml:"corpus_size,omitzero" json:"corpus_size,omitempty"`
+
+	// Weighting is the strategy used to weight subjects when sampling the corpus.
+	// If the corpus isn't sampled, the weighting has no effect.
+	Weighting Weighting `toml:"weighting,omitzero" json:"weighting,omitempty"`
+
+	// StratifyBy lists the test features over which to stratify corpus sampling.
+	// If non-empty, the perturber groups subjects into strata by these features, and samples from each stratum
+	// separately so that no one stratum dominates the sample.
+	StratifyBy []Stratum `toml:"stratify_by,omitempty" json:"stratify_by,omitempty"`
+
+	// Quotas maps stratum keys (see StratumKey) to the number of subjects to sample from that stratum.
+	// Strata without quotas share out the rest of the corpus size evenly.
+	Quotas map[string]int `toml:"quotas,omitempty" json:"quotas,omitempty"`
 }
 
 // Override substitutes any quantities in new that are non-zero for those in this set.
@@ -28,4 +42,17 @@ func (q *PerturbSet) Override(new PerturbSet) {
 // Log logs q to l.
 func (q *PerturbSet) Log(l *log.Logger) {
 	l.Println("target corpus size:", stringhelp.PluralQuantity(q.CorpusSize, "subject", "", "s"))
+	l.Println("corpus weighting:", q.Weighting)
+	if len(q.StratifyBy) == 0 {
+		return
+	}
+	l.Println("stratifying by:", q.StratifyBy)
+	keys := make([]string, 0, len(q.Quotas))
+	for k := range q.Quotas {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		l.Printf("quota for %s: %s\n", k, stringhelp.PluralQuantity(q.Quotas[k], "subject", "", "s"))
+	}
 }
@@ -34,6 +34,9 @@ func ExampleRootSet_Log() {
 			},
 			Perturb: quantity.PerturbSet{
 				CorpusSize: 80,
+				Weighting:  quantity.WeightYield,
+				StratifyBy: []quantity.Stratum{quantity.StratumThreads, quantity.StratumRMW},
+				Quotas:     map[string]int{"threads=2,rmw=no": 10},
 			},
 		},
 		Plan: quantity.PlanSet{
@@ -48,6 +51,9 @@ func ExampleRootSet_Log() {
 	// running across 9 workers
 	// [Perturb]
 	// target corpus size: 80 subjects
+	// corpus weighting: yield
+	// stratifying by: [threads rmw]
+	// quota for threads=2,rmw=no: 10 subjects
 	// [Fuzz]
 	// running across 4 workers
 	// fuzzing each subject 5 times
@@ -0,0 +1,91 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package quantity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Stratum is an enumeration of test features over which the perturber can stratify corpus sampling.
+type Stratum uint8
+
+const (
+	// StratumThreads stratifies subjects by their thread count.
+	StratumThreads Stratum = iota
+	// StratumRMW stratifies subjects by whether they contain atomic read-modify-write operations.
+	StratumRMW
+	// StratumMemOrders stratifies subjects by the mix of memory orders they use.
+	StratumMemOrders
+	// NumStratum marks the number of stratum members.
+	NumStratum
+)
+
+var (
+	// ErrBadStratum occurs when we try to marshal/unmarshal a stratum that doesn't exist.
+	ErrBadStratum = errors.New("no such stratum")
+
+	stratumStrings = [NumStratum]string{
+		"threads",
+		"rmw",
+		"mem-orders",
+	}
+)
+
+// StratumOfString tries to get the stratum corresponding to s.
+func StratumOfString(s string) (Stratum, error) {
+	for i := StratumThreads; i < NumStratum; i++ {
+		if strings.EqualFold(stratumStrings[i], s) {
+			return i, nil
+		}
+	}
+	return StratumThreads, fmt.Errorf("%w: %s", ErrBadStratum, s)
+}
+
+// String converts this stratum into a human-readable string.
+func (s Stratum) String() string {
+	ts, err := s.tryString()
+	if err != nil {
+		return "(ERROR)"
+	}
+	return ts
+}
+
+// MarshalText tries to marshal this stratum into text.
+func (s Stratum) MarshalText() ([]byte, error) {
+	ts, err := s.tryString()
+	if err != nil {
+		return []byte{}, err
+	}
+	return []byte(ts), nil
+}
+
+func (s Stratum) tryString() (string, error) {
+	if NumStratum <= s {
+		return "", fmt.Errorf("%w: #%d", ErrBadStratum, s)
+	}
+	return stratumStrings[s], nil
+}
+
+// UnmarshalText tries to unmarshal text into a Stratum.
+func (s *Stratum) UnmarshalText(text []byte) error {
+	var err error
+	*s, err = StratumOfString(string(text))
+	return err
+}
+
+// StratumKey builds the key of the stratum in which a subject falls, given its value for each of the strata in by.
+//
+// Keys take the form `threads=2,rmw=yes`, listing each stratum in the order given in by; this is the form that
+// quotas take in PerturbSet.
+func StratumKey(by []Stratum, value func(Stratum) string) string {
+	parts := make([]string, len(by))
+	for i, s := range by {
+		parts[i] = s.String() + "=" + value(s)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package quantity_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ExampleStratumKey is a runnable example for StratumKey.
+func ExampleStratumKey() {
+	by := []quantity.Stratum{quantity.StratumThreads, quantity.StratumMemOrders}
+	fmt.Println(quantity.StratumKey(by, func(s quantity.Stratum) string {
+		if s == quantity.StratumThreads {
+			return "2"
+		}
+		return "ra+rlx"
+	}))
+
+	// Output:
+	// threads=2,mem-orders=ra+rlx
+}
+
+// TestStratum_MarshalText_roundTrip tests that every stratum survives a text round trip.
+func TestStratum_MarshalText_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	for s := quantity.StratumThreads; s < quantity.NumStratum; s++ {
+		s := s
+		t.Run(s.String(), func(t *testing.T) {
+			t.Parallel()
+			bs, err := s.MarshalText()
+			require.NoError(t, err, "marshalling stratum")
+			var got quantity.Stratum
+			require.NoError(t, got.UnmarshalText(bs), "unmarshalling stratum")
+			assert.Equal(t, s, got)
+		})
+	}
+}
+
+// TestStratum_UnmarshalText_bad tests that unmarshalling an unknown stratum fails.
+func TestStratum_UnmarshalText_bad(t *testing.T) {
+	t.Parallel()
+
+	var s quantity.Stratum
+	assert.ErrorIs(t, s.UnmarshalText([]byte("colour")), quantity.ErrBadStratum)
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package quantity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Weighting is an enumeration of strategies for weighting subjects when sampling a corpus.
+type Weighting uint8
+
+const (
+	// WeightUniform samples every subject with equal probability.
+	WeightUniform Weighting = iota
+	// WeightYield weights subjects by how often their fuzzed descendants were flagged or killed mutants.
+	WeightYield
+	// WeightNovelty weights subjects by how rare their test features are within the corpus.
+	WeightNovelty
+	// WeightMixed weights subjects by both yield and novelty.
+	WeightMixed
+	// NumWeighting marks the number of weighting members.
+	NumWeighting
+)
+
+var (
+	// ErrBadWeighting occurs when we try to marshal/unmarshal a weighting that doesn't exist.
+	ErrBadWeighting = errors.New("no such weighting")
+
+	weightingStrings = [NumWeighting]string{
+		"uniform",
+		"yield",
+		"novelty",
+		"mixed",
+	}
+)
+
+// WeightingOfString tries to get the weighting corresponding to s.
+func WeightingOfString(s string) (Weighting, error) {
+	for i := WeightUniform; i < NumWeighting; i++ {
+		if strings.EqualFold(weightingStrings[i], s) {
+			return i, nil
+		}
+	}
+	return WeightUniform, fmt.Errorf("%w: %s", ErrBadWeighting, s)
+}
+
+// String converts this weighting into a human-readable string.
+func (w Weighting) String() string {
+	ts, err := w.tryString()
+	if err != nil {
+		return "(ERROR)"
+	}
+	return ts
+}
+
+// MarshalText tries to marshal this weighting into text.
+func (w Weighting) MarshalText() ([]byte, error) {
+	ts, err := w.tryString()
+	if err != nil {
+		return []byte{}, err
+	}
+	return []byte(ts), nil
+}
+
+func (w Weighting) tryString() (string, error) {
+	if NumWeighting <= w {
+		return "", fmt.Errorf("%w: #%d", ErrBadWeighting, w)
+	}
+	return weightingStrings[w], nil
+}
+
+// UnmarshalText tries to unmarshal text into a Weighting.
+func (w *Weighting) UnmarshalText(text []byte) error {
+	var err error
+	*w, err = WeightingOfString(string(text))
+	return err
+}
+
+// UsesYield gets whether this weighting takes historical yield into account.
+func (w Weighting) UsesYield() bool {
+	return w == WeightYield || w == WeightMixed
+}
+
+// UsesNovelty gets whether this weighting takes feature novelty into account.
+func (w Weighting) UsesNovelty() bool {
+	return w == WeightNovelty || w == WeightMixed
+}
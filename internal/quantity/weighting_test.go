@@ -0,0 +1,39 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package quantity_test
+
+import (
+	"testing"
+
+	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWeighting_MarshalText_roundTrip tests that every weighting survives a text round trip.
+func TestWeighting_MarshalText_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	for w := quantity.WeightUniform; w < quantity.NumWeighting; w++ {
+		w := w
+		t.Run(w.String(), func(t *testing.T) {
+			t.Parallel()
+			bs, err := w.MarshalText()
+			require.NoError(t, err, "marshalling weighting")
+			var got quantity.Weighting
+			require.NoError(t, got.UnmarshalText(bs), "unmarshalling weighting")
+			assert.Equal(t, w, got)
+		})
+	}
+}
+
+// TestWeighting_UnmarshalText_bad tests that unmarshalling an unknown weighting fails.
+func TestWeighting_UnmarshalText_bad(t *testing.T) {
+	t.Parallel()
+
+	var w quantity.Weighting
+	assert.ErrorIs(t, w.UnmarshalText([]byte("lottery")), quantity.ErrBadWeighting)
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/c4-project/c4t/internal/id"
 
 	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/subject/compilation"
 
 	backend2 "github.com/c4-project/c4t/internal/model/service/backend"
 
@@ -128,6 +129,15 @@ func (h Backend) ParseObs(_ context.Context, r io.Reader, o *obs.Obs) error {
 	return parser.Parse(h.class.Impl, r, o)
 }
 
+// IterationArgs gets the iteration arguments for executables produced by this backend, if its implementation
+// supports them.
+func (h Backend) IterationArgs(it compilation.Iterations) []string {
+	if ia, ok := h.class.Impl.(backend2.IterationArger); ok {
+		return ia.IterationArgs(it)
+	}
+	return nil
+}
+
 func (h Backend) Lift(ctx context.Context, j backend2.LiftJob, x service.Runner) (recipe.Recipe, error) {
 	if err := h.checkAndAmendJob(&j); err != nil {
 		return recipe.Recipe{}, err
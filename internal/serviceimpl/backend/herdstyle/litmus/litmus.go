@@ -10,6 +10,9 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strconv"
+
+	"github.com/c4-project/c4t/internal/subject/compilation"
 
 	"github.com/c4-project/c4t/internal/model/litmus"
 
@@ -39,6 +42,18 @@ func litmusCommonArgs(j backend.LiftJob) ([]string, error) {
 	return []string{"-carch", carch, "-c11", "true", j.In.Litmus.Path}, nil
 }
 
+// IterationArgs gets the arguments that make a litmus7 harness perform the iterations described by it.
+func (l Litmus) IterationArgs(it compilation.Iterations) []string {
+	var args []string
+	if 0 < it.Size {
+		args = append(args, "-s", strconv.Itoa(it.Size))
+	}
+	if 0 < it.Count {
+		args = append(args, "-r", strconv.Itoa(it.Count))
+	}
+	return args
+}
+
 // LiftStandalone runs litmus in standalone mode.
 // It currently doesn't do the same patching as LiftExe does.
 func (l Litmus) LiftStandalone(ctx context.Context, j backend.LiftJob, r service.RunInfo, x service.Runner, w io.Writer) error {
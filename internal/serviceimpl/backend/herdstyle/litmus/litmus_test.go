@@ -7,9 +7,11 @@ package litmus_test
 
 import (
 	"context"
+	"fmt"
 	"os"
 
 	"github.com/c4-project/c4t/internal/model/service/backend"
+	"github.com/c4-project/c4t/internal/subject/compilation"
 
 	"github.com/c4-project/c4t/internal/helper/srvrun"
 
@@ -38,3 +40,13 @@ func ExampleInstance_Run() {
 	// Output:
 	// litmus7 -v -o out -carch X86_64 -c11 true in.litmus
 }
+
+// ExampleLitmus_IterationArgs is a testable example for IterationArgs.
+func ExampleLitmus_IterationArgs() {
+	fmt.Println(litmus.Litmus{}.IterationArgs(compilation.Iterations{Size: 5000, Count: 20}))
+	fmt.Println(litmus.Litmus{}.IterationArgs(compilation.Iterations{Count: 3}))
+
+	// Output:
+	// [-s 5000 -r 20]
+	// [-r 3]
+}
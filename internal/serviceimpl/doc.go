@@ -3,6 +3,6 @@
 // This file is part of c4t.
 // Licenced under the MIT licence; see `LICENSE`.
 
-// Package serviceimpl contains implementations of compiler and backend services,
+// Package serviceimpl contains implementations of compiler, backend, and fuzzer services,
 // as well as hard-coded logic for resolving them from style IDs.
 package serviceimpl
@@ -0,0 +1,24 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package fuzzer
+
+import (
+	"github.com/c4-project/c4t/internal/c4f"
+	"github.com/c4-project/c4t/internal/model/service"
+
+	fuzzer2 "github.com/c4-project/c4t/internal/model/service/fuzzer"
+)
+
+// C4f is the class of the c4f fuzzer.
+type C4f struct {
+	// DuneExec toggles whether c4f should be run through dune.
+	DuneExec bool
+}
+
+// Instantiate makes a c4f fuzzer that runs c4f through sr.
+func (c C4f) Instantiate(_ fuzzer2.Config, sr service.Runner) (fuzzer2.Fuzzer, error) {
+	return &c4f.Runner{DuneExec: c.DuneExec, Base: sr}, nil
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package fuzzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/c4-project/c4t/internal/model/service"
+
+	fuzzer2 "github.com/c4-project/c4t/internal/model/service/fuzzer"
+)
+
+// ErrNeedRunInfo occurs when we try to instantiate an external fuzzer without saying how to run it.
+var ErrNeedRunInfo = errors.New("external fuzzers need run information")
+
+// External is the class of fuzzers that run an arbitrary external command, such as another C11 concurrency test
+// generator.
+//
+// The command comes from the fuzzer configuration's run information.  Its arguments and environment can refer to the
+// input file as ${input}, the output Litmus file as ${output}, the output trace file as ${trace}, and the seed as
+// ${seed}.
+type External struct{}
+
+// Instantiate makes an external fuzzer from the run information in c, which it runs through sr.
+func (External) Instantiate(c fuzzer2.Config, sr service.Runner) (fuzzer2.Fuzzer, error) {
+	if c.Run == nil || c.Run.Cmd == "" {
+		return nil, ErrNeedRunInfo
+	}
+	return &ExternalFuzzer{Run: *c.Run, Runner: sr}, nil
+}
+
+// ExternalFuzzer is a fuzzer that runs an external command.
+type ExternalFuzzer struct {
+	// Run is the uninterpolated run information for the command.
+	Run service.RunInfo
+	// Runner is the service runner used to run the command.
+	Runner service.Runner
+}
+
+// Fuzz runs the external command on j.
+func (e *ExternalFuzzer) Fuzz(ctx context.Context, j fuzzer2.Job) error {
+	ri, err := e.RunInfo(j)
+	if err != nil {
+		return err
+	}
+	if err := e.Runner.Run(ctx, ri); err != nil {
+		return fmt.Errorf("running external fuzzer %q: %w", ri.Cmd, err)
+	}
+	return nil
+}
+
+// RunInfo gets the run information for fuzzing j, with any references to j interpolated.
+func (e *ExternalFuzzer) RunInfo(j fuzzer2.Job) (service.RunInfo, error) {
+	ri := e.Run
+	err := ri.Interpolate(map[string]string{
+		"input":  j.In,
+		"output": j.OutLitmus,
+		"trace":  j.OutTrace,
+		"seed":   strconv.Itoa(int(j.Seed)),
+	})
+	return ri, err
+}
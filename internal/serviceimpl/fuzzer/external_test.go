@@ -0,0 +1,66 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package fuzzer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/mocks"
+
+	fuzzer2 "github.com/c4-project/c4t/internal/model/service/fuzzer"
+	"github.com/c4-project/c4t/internal/serviceimpl/fuzzer"
+)
+
+// TestExternal_Instantiate_noRun tests that instantiating an external fuzzer without run information fails.
+func TestExternal_Instantiate_noRun(t *testing.T) {
+	t.Parallel()
+
+	_, err := fuzzer.External{}.Instantiate(fuzzer2.Config{}, nil)
+	assert.ErrorIs(t, err, fuzzer.ErrNeedRunInfo)
+}
+
+// TestExternalFuzzer_Fuzz tests that an external fuzzer runs its command with the job interpolated into it.
+func TestExternalFuzzer_Fuzz(t *testing.T) {
+	t.Parallel()
+
+	sr := new(mocks.Runner)
+	sr.Test(t)
+
+	cfg := fuzzer2.Config{
+		Style: fuzzer.StyleExternal,
+		Run: &service.RunInfo{
+			Cmd:  "gen11",
+			Args: []string{"--seed=${seed}", "-o", "${output}", "${input}"},
+			Env:  map[string]string{"GEN11_TRACE": "${trace}"},
+		},
+	}
+	f, err := fuzzer2.ResolveAndInstantiate(cfg, &fuzzer.Resolve, sr)
+	require.NoError(t, err, "instantiating external fuzzer")
+
+	want := service.RunInfo{
+		Cmd:  "gen11",
+		Args: []string{"--seed=42", "-o", "out/foo_1.litmus", "in/foo.litmus"},
+		Env:  map[string]string{"GEN11_TRACE": "out/foo_1.trace"},
+	}
+	sr.On("Run", mock.Anything, want).Return(nil).Once()
+
+	err = f.Fuzz(context.Background(), fuzzer2.Job{
+		Seed:      42,
+		In:        "in/foo.litmus",
+		OutLitmus: "out/foo_1.litmus",
+		OutTrace:  "out/foo_1.trace",
+	})
+	require.NoError(t, err, "fuzzing with external fuzzer")
+
+	sr.AssertExpectations(t)
+	assert.Equal(t, []string{"--seed=${seed}", "-o", "${output}", "${input}"}, cfg.Run.Args, "config shouldn't change")
+}
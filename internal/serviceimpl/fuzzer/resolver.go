@@ -0,0 +1,72 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package fuzzer contains the styles of fuzzer that c4t can drive, and a resolver for them.
+package fuzzer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/c4-project/c4t/internal/c4f"
+	"github.com/c4-project/c4t/internal/id"
+
+	fuzzer2 "github.com/c4-project/c4t/internal/model/service/fuzzer"
+)
+
+var (
+	// ErrNil occurs when the resolver we try to use is nil.
+	ErrNil = errors.New("fuzzer resolver nil")
+	// ErrUnknownStyle occurs when we ask the resolver for a fuzzer style of which it isn't aware.
+	ErrUnknownStyle = errors.New("unknown fuzzer style")
+
+	// StyleC4f is the style of the c4f fuzzer, which is the default.
+	StyleC4f = id.FromString("c4f")
+	// StyleExternal is the style of fuzzers that run arbitrary external commands.
+	StyleExternal = id.FromString("external")
+
+	// Resolve is a pre-populated fuzzer resolver.
+	Resolve = Resolver{Fuzzers: map[id.ID]fuzzer2.Class{
+		StyleC4f:      C4f{},
+		StyleExternal: External{},
+	}}
+)
+
+// ResolveFrom gets a copy of Resolve whose c4f style runs c4f with the same settings as r.
+//
+// This lets an explicit 'c4f' style in the fuzzer config honour command-line settings such as whether to use dune.
+func ResolveFrom(r *c4f.Runner) *Resolver {
+	fs := make(map[id.ID]fuzzer2.Class, len(Resolve.Fuzzers))
+	for s, c := range Resolve.Fuzzers {
+		fs[s] = c
+	}
+	if r != nil {
+		fs[StyleC4f] = C4f{DuneExec: r.DuneExec}
+	}
+	return &Resolver{Fuzzers: fs}
+}
+
+// Resolver maps fuzzer styles to classes, and implements a resolver accordingly.
+type Resolver struct {
+	// Fuzzers is the raw map from style IDs to fuzzer classes.
+	Fuzzers map[id.ID]fuzzer2.Class
+}
+
+// Resolve tries to look up the fuzzer style style in this resolver.
+// The empty style resolves to c4f.
+func (r *Resolver) Resolve(style id.ID) (fuzzer2.Class, error) {
+	if r == nil {
+		return nil, ErrNil
+	}
+	if style.IsEmpty() {
+		style = StyleC4f
+	}
+
+	c, ok := r.Fuzzers[style]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownStyle, style)
+	}
+	return c, nil
+}
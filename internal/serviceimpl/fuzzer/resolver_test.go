@@ -0,0 +1,62 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package fuzzer_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/c4f"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/serviceimpl/fuzzer"
+)
+
+// TestResolver_Resolve tests resolving the known fuzzer styles, and an unknown one, with the standard resolver.
+func TestResolver_Resolve(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		style string
+		err   error
+	}{
+		"empty":    {style: "", err: nil},
+		"c4f":      {style: "c4f", err: nil},
+		"external": {style: "external", err: nil},
+		"unknown":  {style: "csmith", err: fuzzer.ErrUnknownStyle},
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cls, err := fuzzer.Resolve.Resolve(id.FromString(c.style))
+			if c.err != nil {
+				require.ErrorIs(t, err, c.err, "resolution should fail")
+				return
+			}
+			require.NoError(t, err, "resolution should pass")
+			assert.NotNil(t, cls, "resolution should give a class")
+		})
+	}
+}
+
+// TestResolveFrom tests that ResolveFrom carries the c4f runner's settings into the c4f style.
+func TestResolveFrom(t *testing.T) {
+	t.Parallel()
+
+	r := fuzzer.ResolveFrom(&c4f.Runner{DuneExec: true})
+	for _, style := range []string{"", "c4f"} {
+		cls, err := r.Resolve(id.FromString(style))
+		require.NoError(t, err, "resolving c4f")
+		assert.Equal(t, fuzzer.C4f{DuneExec: true}, cls, "c4f class should use dune")
+	}
+
+	_, err := r.Resolve(fuzzer.StyleExternal)
+	require.NoError(t, err, "other styles should still resolve")
+	assert.Equal(t, fuzzer.C4f{}, fuzzer.Resolve.Fuzzers[fuzzer.StyleC4f], "Resolve itself shouldn't change")
+}
@@ -11,9 +11,14 @@ import (
 	"context"
 	"errors"
 
+	"github.com/c4-project/c4t/internal/helper/errhelp"
 	"github.com/c4-project/c4t/internal/plan/stage"
+	"github.com/c4-project/c4t/internal/remote"
 
+	"github.com/c4-project/c4t/internal/stage/analyser/promoter"
 	"github.com/c4-project/c4t/internal/stage/analyser/saver"
+	"github.com/c4-project/c4t/internal/stage/fuzzer/tuner"
+	"github.com/c4-project/c4t/internal/stage/mach/interpreter"
 
 	"github.com/c4-project/c4t/internal/plan/analysis"
 
@@ -32,6 +37,29 @@ type Analyser struct {
 	observers []Observer
 	// saveObservers is the list of observers to which archival operations are sent.
 	saveObservers []saver.Observer
+	// suppressions, if non-nil, is the suppression database to apply and update.
+	suppressions *analysis.SuppressionDB
+	// warnings, if non-nil, is the warning database against which we check for, and record, new warnings.
+	warnings *analysis.WarningDB
+	// timings, if non-nil, is the timing database against which we check for, and record, timing regressions.
+	timings *analysis.TimingDB
+	// promoter, if non-nil, promotes fuzzer outputs with new behaviours into a persistent corpus.
+	promoter *promoter.Promoter
+	// tuner, if non-nil, learns which tuned fuzzer parameters produce interesting subjects.
+	tuner *tuner.Bandit
+	// skipKnownClusters makes the saver skip subjects whose failures are all in already-archived clusters.
+	skipKnownClusters bool
+	// saveConfig configures the format, target, and retention policy of saved runs.
+	saveConfig saver.Config
+	// ssh is the SSH configuration used to open any SFTP save target.
+	ssh *remote.Config
+	// cdriver, if non-nil, is the compiler driver the saver uses to find compiler invocations for reproductions.
+	cdriver interpreter.Driver
+
+	// saver, if non-nil, is the saver opened on the first save; it lasts for the analyser's lifetime.
+	saver *saver.Saver
+	// target, if non-nil, is the save target opened on the first save; Close closes it.
+	target saver.Target
 }
 
 // New constructs a new analyser stage on plan p, with options opts.
@@ -46,21 +74,47 @@ func (*Analyser) Stage() stage.Stage {
 	return stage.Analyse
 }
 
-// Close does nothing.
-func (*Analyser) Close() error {
-	return nil
+// Close closes any save target the analyser opened.
+func (a *Analyser) Close() error {
+	if a.target == nil {
+		return nil
+	}
+	err := a.target.Close()
+	a.saver, a.target = nil, nil
+	return err
 }
 
-func (a *Analyser) newSaver() (*saver.Saver, error) {
-	if a.savePaths == nil {
-		return nil, nil
+// getSaver gets the analyser's saver, opening it and its target if this is the first save.
+func (a *Analyser) getSaver() (*saver.Saver, error) {
+	if a.saver != nil {
+		return a.saver, nil
+	}
+	// t can be nil if we're saving only to the save pathset.
+	t, err := saver.OpenTarget(a.saveConfig.Target, a.ssh)
+	if err != nil {
+		return nil, err
+	}
+	s, err := a.newSaver(t)
+	if err != nil {
+		if t != nil {
+			err = errhelp.FirstError(err, t.Close())
+		}
+		return nil, err
 	}
+	a.saver, a.target = s, t
+	return s, nil
+}
+
+func (a *Analyser) newSaver(t saver.Target) (*saver.Saver, error) {
 	return saver.New(
 		a.savePaths,
-		func(path string) (saver.Archiver, error) {
-			return saver.CreateTGZ(path)
-		},
-		saver.ObserveWith(a.saveObservers...))
+		a.saveConfig.Format.Create,
+		saver.ArchiveSuffix(a.saveConfig.Format.Suffix()),
+		saver.ObserveWith(a.saveObservers...),
+		saver.SendTo(t),
+		saver.Retain(a.saveConfig.Retention),
+		saver.ReproduceWith(a.cdriver),
+		saver.SkipKnownClusters(a.skipKnownClusters))
 }
 
 // Run runs the analyser on the plan p, outputting to the configured output writer.
@@ -74,10 +128,11 @@ func (a *Analyser) Run(ctx context.Context, p *plan.Plan) (*plan.Plan, error) {
 		return nil, err
 	}
 
+	// We save before notifying observers, so that they can see what was saved (for instance, to link to archives).
+	serr := a.maybeSave(ctx, an)
 	OnAnalysis(*an, a.observers...)
-
-	if err := a.maybeSave(an); err != nil {
-		return nil, err
+	if serr != nil {
+		return nil, serr
 	}
 
 	return an.Plan, a.statusErr(an)
@@ -90,17 +145,64 @@ func checkPlan(p *plan.Plan) error {
 	return p.Check()
 }
 
-func (a *Analyser) maybeSave(an *analysis.Analysis) error {
-	save, err := a.newSaver()
-	// save can be nil if we're not supposed to be saving.
-	if err != nil || save == nil {
+func (a *Analyser) maybeSave(ctx context.Context, an *analysis.Analysis) error {
+	if a.savePaths == nil {
+		return nil
+	}
+	save, err := a.getSaver()
+	if err != nil {
 		return err
 	}
-	return save.Run(*an)
+	return save.Run(ctx, *an)
 }
 
 func (a *Analyser) analyse(ctx context.Context, p *plan.Plan) (*analysis.Analysis, error) {
-	return analysis.Analyse(ctx, p, a.aopts...)
+	aopts := a.aopts
+	if a.suppressions != nil {
+		aopts = append(aopts, analysis.WithSuppressions(a.suppressions.Set()))
+	}
+	if a.warnings != nil {
+		aopts = append(aopts, analysis.WithWarningHistory(a.warnings.History()))
+	}
+	if a.timings != nil {
+		aopts = append(aopts, analysis.WithTimingHistory(a.timings.History()))
+	}
+
+	an, err := analysis.Analyse(ctx, p, aopts...)
+	if err != nil {
+		return nil, err
+	}
+	return an, a.record(an)
+}
+
+// record records the effects of the analysis an on any databases the analyser is tracking.
+func (a *Analyser) record(an *analysis.Analysis) error {
+	t := an.Plan.Metadata.Creation
+	if a.suppressions != nil {
+		if err := a.suppressions.Record(an.Suppressions, t); err != nil {
+			return err
+		}
+	}
+	if a.warnings != nil {
+		if err := a.warnings.Record(an.WarningKeys(), t); err != nil {
+			return err
+		}
+	}
+	if a.timings != nil {
+		if err := a.timings.Record(an.TimingSamples(), t); err != nil {
+			return err
+		}
+	}
+	if a.tuner != nil {
+		if err := a.tuner.Learn(*an); err != nil {
+			return err
+		}
+	}
+	if a.promoter != nil {
+		_, err := a.promoter.Promote(an)
+		return err
+	}
+	return nil
 }
 
 // ErrBadStatus is the error reported when the analyser is asked to error on a bad status, and one arrives.
@@ -0,0 +1,52 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package csvdump
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/subject/status"
+)
+
+// FeatureWriter wraps a CSV writer and makes it output correlations between test features and outcomes.
+type FeatureWriter csv.Writer
+
+// NewFeatureWriter creates a new feature writer over w.
+func NewFeatureWriter(w io.Writer) *FeatureWriter {
+	return (*FeatureWriter)(csv.NewWriter(w))
+}
+
+// OnAnalysis observes an analysis by emitting a CSV with one row per compiler and test feature.
+func (f *FeatureWriter) OnAnalysis(a analysis.Analysis) {
+	f.writeHeader()
+	for cid, can := range a.Compilers {
+		f.writeCompiler(cid, can.Features)
+	}
+	(*csv.Writer)(f).Flush()
+}
+
+func (f *FeatureWriter) writeHeader() {
+	rec := []string{"CompilerID", "Feature", "Total"}
+	for i := status.Ok; i <= status.Last; i++ {
+		rec = append(rec, i.String())
+	}
+	f.write(rec)
+}
+
+func (f *FeatureWriter) writeCompiler(cid id.ID, fs analysis.FeatureSet) {
+	for _, fo := range fs.Sorted() {
+		rec := []string{cid.String(), fo.Feature, strconv.Itoa(fo.Total)}
+		f.write(append(rec, counts(fo.Counts)...))
+	}
+}
+
+func (f *FeatureWriter) write(record []string) {
+	_ = (*csv.Writer)(f).Write(record)
+}
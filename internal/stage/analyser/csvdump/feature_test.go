@@ -0,0 +1,30 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package csvdump_test
+
+import (
+	"context"
+	"os"
+
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/stage/analyser/csvdump"
+)
+
+// ExampleFeatureWriter_OnAnalysis is a testable example for FeatureWriter.OnAnalysis.
+func ExampleFeatureWriter_OnAnalysis() {
+	an, _ := analysis.Analyse(context.Background(), plan.Mock())
+
+	// nb: aside from the header, the actual order of compilers is not deterministic
+	fw := csvdump.NewFeatureWriter(os.Stdout)
+	fw.OnAnalysis(*an)
+
+	// Unordered output:
+	// CompilerID,Feature,Total,Ok,Filtered,Flagged,CompileFail,CompileTimeout,RunFail,RunTimeout,RunLimit
+	// gcc,threads:2,1,0,0,1,0,0,0,0,0
+	// gcc,threads:8,1,0,0,0,1,0,0,0,0
+	// clang,threads:8,1,1,0,0,0,0,0,0,0
+}
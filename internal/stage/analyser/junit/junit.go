@@ -0,0 +1,222 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package junit handles outputting of analysis data as JUnit XML, for consumption by CI dashboards.
+package junit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/c4-project/c4t/internal/subject/status"
+)
+
+// Writer outputs analyses as JUnit XML.
+//
+// Each compiler becomes a test suite, and each subject compiled by that compiler becomes a test case.
+// Bad statuses become failures, filtered compilations are skipped, and compiler logs go into the test case's
+// standard output.
+type Writer struct {
+	w io.Writer
+}
+
+// NewWriter creates a new JUnit writer over w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// OnAnalysis observes an analysis by emitting it as JUnit XML; if an error occurs, it tries to rescue.
+func (j *Writer) OnAnalysis(a analysis.Analysis) {
+	if err := j.Write(a); err != nil {
+		_, _ = fmt.Fprintf(j.w, "<!-- ERROR OUTPUTTING ANALYSIS: %s -->\n", err)
+	}
+}
+
+// Write writes a as JUnit XML.
+func (j *Writer) Write(a analysis.Analysis) error {
+	if _, err := io.WriteString(j.w, xml.Header); err != nil {
+		return err
+	}
+	e := xml.NewEncoder(j.w)
+	e.Indent("", "  ")
+	if err := e.Encode(NewTestSuites(a)); err != nil {
+		return err
+	}
+	_, err := io.WriteString(j.w, "\n")
+	return err
+}
+
+// TestSuites is the root element of a JUnit XML document.
+type TestSuites struct {
+	XMLName  xml.Name    `xml:"testsuites"`
+	Name     string      `xml:"name,attr,omitempty"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     seconds     `xml:"time,attr"`
+	Suites   []TestSuite `xml:"testsuite"`
+}
+
+// TestSuite is a JUnit test suite, which we use to represent a compiler.
+type TestSuite struct {
+	Name      string     `xml:"name,attr"`
+	Tests     int        `xml:"tests,attr"`
+	Failures  int        `xml:"failures,attr"`
+	Skipped   int        `xml:"skipped,attr"`
+	Time      seconds    `xml:"time,attr"`
+	Timestamp string     `xml:"timestamp,attr,omitempty"`
+	Cases     []TestCase `xml:"testcase"`
+}
+
+// TestCase is a JUnit test case, which we use to represent a subject's compilation by a compiler.
+type TestCase struct {
+	Name      string   `xml:"name,attr"`
+	ClassName string   `xml:"classname,attr"`
+	Time      seconds  `xml:"time,attr"`
+	Failure   *Failure `xml:"failure,omitempty"`
+	Skipped   *Skipped `xml:"skipped,omitempty"`
+	SystemOut string   `xml:"system-out,omitempty"`
+}
+
+// Failure marks a test case as having failed.
+type Failure struct {
+	// Type is the status of the failed compilation.
+	Type string `xml:"type,attr"`
+	// Message is the failure signature of the compilation, if it has one.
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// Skipped marks a test case as having been skipped.
+type Skipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// seconds is a duration that marshals as an XML attribute in fractional seconds.
+type seconds time.Duration
+
+// MarshalXMLAttr marshals this duration as fractional seconds.
+func (s seconds) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: fmt.Sprintf("%.3f", time.Duration(s).Seconds())}, nil
+}
+
+// NewTestSuites builds a JUnit test suite document from the analysis a.
+func NewTestSuites(a analysis.Analysis) TestSuites {
+	var (
+		ts   = TestSuites{Name: "c4t"}
+		cids []id.ID
+	)
+
+	sub := subjects(a)
+	sigs := signatures(a.Clusters)
+	for cid := range a.Compilers {
+		cids = append(cids, cid)
+	}
+	sort.Slice(cids, func(i, j int) bool { return cids[i].Less(cids[j]) })
+
+	for _, cid := range cids {
+		s := newTestSuite(cid, a.Compilers[cid], sub, sigs)
+		if a.Plan != nil {
+			s.Timestamp = a.Plan.Metadata.Creation.Format(time.RFC3339)
+		}
+		ts.Tests += s.Tests
+		ts.Failures += s.Failures
+		ts.Skipped += s.Skipped
+		ts.Time += s.Time
+		ts.Suites = append(ts.Suites, s)
+	}
+	return ts
+}
+
+func newTestSuite(cid id.ID, c analysis.Compiler, sub corpus.Corpus, sigs map[compilation.Name]analysis.Signature) TestSuite {
+	s := TestSuite{Name: cid.String()}
+
+	snames := make([]string, 0, len(c.Statuses))
+	for sname := range c.Statuses {
+		snames = append(snames, sname)
+	}
+	sort.Strings(snames)
+
+	for _, sname := range snames {
+		tc := newTestCase(compilation.Name{SubjectName: sname, CompilerID: cid}, c, sub, sigs)
+		s.Tests++
+		if tc.Failure != nil {
+			s.Failures++
+		}
+		if tc.Skipped != nil {
+			s.Skipped++
+		}
+		s.Time += tc.Time
+		s.Cases = append(s.Cases, tc)
+	}
+	return s
+}
+
+func newTestCase(n compilation.Name, c analysis.Compiler, sub corpus.Corpus, sigs map[compilation.Name]analysis.Signature) TestCase {
+	tc := TestCase{
+		Name:      n.SubjectName,
+		ClassName: n.CompilerID.String(),
+		Time:      seconds(duration(sub, n)),
+		SystemOut: c.Logs[n.SubjectName],
+	}
+	switch st := c.Statuses[n.SubjectName]; {
+	case st == status.Filtered:
+		tc.Skipped = &Skipped{Message: "filtered or suppressed"}
+	case st.IsBad():
+		tc.Failure = &Failure{Type: st.String()}
+		if sig, ok := sigs[n]; ok {
+			tc.Failure.Message = sig.String()
+		}
+	}
+	return tc
+}
+
+// subjects collects every subject in the analysis into one corpus.
+func subjects(a analysis.Analysis) corpus.Corpus {
+	c := make(corpus.Corpus)
+	for _, bs := range a.ByStatus {
+		for n, s := range bs {
+			c[n] = s
+		}
+	}
+	return c
+}
+
+// signatures maps each clustered compilation to its failure signature.
+func signatures(cs analysis.ClusterSet) map[compilation.Name]analysis.Signature {
+	sigs := make(map[compilation.Name]analysis.Signature)
+	for _, c := range cs {
+		for _, m := range c.Members {
+			sigs[m] = c.Signature
+		}
+	}
+	return sigs
+}
+
+// duration gets the total compile and run time of the compilation n in sub.
+func duration(sub corpus.Corpus, n compilation.Name) time.Duration {
+	s, ok := sub[n.SubjectName]
+	if !ok {
+		return 0
+	}
+	cm, ok := s.Compilations[n.CompilerID]
+	if !ok {
+		return 0
+	}
+	var d time.Duration
+	if cm.Compile != nil {
+		d += cm.Compile.Timespan.Duration()
+	}
+	if cm.Run != nil {
+		d += cm.Run.Timespan.Duration()
+	}
+	return d
+}
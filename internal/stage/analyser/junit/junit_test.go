@@ -0,0 +1,45 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package junit_test
+
+import (
+	"context"
+	"os"
+
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/stage/analyser/junit"
+)
+
+// NB: the below XML is likely to change as the plan mock changes.
+// At time of writing, the mock had no compiler logs, so the system-out sections show log errors.
+
+// ExampleWriter_OnAnalysis is a testable example for Writer.OnAnalysis.
+func ExampleWriter_OnAnalysis() {
+	an, _ := analysis.Analyse(context.Background(), plan.Mock())
+
+	junit.NewWriter(os.Stdout).OnAnalysis(*an)
+
+	// Output:
+	// <?xml version="1.0" encoding="UTF-8"?>
+	// <testsuites name="c4t" tests="3" failures="2" skipped="0" time="400.000">
+	//   <testsuite name="clang" tests="1" failures="0" skipped="0" time="200.000" timestamp="2011-11-11T11:11:11-08:00">
+	//     <testcase name="bar" classname="clang" time="200.000">
+	//       <system-out>(ERROR GETTING COMPILE LOG: subject file not available: clang/bar/log.txt (tried archive clang.tar.gz, but couldn&#39;t find it))</system-out>
+	//     </testcase>
+	//   </testsuite>
+	//   <testsuite name="gcc" tests="2" failures="2" skipped="0" time="200.000" timestamp="2011-11-11T11:11:11-08:00">
+	//     <testcase name="bar" classname="gcc" time="0.000">
+	//       <failure type="CompileFail" message="CompileFail gcc@ppc.64le.power9: (ERROR GETTING COMPILE LOG: compiler result has no log file)"></failure>
+	//       <system-out>(ERROR GETTING COMPILE LOG: compiler result has no log file)</system-out>
+	//     </testcase>
+	//     <testcase name="baz" classname="gcc" time="200.000">
+	//       <failure type="Flagged" message="Flagged gcc@ppc.64le.power9"></failure>
+	//       <system-out>(ERROR GETTING COMPILE LOG: subject file not available: gcc/baz/log.txt (tried archive gcc.tar.gz, but couldn&#39;t find it))</system-out>
+	//     </testcase>
+	//   </testsuite>
+	// </testsuites>
+}
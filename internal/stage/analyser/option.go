@@ -9,8 +9,12 @@ import (
 	"errors"
 
 	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/remote"
 
+	"github.com/c4-project/c4t/internal/stage/analyser/promoter"
 	"github.com/c4-project/c4t/internal/stage/analyser/saver"
+	"github.com/c4-project/c4t/internal/stage/fuzzer/tuner"
+	"github.com/c4-project/c4t/internal/stage/mach/interpreter"
 )
 
 // ErrObserverNil occurs if we pass a nil Observer to ObserveWith.
@@ -82,3 +86,76 @@ func SaveToPathset(ps *saver.Pathset) Option {
 		return nil
 	}
 }
+
+// SaveWith sets the format, target, and retention policy of saved runs to those in cfg.
+// ssh is the SSH configuration used to connect to SFTP targets, and can be nil.
+func SaveWith(cfg saver.Config, ssh *remote.Config) Option {
+	return func(a *Analyser) error {
+		a.saveConfig = cfg
+		a.ssh = ssh
+		return nil
+	}
+}
+
+// ReproduceWith makes the saver use cdriver to find the exact compiler invocations in reproduction bundles.
+func ReproduceWith(cdriver interpreter.Driver) Option {
+	return func(a *Analyser) error {
+		a.cdriver = cdriver
+		return nil
+	}
+}
+
+// SkipKnownClusters makes the saver skip archiving subjects whose failures fall only into known clusters, if on.
+func SkipKnownClusters(on bool) Option {
+	return func(a *Analyser) error {
+		a.skipKnownClusters = on
+		return nil
+	}
+}
+
+// Suppress makes the analyser apply the suppressions in db, and record their hits back into db.
+// db can be nil, in which case no suppressions are applied.
+func Suppress(db *analysis.SuppressionDB) Option {
+	return func(a *Analyser) error {
+		a.suppressions = db
+		return nil
+	}
+}
+
+// TrackWarnings makes the analyser report warnings not in db, and record the warnings it sees back into db.
+// db can be nil, in which case warnings aren't tracked.
+func TrackWarnings(db *analysis.WarningDB) Option {
+	return func(a *Analyser) error {
+		a.warnings = db
+		return nil
+	}
+}
+
+// TrackTimings makes the analyser report subject timings that regress against db, and record the timings it sees
+// back into db.
+// db can be nil, in which case timings aren't tracked.
+func TrackTimings(db *analysis.TimingDB) Option {
+	return func(a *Analyser) error {
+		a.timings = db
+		return nil
+	}
+}
+
+// TuneWith makes the analyser reward the fuzzer parameters tuned by b according to each analysis, and save b's
+// statistics if b is persisting them.
+// b can be nil, in which case nothing is tuned.
+func TuneWith(b *tuner.Bandit) Option {
+	return func(a *Analyser) error {
+		a.tuner = b
+		return nil
+	}
+}
+
+// PromoteTo makes the analyser promote fuzzer outputs that showed new behaviours into p's corpus directory.
+// p can be nil, in which case nothing is promoted.
+func PromoteTo(p *promoter.Promoter) Option {
+	return func(a *Analyser) error {
+		a.promoter = p
+		return nil
+	}
+}
@@ -71,3 +71,17 @@ func ShowMutation(show bool) Option {
 		aw.ctx.ShowMutation = show
 	}
 }
+
+// ShowFeatures sets whether the printer should show correlations between test features and outcomes, according to show.
+func ShowFeatures(show bool) Option {
+	return func(aw *Printer) {
+		aw.ctx.ShowFeatures = show
+	}
+}
+
+// ShowClusters sets whether the printer should show failure clusters, according to show.
+func ShowClusters(show bool) Option {
+	return func(aw *Printer) {
+		aw.ctx.ShowClusters = show
+	}
+}
@@ -13,13 +13,17 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/c4-project/c4t/internal/helper/testhelp"
 	"github.com/stretchr/testify/require"
 
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
 	"github.com/c4-project/c4t/internal/plan"
 	"github.com/c4-project/c4t/internal/plan/analysis"
 	"github.com/c4-project/c4t/internal/stage/analyser/pretty"
+	"github.com/c4-project/c4t/internal/subject/status"
 )
 
 // ExamplePrinter_OnAnalysis is a testable example for Printer.OnAnalysis.
@@ -103,3 +107,196 @@ func TestPrinter_OnAnalysis_regress(t *testing.T) {
 		}
 	})
 }
+
+// ExamplePrinter_OnAnalysis_clusters is a testable example for Printer.OnAnalysis, showing failure clusters.
+func ExamplePrinter_OnAnalysis_clusters() {
+	p := plan.Mock()
+	a, err := analysis.Analyse(context.Background(), p)
+	if err != nil {
+		fmt.Println("analysis error:", err)
+		return
+	}
+	pw, err := pretty.NewPrinter(pretty.ShowClusters(true))
+	if err != nil {
+		fmt.Println("printer init error:", err)
+		return
+	}
+	pw.OnAnalysis(*a)
+
+	// Output:
+	// # Failure Clusters
+	//   4 distinct failure cluster(s)
+	//   ## 25ec87b791bef17d (1 compilation(s))
+	//     - signature: Flagged gcc@ppc.64le.power9
+	//     - representative: baz@gcc
+	//   ## 4b3779a03b6873b9 (1 compilation(s))
+	//     - signature: CompileFail gcc@ppc.64le.power9: (ERROR GETTING COMPILE LOG: compiler result has no log file)
+	//     - representative: bar@gcc
+	//   ## 5e921e875caac23a (1 compilation(s))
+	//     - signature: Flagged
+	//     - representative: baz@icc
+	//   ## 82fd39884deb6022 (1 compilation(s))
+	//     - signature: RunTimeout
+	//     - representative: barbaz@msvc
+}
+
+// ExamplePrinter_OnAnalysis_suppressions is a testable example for Printer.OnAnalysis, showing suppressions.
+func ExamplePrinter_OnAnalysis_suppressions() {
+	ss := analysis.SuppressionSet{
+		StaleAfter: 1,
+		Suppressions: []analysis.Suppression{
+			{Fingerprint: "5e921e875caac23a", Bug: "https://example.com/bugs/42"},
+			{Fingerprint: "0123456789abcdef", Misses: 3},
+		},
+	}
+	a, err := analysis.Analyse(context.Background(), plan.Mock(), analysis.WithSuppressions(ss))
+	if err != nil {
+		fmt.Println("analysis error:", err)
+		return
+	}
+	pw, err := pretty.NewPrinter(pretty.ShowClusters(true))
+	if err != nil {
+		fmt.Println("printer init error:", err)
+		return
+	}
+	pw.OnAnalysis(*a)
+
+	// Output:
+	// # Failure Clusters
+	//   3 distinct failure cluster(s)
+	//   ## 25ec87b791bef17d (1 compilation(s))
+	//     - signature: Flagged gcc@ppc.64le.power9
+	//     - representative: baz@gcc
+	//   ## 4b3779a03b6873b9 (1 compilation(s))
+	//     - signature: CompileFail gcc@ppc.64le.power9: (ERROR GETTING COMPILE LOG: compiler result has no log file)
+	//     - representative: bar@gcc
+	//   ## 82fd39884deb6022 (1 compilation(s))
+	//     - signature: RunTimeout
+	//     - representative: barbaz@msvc
+	// # Suppressions
+	//   ## 5e921e875caac23a
+	//     - bug: https://example.com/bugs/42
+	//     - suppressed 1 compilation(s):
+	//       - baz@icc
+	//   ## 0123456789abcdef *STALE*
+	//     - suppressed nothing; 3 previous miss(es)
+}
+
+// ExamplePrinter_OnAnalysis_warnings is a testable example for Printer.OnAnalysis, showing compiler warnings.
+func ExamplePrinter_OnAnalysis_warnings() {
+	gcc := analysis.Compiler{
+		Info:     compiler.MockX86Gcc(),
+		Counts:   map[status.Status]int{status.Ok: 2},
+		Time:     analysis.NewTimeSet(),
+		RunTime:  analysis.NewTimeSet(),
+		Warnings: analysis.WarningSet{},
+	}
+	gcc.Warnings.Add("foo", compiler.Diagnostic{Severity: compiler.SeverityWarning, Flag: "-Wunused-variable"})
+	gcc.Warnings.Add("bar", compiler.Diagnostic{Severity: compiler.SeverityWarning, Flag: "-Wmaybe-uninitialized"})
+	gcc.Warnings.Add("foo", compiler.Diagnostic{Severity: compiler.SeverityWarning, Flag: "-Wmaybe-uninitialized"})
+	a := analysis.Analysis{
+		Compilers:   map[id.ID]analysis.Compiler{id.FromString("gcc"): gcc},
+		NewWarnings: []analysis.WarningKey{{Compiler: "gcc", Opt: "3", Kind: "-Wmaybe-uninitialized"}},
+	}
+
+	pw, err := pretty.NewPrinter(pretty.ShowCompilers(true))
+	if err != nil {
+		fmt.Println("printer init error:", err)
+		return
+	}
+	pw.OnAnalysis(a)
+
+	// Output:
+	// # Compilers
+	//   ## gcc
+	//     - style: gcc
+	//     - arch: x86
+	//     - opt: none
+	//     - mopt: none
+	//     ### Times (sec)
+	//       - compile: Min 0 Avg 0 Max 0
+	//       - run: Min 0 Avg 0 Max 0
+	//     ### Results
+	//       - Ok: 2 subject(s)
+	//     ### Warnings
+	//       - -Wmaybe-uninitialized: 2 time(s) in 2 subject(s)
+	//       - -Wunused-variable: 1 time(s) in 1 subject(s)
+	// # New Warnings
+	//   - gcc (opt "3"): -Wmaybe-uninitialized
+}
+
+// ExamplePrinter_OnAnalysis_regressions is a testable example for Printer.OnAnalysis, showing timing regressions.
+func ExamplePrinter_OnAnalysis_regressions() {
+	gcc := analysis.Compiler{
+		Info:    compiler.MockX86Gcc(),
+		Counts:  map[status.Status]int{status.Ok: 1},
+		Time:    analysis.NewTimeSet(),
+		RunTime: analysis.NewTimeSet(),
+	}
+	a := analysis.Analysis{
+		Compilers: map[id.ID]analysis.Compiler{id.FromString("gcc"): gcc},
+		Regressions: []analysis.Regression{{
+			TimingKey: analysis.TimingKey{Compiler: "gcc", Opt: "3", Subject: "foo", Kind: analysis.TimingCompile},
+			Time:      40 * time.Second,
+			Median:    2 * time.Second,
+			Ratio:     20,
+		}},
+	}
+
+	pw, err := pretty.NewPrinter(pretty.ShowCompilers(true))
+	if err != nil {
+		fmt.Println("printer init error:", err)
+		return
+	}
+	pw.OnAnalysis(a)
+
+	// Output:
+	// # Compilers
+	//   ## gcc
+	//     - style: gcc
+	//     - arch: x86
+	//     - opt: none
+	//     - mopt: none
+	//     ### Times (sec)
+	//       - compile: Min 0 Avg 0 Max 0
+	//       - run: Min 0 Avg 0 Max 0
+	//     ### Results
+	//       - Ok: 1 subject(s)
+	// # Timing Regressions
+	//   - gcc (opt "3"): foo compile took 40s, 20.0x its median of 2s
+}
+
+// ExamplePrinter_OnAnalysis_features is a testable example for Printer.OnAnalysis, showing feature correlations.
+func ExamplePrinter_OnAnalysis_features() {
+	gcc := analysis.FeatureSet{}
+	gcc.Add(status.Flagged, "threads:2", "stmt-mo:seq_cst")
+	gcc.Add(status.Ok, "threads:2", "stmt-mo:relaxed")
+	clang := analysis.FeatureSet{}
+	clang.Add(status.CompileFail, "stmt-mo:seq_cst")
+	a := analysis.Analysis{
+		Compilers: map[id.ID]analysis.Compiler{
+			id.FromString("gcc"):   {Features: gcc},
+			id.FromString("clang"): {Features: clang},
+		},
+	}
+
+	pw, err := pretty.NewPrinter(pretty.ShowFeatures(true))
+	if err != nil {
+		fmt.Println("printer init error:", err)
+		return
+	}
+	pw.OnAnalysis(a)
+
+	// Output:
+	// # Feature Correlation
+	//   ## Overall
+	//     - stmt-mo:seq_cst: 100.0% bad of 2 compilation(s); Flagged 1; CompileFail 1
+	//     - threads:2: 50.0% bad of 2 compilation(s); Ok 1; Flagged 1
+	//     - stmt-mo:relaxed: 0.0% bad of 1 compilation(s); Ok 1
+	//   ## clang
+	//     - stmt-mo:seq_cst: 100.0% bad of 1 compilation(s); CompileFail 1
+	//   ## gcc
+	//     - stmt-mo:seq_cst: 100.0% bad of 1 compilation(s); Flagged 1
+	//     - threads:2: 50.0% bad of 2 compilation(s); Ok 1; Flagged 1
+	//     - stmt-mo:relaxed: 0.0% bad of 1 compilation(s); Ok 1
+}
@@ -8,6 +8,7 @@ package pretty
 import (
 	"embed"
 	"io/fs"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
@@ -37,6 +38,12 @@ type Config struct {
 
 	// ShowMutation is true if mutation testing information should be shown.
 	ShowMutation bool
+
+	// ShowClusters is true if failure clusters should be shown.
+	ShowClusters bool
+
+	// ShowFeatures is true if correlations between test features and outcomes should be shown.
+	ShowFeatures bool
 }
 
 // WithConfig is the type of things wrapped with pretty-printer config.
@@ -68,5 +75,6 @@ func getTemplate() (*template.Template, error) {
 	return t.Funcs(template.FuncMap{
 		"withConfig": AddConfig,
 		"time":       func(t time.Time) string { return t.Format(time.StampMilli) },
+		"percent":    func(r float64) string { return strconv.FormatFloat(100*r, 'f', 1, 64) + "%" },
 	}).ParseFS(efs, "*.tmpl")
 }
@@ -0,0 +1,130 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package promoter
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/subject/obs"
+)
+
+// behaviour is a behaviour that a subject exhibited during a cycle.
+type behaviour struct {
+	// key uniquely identifies the behaviour across cycles.
+	key string
+	// reason is the reason for promoting a subject that exhibits the behaviour for the first time.
+	reason Reason
+}
+
+// behaviours gets the behaviours each subject in an exhibited, keyed by subject name.
+// Each subject's behaviours are in ascending key order.
+//
+// States are keyed only on the locations of their subject's seed (see seedLocations), so that fuzzer outputs that
+// merely add variables to a seed don't count as exhibiting new states.
+func behaviours(an *analysis.Analysis) map[string][]behaviour {
+	bs := make(map[string][]behaviour, len(an.Plan.Corpus))
+	locs := seedLocations(an)
+	for name := range an.Plan.Corpus {
+		root := RootName(name)
+		for _, st := range states(an, name) {
+			st.Values = project(st.Values, locs[root])
+			bs[name] = append(bs[name], behaviour{key: fmt.Sprintf("state:%s:%s", root, st.Key()), reason: ReasonStates})
+		}
+	}
+	for cid, c := range an.Compilers {
+		for kind, w := range c.Warnings {
+			k := analysis.WarningKey{Compiler: cid.String(), Opt: c.Info.SelectedOptName(), Kind: kind}
+			for _, name := range w.Subjects {
+				bs[name] = append(bs[name], behaviour{key: "warning:" + k.String(), reason: ReasonWarnings})
+			}
+		}
+	}
+	for i, m := range an.Mutation {
+		for _, sel := range m.Selections {
+			if sel.Hit() {
+				name := sel.HitBy.SubjectName
+				bs[name] = append(bs[name], behaviour{key: fmt.Sprintf("mutant:%d", i), reason: ReasonMutants})
+			}
+		}
+	}
+	for _, b := range bs {
+		sort.Slice(b, func(i, j int) bool { return b[i].key < b[j].key })
+	}
+	return bs
+}
+
+// seedLocations gets, for each seed named in an, the locations its states range over.
+//
+// If the seed itself was run, these are the locations of its states; otherwise, they are the locations common to
+// every state observed by any of the seed's fuzzer outputs.
+func seedLocations(an *analysis.Analysis) map[string]map[string]struct{} {
+	locs := make(map[string]map[string]struct{})
+	for _, name := range an.Plan.Corpus.Names() {
+		root := RootName(name)
+		if name == root {
+			continue
+		}
+		for _, st := range states(an, name) {
+			ls, ok := locs[root]
+			if !ok {
+				locs[root] = varSet(st.Values)
+				continue
+			}
+			for l := range ls {
+				if _, ok := st.Values[l]; !ok {
+					delete(ls, l)
+				}
+			}
+		}
+	}
+	for _, name := range an.Plan.Corpus.Names() {
+		if name != RootName(name) {
+			continue
+		}
+		if sts := states(an, name); len(sts) != 0 {
+			locs[name] = make(map[string]struct{})
+			for _, st := range sts {
+				for l := range st.Values {
+					locs[name][l] = struct{}{}
+				}
+			}
+		}
+	}
+	return locs
+}
+
+// states gets every state observed across the compilations of the subject called name in an.
+func states(an *analysis.Analysis, name string) []obs.State {
+	var sts []obs.State
+	for _, c := range an.Plan.Corpus[name].Compilations {
+		if c.Run != nil && c.Run.Obs != nil {
+			sts = append(sts, c.Run.Obs.States...)
+		}
+	}
+	return sts
+}
+
+// varSet gets the set of variables in v.
+func varSet(v obs.Valuation) map[string]struct{} {
+	vs := make(map[string]struct{}, len(v))
+	for l := range v {
+		vs[l] = struct{}{}
+	}
+	return vs
+}
+
+// project restricts v to the locations in locs.
+func project(v obs.Valuation, locs map[string]struct{}) obs.Valuation {
+	pv := make(obs.Valuation, len(locs))
+	for l := range locs {
+		if x, ok := v[l]; ok {
+			pv[l] = x
+		}
+	}
+	return pv
+}
@@ -0,0 +1,256 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package promoter contains the part of the analyser stage that promotes interesting fuzzer outputs into a persistent,
+// deduplicated corpus directory, so that later cycles can use them as fuzzer seeds.
+package promoter
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/helper/iohelp"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/stage/fuzzer"
+	"github.com/c4-project/c4t/internal/subject"
+)
+
+const (
+	// IndexFile is the name of the file, inside a promoted corpus directory, that indexes the directory.
+	IndexFile = "index.json"
+
+	// hashLen is the number of hex digits of the content hash used to name promoted files.
+	hashLen = 8
+)
+
+// ErrDirEmpty occurs when we try to open a promoter on a blank directory.
+var ErrDirEmpty = errors.New("promoted corpus directory not given")
+
+// Reason is the type of reasons for promoting a subject.
+type Reason string
+
+const (
+	// ReasonStates means that running the subject observed states not yet seen for its seed.
+	// Only the seed's own locations count towards a state being new.
+	ReasonStates Reason = "new-states"
+	// ReasonWarnings means that compiling the subject raised kinds of compiler warning not seen before.
+	ReasonWarnings Reason = "new-warnings"
+	// ReasonMutants means that compiling the subject hit mutants not hit before.
+	ReasonMutants Reason = "new-mutants"
+)
+
+// Entry records one promoted file.
+type Entry struct {
+	// Name is the name of the promoted test, which is also the base name of its file.
+	Name string `json:"name"`
+	// Hash is the hex-encoded SHA-256 hash of the fuzzer output that was promoted.
+	Hash string `json:"hash"`
+	// Origin is the name of the subject that was promoted.
+	Origin string `json:"origin"`
+	// Reasons lists the reasons for which the subject was promoted.
+	Reasons []Reason `json:"reasons"`
+	// Promoted is the creation time of the plan from which the subject was promoted.
+	Promoted time.Time `json:"promoted"`
+}
+
+// File gets the file name of the entry's test, relative to its corpus directory.
+func (e Entry) File() string {
+	return e.Name + ".litmus"
+}
+
+// Index is the persistent state of a promoted corpus directory.
+type Index struct {
+	// Entries lists, in order of promotion, every file promoted into the directory.
+	Entries []Entry `json:"entries"`
+	// Seen lists, in ascending order, the keys of every behaviour seen so far across all analysed subjects.
+	Seen []string `json:"seen"`
+}
+
+// Promoter promotes interesting fuzzer outputs into a corpus directory.
+// It can safely be shared between concurrent analyses.
+type Promoter struct {
+	mu     sync.Mutex
+	dir    string
+	index  Index
+	seen   map[string]struct{}
+	hashes map[string]struct{}
+}
+
+// Open opens a promoter on the corpus directory dir, creating it if it doesn't yet exist.
+func Open(dir string) (*Promoter, error) {
+	if dir == "" {
+		return nil, ErrDirEmpty
+	}
+	if err := os.MkdirAll(dir, 0744); err != nil {
+		return nil, err
+	}
+	idx, err := loadIndex(filepath.Join(dir, IndexFile))
+	if err != nil {
+		return nil, err
+	}
+	p := Promoter{
+		dir:    dir,
+		index:  idx,
+		seen:   make(map[string]struct{}, len(idx.Seen)),
+		hashes: make(map[string]struct{}, len(idx.Entries)),
+	}
+	for _, k := range idx.Seen {
+		p.seen[k] = struct{}{}
+	}
+	for _, e := range idx.Entries {
+		p.hashes[e.Hash] = struct{}{}
+	}
+	return &p, nil
+}
+
+func loadIndex(path string) (Index, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return Index{}, nil
+		}
+		return Index{}, err
+	}
+	var idx Index
+	derr := json.NewDecoder(f).Decode(&idx)
+	cerr := f.Close()
+	return idx, errhelp.FirstError(derr, cerr)
+}
+
+func (p *Promoter) writeIndex() error {
+	return iohelp.WriteJSONFileAtomic(filepath.Join(p.dir, IndexFile), p.index)
+}
+
+// Dir gets the corpus directory into which this promoter promotes files.
+func (p *Promoter) Dir() string {
+	return p.dir
+}
+
+// Entries gets a copy of the entries for every file promoted so far.
+func (p *Promoter) Entries() []Entry {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Entry(nil), p.index.Entries...)
+}
+
+// Promote records the behaviours seen in the analysis an, and copies into the corpus directory every fuzzer output
+// in an that showed behaviours not seen before, unless its contents are already in the directory.
+// It returns the entries for the newly promoted files.
+func (p *Promoter) Promote(an *analysis.Analysis) ([]Entry, error) {
+	bs := behaviours(an)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var promoted []Entry
+	for _, name := range an.Plan.Corpus.Names() {
+		rs := p.see(bs[name])
+		s := an.Plan.Corpus[name]
+		if len(rs) == 0 || !s.HasFuzzFile() {
+			continue
+		}
+		e, ok, err := p.promote(name, s, rs, an.Plan.Metadata.Creation)
+		if err != nil {
+			return nil, fmt.Errorf("promoting %s: %w", name, err)
+		}
+		if ok {
+			promoted = append(promoted, e)
+		}
+	}
+	return promoted, p.writeIndex()
+}
+
+// see marks each behaviour in bs as seen, returning the reasons for any behaviours that weren't seen before.
+func (p *Promoter) see(bs []behaviour) []Reason {
+	var rs []Reason
+	for _, b := range bs {
+		if _, ok := p.seen[b.key]; ok {
+			continue
+		}
+		p.seen[b.key] = struct{}{}
+		i := sort.SearchStrings(p.index.Seen, b.key)
+		p.index.Seen = append(p.index.Seen, "")
+		copy(p.index.Seen[i+1:], p.index.Seen[i:])
+		p.index.Seen[i] = b.key
+		rs = addReason(rs, b.reason)
+	}
+	return rs
+}
+
+func addReason(rs []Reason, r Reason) []Reason {
+	for _, r2 := range rs {
+		if r == r2 {
+			return rs
+		}
+	}
+	return append(rs, r)
+}
+
+func (p *Promoter) promote(name string, s subject.Subject, rs []Reason, t time.Time) (Entry, bool, error) {
+	src, err := os.ReadFile(s.Fuzz.Litmus.Filepath())
+	if err != nil {
+		return Entry{}, false, err
+	}
+	sum := sha256.Sum256(src)
+	hash := hex.EncodeToString(sum[:])
+	if _, ok := p.hashes[hash]; ok {
+		return Entry{}, false, nil
+	}
+
+	e := Entry{
+		Name:     fmt.Sprintf("%s_h%s", RootName(name), hash[:hashLen]),
+		Hash:     hash,
+		Origin:   name,
+		Reasons:  rs,
+		Promoted: t,
+	}
+	if err := os.WriteFile(filepath.Join(p.dir, e.File()), Rename(src, e.Name), 0644); err != nil {
+		return Entry{}, false, err
+	}
+	p.hashes[hash] = struct{}{}
+	p.index.Entries = append(p.index.Entries, e)
+	return e, true, nil
+}
+
+// promotedSuffix matches the suffix the promoter adds to the names of promoted tests.
+var promotedSuffix = regexp.MustCompile(`_h[0-9a-f]{8}$`)
+
+// RootName gets the name of the original seed from which the subject called name descends.
+// It strips both the cycle suffix the fuzzer adds to its outputs and the hash suffix the promoter adds to promoted
+// tests, so that repeatedly promoting the descendants of one seed doesn't grow their names.
+func RootName(name string) string {
+	if sc, err := fuzzer.ParseSubjectCycle(name); err == nil {
+		name = sc.Name
+	}
+	return promotedSuffix.ReplaceAllString(name, "")
+}
+
+// Rename renames the C litmus test src to name, by rewriting its header line.
+// If src doesn't start with a C litmus header, it is returned unchanged.
+func Rename(src []byte, name string) []byte {
+	if !bytes.HasPrefix(src, []byte("C ")) {
+		return src
+	}
+	end := bytes.IndexByte(src, '\n')
+	if end < 0 {
+		end = len(src)
+	}
+	out := make([]byte, 0, len(src)+len(name))
+	out = append(out, "C "...)
+	out = append(out, name...)
+	return append(out, src[end:]...)
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package promoter_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/stage/analyser/promoter"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/c4-project/c4t/internal/subject/obs"
+)
+
+// ExampleRootName is a runnable example for RootName.
+func ExampleRootName() {
+	for _, n := range []string{"mp", "mp_3", "mp_h0123abcd", "mp_h0123abcd_12"} {
+		fmt.Println(promoter.RootName(n))
+	}
+
+	// Output:
+	// mp
+	// mp
+	// mp
+	// mp
+}
+
+// ExampleRename is a runnable example for Rename.
+func ExampleRename() {
+	fmt.Printf("%s", promoter.Rename([]byte("C mp\n{ x = 0; }\n"), "mp_h0123abcd"))
+
+	// Output:
+	// C mp_h0123abcd
+	// { x = 0; }
+}
+
+// TestPromoter_Promote tests promoting fuzzer outputs from a small analysis, both the first time and on a repeat.
+func TestPromoter_Promote(t *testing.T) {
+	t.Parallel()
+
+	scratch := t.TempDir()
+	dir := filepath.Join(t.TempDir(), "corpus")
+
+	an := testAnalysis(t, scratch)
+
+	p, err := promoter.Open(dir)
+	require.NoError(t, err, "opening promoter")
+
+	es, err := p.Promote(an)
+	require.NoError(t, err, "promoting")
+	require.Len(t, es, 2, "should promote mp_1 for its states and mp_2 for its warning")
+
+	assert.Equal(t, "mp_1", es[0].Origin)
+	assert.Equal(t, []promoter.Reason{promoter.ReasonStates}, es[0].Reasons)
+	assert.Equal(t, "mp_2", es[1].Origin)
+	assert.Equal(t, []promoter.Reason{promoter.ReasonWarnings}, es[1].Reasons)
+
+	for _, e := range es {
+		bs, err := os.ReadFile(filepath.Join(dir, e.File()))
+		require.NoError(t, err, "reading promoted file")
+		assert.Contains(t, string(bs), "C "+e.Name+"\n", "promoted file should be renamed")
+	}
+
+	es, err = p.Promote(an)
+	require.NoError(t, err, "promoting again")
+	assert.Empty(t, es, "nothing should be new the second time around")
+
+	// The promoter's state should survive reopening.
+	p2, err := promoter.Open(dir)
+	require.NoError(t, err, "reopening promoter")
+	assert.Equal(t, p.Entries(), p2.Entries())
+	es, err = p2.Promote(an)
+	require.NoError(t, err, "promoting after reopening")
+	assert.Empty(t, es, "nothing should be new after reopening")
+}
+
+// TestPromoter_Promote_dedup tests that the promoter doesn't promote the same contents twice.
+func TestPromoter_Promote_dedup(t *testing.T) {
+	t.Parallel()
+
+	scratch := t.TempDir()
+	p, err := promoter.Open(t.TempDir())
+	require.NoError(t, err, "opening promoter")
+
+	an := testAnalysis(t, scratch)
+	_, err = p.Promote(an)
+	require.NoError(t, err, "promoting")
+
+	// Same contents, new behaviour.
+	s := an.Plan.Corpus["mp_1"]
+	s.Compilations[id.FromString("gcc")].Run.Obs.States[0].Values["x"] = "2"
+	es, err := p.Promote(an)
+	require.NoError(t, err, "promoting again")
+	assert.Empty(t, es, "contents already promoted shouldn't be promoted again")
+}
+
+// TestPromoter_Promote_addedVariables tests that fuzzer outputs that differ only in the variables they add to their
+// seed aren't promoted for having new states.
+func TestPromoter_Promote_addedVariables(t *testing.T) {
+	t.Parallel()
+
+	scratch := t.TempDir()
+	p, err := promoter.Open(t.TempDir())
+	require.NoError(t, err, "opening promoter")
+
+	gcc := id.FromString("gcc")
+	run := func(v obs.Valuation) compilation.Map {
+		o := obs.Obs{States: []obs.State{{Values: v}}}
+		return compilation.Map{gcc: {Run: &compilation.RunResult{Obs: &o}}}
+	}
+	fuzz := func(name string) *subject.Fuzz {
+		path := filepath.Join(scratch, name+".litmus")
+		require.NoError(t, os.WriteFile(path, []byte("C sb\n// "+name+"\n"), 0644), "writing fuzz output")
+		return &subject.Fuzz{Litmus: *litmus.NewOrPanic(filepath.ToSlash(path))}
+	}
+
+	an := &analysis.Analysis{
+		Plan: &plan.Plan{Metadata: *plan.NewMetadata(0), Corpus: corpus.Corpus{
+			"sb_1": {Fuzz: fuzz("sb_1"), Compilations: run(obs.Valuation{"x": "1", "y": "0", "gen1": "0"})},
+			"sb_2": {Fuzz: fuzz("sb_2"), Compilations: run(obs.Valuation{"x": "1", "y": "0", "gen2": "4"})},
+		}},
+	}
+	es, err := p.Promote(an)
+	require.NoError(t, err, "promoting")
+	require.Len(t, es, 1, "only one of the outputs should be promoted")
+	assert.Equal(t, "sb_1", es[0].Origin)
+
+	// Now with the seed present, neither output shows anything the seed didn't.
+	p, err = promoter.Open(t.TempDir())
+	require.NoError(t, err, "opening promoter")
+	an.Plan.Corpus["sb"] = subject.Subject{Compilations: run(obs.Valuation{"x": "1", "y": "0"})}
+	es, err = p.Promote(an)
+	require.NoError(t, err, "promoting with seed")
+	assert.Empty(t, es, "neither output should be promoted")
+}
+
+// testAnalysis makes an analysis with two fuzzer outputs and one unfuzzed seed, writing the outputs to dir.
+//
+// The seed and first output observe the same state, but the first output also observes a new one; the second output
+// observes only states the first did, but raises a warning.
+func testAnalysis(t *testing.T, dir string) *analysis.Analysis {
+	t.Helper()
+
+	gcc := id.FromString("gcc")
+	run := func(xs ...string) compilation.Map {
+		o := obs.Obs{}
+		for _, x := range xs {
+			o.States = append(o.States, obs.State{Values: obs.Valuation{"x": x}})
+		}
+		return compilation.Map{gcc: {Run: &compilation.RunResult{Obs: &o}}}
+	}
+	fuzz := func(name string) *subject.Fuzz {
+		path := filepath.Join(dir, name+".litmus")
+		require.NoError(t, os.WriteFile(path, []byte("C mp\n// "+name+"\n"), 0644), "writing fuzz output")
+		return &subject.Fuzz{Litmus: *litmus.NewOrPanic(filepath.ToSlash(path))}
+	}
+
+	md := plan.NewMetadata(0)
+	md.Creation = time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	c := corpus.Corpus{
+		"mp":   {Compilations: run("0")},
+		"mp_1": {Fuzz: fuzz("mp_1"), Compilations: run("0", "1")},
+		"mp_2": {Fuzz: fuzz("mp_2"), Compilations: run("1")},
+	}
+	return &analysis.Analysis{
+		Plan: &plan.Plan{Metadata: *md, Corpus: c},
+		Compilers: map[id.ID]analysis.Compiler{
+			gcc: {
+				Info: compiler.Instance{},
+				Warnings: analysis.WarningSet{
+					"-Wunused": {Kind: "-Wunused", Count: 1, Subjects: []string{"mp_2"}},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package sarif handles outputting of compiler errors and warnings in analyses as SARIF, for consumption by CI
+// dashboards.
+package sarif
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/subject/status"
+)
+
+const (
+	// Version is the version of SARIF emitted by this package.
+	Version = "2.1.0"
+	// Schema is the URI of the JSON schema for the version of SARIF emitted by this package.
+	Schema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	// RuleCompileFail is the rule ID used for compile failures whose logs contain no parseable errors.
+	RuleCompileFail = "c4t-compile-fail"
+)
+
+// Writer outputs the compiler errors and warnings in analyses as SARIF.
+//
+// Each compiler becomes a SARIF run.  Each error or warning that can be parsed out of a compiler log becomes a result,
+// with its rule ID being the warning flag if the compiler gave one, and the severity otherwise.
+type Writer struct {
+	w io.Writer
+}
+
+// NewWriter creates a new SARIF writer over w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// OnAnalysis observes an analysis by emitting its compiler diagnostics as SARIF; if an error occurs, it tries to
+// rescue.
+func (s *Writer) OnAnalysis(a analysis.Analysis) {
+	if err := s.Write(a); err != nil {
+		_, _ = fmt.Fprintf(s.w, "ERROR OUTPUTTING ANALYSIS: %s\n", err)
+	}
+}
+
+// Write writes the compiler diagnostics in a as SARIF.
+func (s *Writer) Write(a analysis.Analysis) error {
+	l, err := NewLog(a)
+	if err != nil {
+		return err
+	}
+	e := json.NewEncoder(s.w)
+	e.SetIndent("", "  ")
+	return e.Encode(l)
+}
+
+// Log is the root object of a SARIF document.
+type Log struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+// Run is a SARIF run, which we use to represent the diagnostics from one compiler.
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+// Tool describes the tool that produced a run's results.
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+// Driver describes the main component of a tool.
+type Driver struct {
+	Name  string `json:"name"`
+	Rules []Rule `json:"rules,omitempty"`
+}
+
+// Rule describes a rule that a result can reference.
+type Rule struct {
+	ID string `json:"id"`
+}
+
+// Result is a single SARIF result, which we use to represent a compiler error or warning.
+type Result struct {
+	RuleID     string     `json:"ruleId"`
+	Level      string     `json:"level"`
+	Message    Message    `json:"message"`
+	Locations  []Location `json:"locations,omitempty"`
+	Properties Properties `json:"properties"`
+}
+
+// Message is a SARIF message.
+type Message struct {
+	Text string `json:"text"`
+}
+
+// Location is a SARIF location.
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+// PhysicalLocation is a location in a file.
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           *Region          `json:"region,omitempty"`
+}
+
+// ArtifactLocation names a file.
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// Region is a region within a file.
+type Region struct {
+	StartLine   int `json:"startLine,omitempty"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// Properties holds the c4t-specific properties of a result.
+type Properties struct {
+	// Subject is the name of the subject whose compilation produced the result.
+	Subject string `json:"subject"`
+	// Status is the final status of that compilation.
+	Status status.Status `json:"status"`
+}
+
+// NewLog builds a SARIF log from the compiler logs in the analysis a.
+func NewLog(a analysis.Analysis) (Log, error) {
+	l := Log{Schema: Schema, Version: Version, Runs: []Run{}}
+
+	cids := make([]id.ID, 0, len(a.Compilers))
+	for cid := range a.Compilers {
+		cids = append(cids, cid)
+	}
+	sort.Slice(cids, func(i, j int) bool { return cids[i].Less(cids[j]) })
+
+	for _, cid := range cids {
+		r, err := newRun(cid, a.Compilers[cid])
+		if err != nil {
+			return l, fmt.Errorf("compiler %s: %w", cid, err)
+		}
+		l.Runs = append(l.Runs, r)
+	}
+	return l, nil
+}
+
+func newRun(cid id.ID, c analysis.Compiler) (Run, error) {
+	r := Run{Tool: Tool{Driver: Driver{Name: cid.String()}}, Results: []Result{}}
+
+	snames := make([]string, 0, len(c.Logs))
+	for sname := range c.Logs {
+		snames = append(snames, sname)
+	}
+	sort.Strings(snames)
+
+	rules := map[string]struct{}{}
+	for _, sname := range snames {
+		rs, err := results(sname, c.Statuses[sname], c.Logs[sname])
+		if err != nil {
+			return r, fmt.Errorf("subject %s: %w", sname, err)
+		}
+		for _, res := range rs {
+			rules[res.RuleID] = struct{}{}
+			r.Results = append(r.Results, res)
+		}
+	}
+
+	for rule := range rules {
+		r.Tool.Driver.Rules = append(r.Tool.Driver.Rules, Rule{ID: rule})
+	}
+	sort.Slice(r.Tool.Driver.Rules, func(i, j int) bool { return r.Tool.Driver.Rules[i].ID < r.Tool.Driver.Rules[j].ID })
+	return r, nil
+}
+
+// results gets the SARIF results for the compiler log clog of subject sname, whose compilation had status st.
+func results(sname string, st status.Status, clog string) ([]Result, error) {
+	ds, err := compiler.ParseDiagnostics(clog)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		rs     []Result
+		errors bool
+	)
+	props := Properties{Subject: sname, Status: st}
+	for _, d := range ds {
+		if d.Severity == compiler.SeverityNote {
+			continue
+		}
+		errors = errors || d.Severity == compiler.SeverityError
+		rs = append(rs, newResult(d, props))
+	}
+	if st == status.CompileFail && !errors {
+		rs = append(rs, Result{
+			RuleID:     RuleCompileFail,
+			Level:      string(compiler.SeverityError),
+			Message:    Message{Text: analysis.NormaliseLog(clog)},
+			Properties: props,
+		})
+	}
+	return rs, nil
+}
+
+func newResult(d compiler.Diagnostic, props Properties) Result {
+	r := Result{
+		RuleID:     d.WarningFlag(),
+		Level:      string(d.Severity),
+		Message:    Message{Text: d.Message},
+		Properties: props,
+	}
+	if r.RuleID == "" {
+		r.RuleID = string(d.Severity)
+	}
+
+	loc := PhysicalLocation{ArtifactLocation: ArtifactLocation{URI: FileURI(d.File)}}
+	if 0 < d.Line {
+		loc.Region = &Region{StartLine: d.Line, StartColumn: d.Column}
+	}
+	r.Locations = []Location{{PhysicalLocation: loc}}
+	return r
+}
+
+// FileURI converts the file path path, as reported by a compiler, to a URI suitable for a SARIF artifact location.
+//
+// Absolute paths become 'file' URIs; relative paths become relative URI references.  In both cases, path separators
+// become slashes, and any characters not allowed in URI paths are escaped.
+func FileURI(path string) string {
+	u := url.URL{Path: filepath.ToSlash(path)}
+	if filepath.IsAbs(path) {
+		u.Scheme = "file"
+		if !strings.HasPrefix(u.Path, "/") {
+			// Windows paths such as C:/foo need a leading slash to become URI paths.
+			u.Path = "/" + u.Path
+		}
+	}
+	return u.String()
+}
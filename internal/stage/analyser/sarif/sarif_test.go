@@ -0,0 +1,111 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package sarif_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/stage/analyser/sarif"
+	"github.com/c4-project/c4t/internal/subject/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAnalysis() analysis.Analysis {
+	return analysis.Analysis{
+		Compilers: map[id.ID]analysis.Compiler{
+			id.FromString("gcc"): {
+				Logs: map[string]string{
+					"foo": "foo.c:12:7: warning: 'r0' may be used uninitialized [-Wmaybe-uninitialized]\n" +
+						"foo.c:3:1: note: declared here",
+					"bar": "cc1: out of memory allocating 65536 bytes",
+					"baz": "",
+				},
+				Statuses: map[string]status.Status{
+					"foo": status.Ok,
+					"bar": status.CompileFail,
+					"baz": status.Ok,
+				},
+			},
+			id.FromString("clang"): {
+				Logs: map[string]string{
+					"foo": "foo.c:20: error: expected ';' before '}' token",
+					"bar": "/tmp/my dir/bar.c:1:2: error: unused variable 'x' [-Werror=unused-variable]",
+				},
+				Statuses: map[string]status.Status{"foo": status.CompileFail, "bar": status.CompileFail},
+			},
+		},
+	}
+}
+
+// TestNewLog tests NewLog on a small analysis.
+func TestNewLog(t *testing.T) {
+	t.Parallel()
+
+	l, err := sarif.NewLog(testAnalysis())
+	require.NoError(t, err, "building log")
+	assert.Equal(t, sarif.Version, l.Version)
+	require.Len(t, l.Runs, 2, "should be one run per compiler")
+
+	clang := l.Runs[0]
+	assert.Equal(t, "clang", clang.Tool.Driver.Name)
+	assert.Equal(t, []sarif.Rule{{ID: "-Wunused-variable"}, {ID: "error"}}, clang.Tool.Driver.Rules)
+	require.Len(t, clang.Results, 2)
+	assert.Equal(t, "-Wunused-variable", clang.Results[0].RuleID, "promoted warnings should use the warning flag")
+	assert.Equal(t, "file:///tmp/my%20dir/bar.c",
+		clang.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI, "absolute paths should be file URIs")
+	assert.Equal(t, sarif.Result{
+		RuleID:  "error",
+		Level:   "error",
+		Message: sarif.Message{Text: "expected ';' before '}' token"},
+		Locations: []sarif.Location{{PhysicalLocation: sarif.PhysicalLocation{
+			ArtifactLocation: sarif.ArtifactLocation{URI: "foo.c"},
+			Region:           &sarif.Region{StartLine: 20},
+		}}},
+		Properties: sarif.Properties{Subject: "foo", Status: status.CompileFail},
+	}, clang.Results[1])
+
+	gcc := l.Runs[1]
+	assert.Equal(t, "gcc", gcc.Tool.Driver.Name)
+	assert.Equal(t, []sarif.Rule{{ID: "-Wmaybe-uninitialized"}, {ID: sarif.RuleCompileFail}}, gcc.Tool.Driver.Rules)
+	require.Len(t, gcc.Results, 2, "notes and empty logs shouldn't produce results")
+	assert.Equal(t, sarif.RuleCompileFail, gcc.Results[0].RuleID, "unparseable compile failure")
+	assert.Equal(t, "bar", gcc.Results[0].Properties.Subject)
+	assert.Equal(t, "-Wmaybe-uninitialized", gcc.Results[1].RuleID)
+	assert.Equal(t, "warning", gcc.Results[1].Level)
+}
+
+// ExampleFileURI is a runnable example for FileURI.
+func ExampleFileURI() {
+	for _, p := range []string{"foo.c", "sub dir/foo.c", "/tmp/foo.c", "c:foo.c"} {
+		fmt.Println(sarif.FileURI(p))
+	}
+
+	// Output:
+	// foo.c
+	// sub%20dir/foo.c
+	// file:///tmp/foo.c
+	// ./c:foo.c
+}
+
+// TestWriter_Write tests that Writer.Write produces JSON that round-trips to the result of NewLog.
+func TestWriter_Write(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	require.NoError(t, sarif.NewWriter(&b).Write(testAnalysis()))
+
+	var got sarif.Log
+	require.NoError(t, json.Unmarshal(b.Bytes(), &got), "output should be valid JSON")
+	want, err := sarif.NewLog(testAnalysis())
+	require.NoError(t, err, "building log")
+	assert.Equal(t, want, got)
+}
@@ -6,9 +6,15 @@
 package saver
 
 import (
-	"time"
+	"context"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
 
 	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/subject/normpath"
 
 	"github.com/c4-project/c4t/internal/plan"
 	"github.com/c4-project/c4t/internal/subject/corpus"
@@ -20,18 +26,18 @@ type bucketSaver struct {
 	parent   *Saver
 	s        status.Status
 	plan     *plan.Plan
+	analysis *analysis.Analysis
 	paths    *RunPathset
-	creation time.Time
 }
 
-func (b *bucketSaver) save(c corpus.Corpus) error {
+func (b *bucketSaver) save(ctx context.Context, c corpus.Corpus) error {
 	if err := b.paths.Prepare(); err != nil {
 		return err
 	}
 	if err := b.writePlan(c); err != nil {
 		return err
 	}
-	return b.archiveSubjects(c)
+	return b.archiveSubjects(ctx, c)
 }
 
 func (b *bucketSaver) writePlan(c corpus.Corpus) error {
@@ -41,23 +47,74 @@ func (b *bucketSaver) writePlan(c corpus.Corpus) error {
 	return pn.WriteFile(b.paths.FilePlan, plan.WriteCompress|plan.WriteHuman)
 }
 
-func (b *bucketSaver) archiveSubjects(corp corpus.Corpus) error {
+func (b *bucketSaver) archiveSubjects(ctx context.Context, corp corpus.Corpus) error {
 	for name := range corp {
-		if err := b.archiveSubject(name); err != nil {
+		if err := b.archiveSubject(ctx, name); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (b *bucketSaver) archiveSubject(name string) error {
-	path := b.paths.SubjectTarFile(name)
-	ar, err := b.parent.archiveMaker(path)
+func (b *bucketSaver) archiveSubject(ctx context.Context, name string) error {
+	apath := b.paths.SubjectArchiveFile(name, b.parent.suffix)
+	ar, err := b.parent.archiveMaker(apath)
 	if err != nil {
 		return err
 	}
 	nameMap := b.parent.norm.BySubject[name].Mappings
-	aerr := ArchiveSubject(ar, name, path, nameMap, b.parent.observers...)
+	aerr := ArchiveSubject(ar, name, apath, nameMap, b.parent.observers...)
+	if aerr == nil {
+		aerr = b.archiveRepro(ctx, ar, name)
+	}
 	cerr := ar.Close()
 	return errhelp.FirstError(aerr, cerr)
 }
+
+// archiveRepro generates the reproduction bundle for subject name, and archives it into ar.
+func (b *bucketSaver) archiveRepro(ctx context.Context, ar Archiver, name string) error {
+	dir, err := os.MkdirTemp("", "c4t-repro")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	m, err := NewManifest(ctx, b.plan, b.analysis, name, b.parent.cdriver)
+	if err != nil {
+		return err
+	}
+	files := []struct {
+		name  string
+		mode  int64
+		write func(string) error
+	}{
+		{name: normpath.FileReproManifest, mode: 0644, write: func(p string) error { return writeFileWith(p, m.Write) }},
+		{name: normpath.FileReproScript, mode: 0755, write: func(p string) error { return writeFileWith(p, m.WriteScript) }},
+		{name: normpath.FileReproPlan, mode: 0644, write: func(p string) error {
+			pn := *b.plan
+			pn.Corpus = pn.Corpus.FilterToNames(name)
+			return pn.WriteFile(p, plan.WriteHuman)
+		}},
+	}
+	for _, f := range files {
+		rpath := filepath.Join(dir, f.name)
+		if err := f.write(rpath); err != nil {
+			return err
+		}
+		if err := ar.ArchiveFile(rpath, path.Join(name, f.name), f.mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFileWith creates the file at path and writes to it using w.
+func writeFileWith(path string, w func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	werr := w(f)
+	cerr := f.Close()
+	return errhelp.FirstError(werr, cerr)
+}
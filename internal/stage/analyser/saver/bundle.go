@@ -0,0 +1,124 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package saver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/subject/normaliser"
+	"github.com/c4-project/c4t/internal/subject/normpath"
+)
+
+// ErrNotBundle occurs when we try to open a saved subject that doesn't have a reproduction bundle.
+var ErrNotBundle = errors.New("not a reproduction bundle")
+
+// Bundle is a saved subject, unpacked so that it can be reproduced.
+type Bundle struct {
+	// Root is the directory in which the subject's directory sits; normalised paths are relative to this.
+	Root string
+	// Manifest is the subject's reproduction manifest.
+	Manifest *Manifest
+	// Plan is the single-subject plan for the subject, with its paths resolved against Root.
+	Plan *plan.Plan
+
+	// tmp is, if non-empty, the temporary directory into which the archive was unpacked.
+	tmp string
+}
+
+// OpenBundle opens the saved subject archive at path as a reproduction bundle.
+//
+// Archives are unpacked into a temporary directory, which Close removes; if the subject was saved in FormatDir, path
+// should be the subject's directory, and the bundle is used in place.
+func OpenBundle(path string) (*Bundle, error) {
+	f, err := FormatFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if f == FormatDir {
+		return loadBundle(filepath.Dir(path), filepath.Base(path), "")
+	}
+
+	tmp, err := os.MkdirTemp("", "c4t-repro")
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Extract(path, tmp); err != nil {
+		_ = os.RemoveAll(tmp)
+		return nil, fmt.Errorf("extracting %s: %w", path, err)
+	}
+	name, err := bundleSubject(tmp)
+	if err != nil {
+		_ = os.RemoveAll(tmp)
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	b, err := loadBundle(tmp, name, tmp)
+	if err != nil {
+		_ = os.RemoveAll(tmp)
+	}
+	return b, err
+}
+
+// bundleSubject gets the name of the single subject directory unpacked into dir.
+func bundleSubject(dir string) (string, error) {
+	ents, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(ents) != 1 || !ents[0].IsDir() {
+		return "", fmt.Errorf("%w: expected a single subject directory", ErrNotBundle)
+	}
+	return ents[0].Name(), nil
+}
+
+func loadBundle(root, name, tmp string) (*Bundle, error) {
+	sdir := filepath.Join(root, name)
+	m, err := ReadManifest(filepath.Join(sdir, normpath.FileReproManifest))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = fmt.Errorf("%w: %s has no %s", ErrNotBundle, name, normpath.FileReproManifest)
+		}
+		return nil, err
+	}
+	var p plan.Plan
+	if err := plan.ReadFile(filepath.Join(sdir, normpath.FileReproPlan), &p); err != nil {
+		return nil, err
+	}
+	// The plan's paths are normalised, so re-normalising them against the root resolves them to the unpacked files.
+	if p.Corpus, err = normaliser.NewCorpus(filepath.ToSlash(root)).Normalise(p.Corpus); err != nil {
+		return nil, err
+	}
+	return &Bundle{Root: root, Manifest: m, Plan: &p, tmp: tmp}, nil
+}
+
+// Reproduce re-runs the bundle's subject through the machine stage r, and compares the results to the manifest.
+func (b *Bundle) Reproduce(ctx context.Context, r plan.Runner) ([]ReproOutcome, error) {
+	p := *b.Plan
+	p.Corpus = p.Corpus.Copy()
+	p.Corpus.EraseCompilations()
+	rp, err := p.RunStage(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	a, err := analysis.Analyse(ctx, rp)
+	if err != nil {
+		return nil, err
+	}
+	return b.Manifest.Compare(a), nil
+}
+
+// Close removes any temporary directory into which the bundle was unpacked.
+func (b *Bundle) Close() error {
+	if b.tmp == "" {
+		return nil
+	}
+	return os.RemoveAll(b.tmp)
+}
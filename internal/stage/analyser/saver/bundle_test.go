@@ -0,0 +1,106 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package saver_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/plan/stage"
+	"github.com/c4-project/c4t/internal/stage/analyser/saver"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/normpath"
+	"github.com/c4-project/c4t/internal/subject/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cimpl "github.com/c4-project/c4t/internal/serviceimpl/compiler"
+)
+
+// fakeMach is a plan runner that pretends to compile and run every subject, giving each compiler a fixed status.
+type fakeMach map[string]status.Status
+
+func (fakeMach) Stage() stage.Stage {
+	return stage.Mach
+}
+
+func (f fakeMach) Run(_ context.Context, p *plan.Plan) (*plan.Plan, error) {
+	for name, s := range p.Corpus {
+		for cid, st := range f {
+			s.Compilations[id.FromString(cid)] = compilation.Compilation{
+				Compile: &compilation.CompileResult{Result: compilation.Result{Status: status.Ok}},
+				Run:     &compilation.RunResult{Result: compilation.Result{Status: st}},
+			}
+		}
+		p.Corpus[name] = s
+	}
+	return p, nil
+}
+
+func (fakeMach) Close() error {
+	return nil
+}
+
+// TestOpenBundle tests that subjects saved in each format can be opened and reproduced as bundles.
+func TestOpenBundle(t *testing.T) {
+	t.Parallel()
+
+	p := reproPlan()
+	an, err := analysis.Analyse(context.Background(), p)
+	require.NoError(t, err, "analysing plan")
+
+	for f := saver.FormatTGZ; f <= saver.FormatLast; f++ {
+		f := f
+		t.Run(f.String(), func(t *testing.T) {
+			t.Parallel()
+			if f == saver.FormatTarZstd {
+				if _, err := exec.LookPath(saver.ZstdCommand); err != nil {
+					t.Skip("zstd not available")
+				}
+			}
+
+			ps := saver.NewPathset(t.TempDir())
+			s, err := saver.New(ps, f.Create, saver.ArchiveSuffix(f.Suffix()), saver.ReproduceWith(&cimpl.CResolve))
+			require.NoError(t, err, "constructing saver")
+			require.NoError(t, s.Run(context.Background(), *an), "saving")
+
+			paths, err := filepath.Glob(filepath.Join(ps.Root, "*", "*", "*", "*", "*", "foo"+f.Suffix()))
+			require.NoError(t, err, "finding archive")
+			require.Len(t, paths, 1, "should be one saved archive")
+
+			b, err := saver.OpenBundle(paths[0])
+			require.NoError(t, err, "opening bundle")
+			defer func() { assert.NoError(t, b.Close(), "closing bundle") }()
+
+			assert.Equal(t, "foo", b.Manifest.Subject)
+			assert.Equal(t, status.Flagged, b.Manifest.Compilations["gcc"].Observed)
+
+			sdir := filepath.Join(b.Root, "foo")
+			info, err := os.Stat(filepath.Join(sdir, normpath.FileReproScript))
+			require.NoError(t, err, "bundle should contain a script")
+			assert.NotZero(t, info.Mode().Perm()&0100, "script should be executable")
+
+			fs := b.Plan.Corpus["foo"]
+			assert.Equal(t, filepath.ToSlash(filepath.Join(sdir, normpath.FileOrigLitmus)), fs.Source.Path)
+			assert.Equal(t, filepath.ToSlash(filepath.Join(sdir, normpath.DirRecipes, "x86")), fs.Recipes[id.ArchX86].Dir)
+
+			outs, err := b.Reproduce(context.Background(), fakeMach{"gcc": status.Flagged, "clang": status.Ok})
+			require.NoError(t, err, "reproducing")
+			assert.Equal(t, []saver.ReproOutcome{{Compiler: "gcc", Observed: status.Flagged, Now: status.Flagged}}, outs)
+			assert.True(t, outs[0].Reproduced(), "gcc outcome should reproduce")
+
+			outs, err = b.Reproduce(context.Background(), fakeMach{"gcc": status.Ok, "clang": status.Ok})
+			require.NoError(t, err, "reproducing")
+			assert.False(t, outs[0].Reproduced(), "gcc outcome should no longer reproduce")
+		})
+	}
+}
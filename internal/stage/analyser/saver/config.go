@@ -0,0 +1,118 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package saver
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/c4-project/c4t/internal/remote"
+	"github.com/mitchellh/go-homedir"
+)
+
+// Config configures how the saver archives subjects, where it sends them, and how long it keeps them.
+type Config struct {
+	// Format is the archive format in which subjects are saved.
+	Format Format `toml:"format,omitzero" json:"format,omitempty"`
+	// Target, if non-empty, is a URL describing a target to which saved runs are sent; see OpenTarget.
+	Target string `toml:"target,omitempty" json:"target,omitempty"`
+	// Retention is the policy for pruning old saved runs.
+	Retention Retention `toml:"retention,omitempty" json:"retention,omitempty"`
+}
+
+// ErrBadTarget occurs when OpenTarget can't understand a target URL.
+var ErrBadTarget = errors.New("bad save target")
+
+const (
+	// DefaultS3Region is the region used for S3 targets that don't specify one.
+	DefaultS3Region = "us-east-1"
+)
+
+// OpenTarget opens the target described by the URL rawurl, using the SSH configuration ssh if needed.
+//
+// The URL can take the following forms:
+//
+//   - a plain path or file:// URL, denoting a local directory;
+//   - sftp://[user@]host[:port]/dir, denoting a directory on an SFTP host;
+//   - s3://bucket[/prefix][?endpoint=URL&region=REGION], denoting a bucket on an S3-compatible object store.
+//     Without an endpoint, we assume Amazon S3 itself.  Credentials come from the standard AWS environment variables.
+//
+// If rawurl is empty, OpenTarget returns nil, meaning that saved runs stay where the saver put them.
+func OpenTarget(rawurl string, ssh *remote.Config) (Target, error) {
+	if rawurl == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadTarget, err)
+	}
+	switch u.Scheme {
+	case "", "file":
+		return openDirTarget(u)
+	case "sftp":
+		return openSFTPTarget(u, ssh)
+	case "s3":
+		return openS3Target(u)
+	default:
+		return nil, fmt.Errorf("%w: unsupported scheme %q", ErrBadTarget, u.Scheme)
+	}
+}
+
+func openDirTarget(u *url.URL) (Target, error) {
+	root, err := homedir.Expand(u.Path)
+	if err != nil {
+		return nil, err
+	}
+	if root == "" {
+		return nil, fmt.Errorf("%w: no directory given", ErrBadTarget)
+	}
+	return DirTarget{Root: root}, nil
+}
+
+func openSFTPTarget(u *url.URL, ssh *remote.Config) (Target, error) {
+	mc := remote.MachineConfig{Host: u.Hostname(), User: u.User.Username()}
+	if p := u.Port(); p != "" {
+		var err error
+		if mc.Port, err = strconv.Atoi(p); err != nil {
+			return nil, fmt.Errorf("%w: bad port %q", ErrBadTarget, p)
+		}
+	}
+	mr, err := mc.MachineRunner(ssh)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %s: %w", mc.Host, err)
+	}
+	cli, err := mr.NewSFTP()
+	if err != nil {
+		_ = mr.Close()
+		return nil, fmt.Errorf("opening SFTP session on %s: %w", mc.Host, err)
+	}
+	return &SFTPTarget{Client: cli, Root: u.Path, closer: mr}, nil
+}
+
+func openS3Target(u *url.URL) (Target, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("%w: no bucket given", ErrBadTarget)
+	}
+	q := u.Query()
+	region := q.Get("region")
+	if region == "" {
+		region = DefaultS3Region
+	}
+	endpoint := q.Get("endpoint")
+	if endpoint == "" {
+		endpoint = "https://s3." + region + ".amazonaws.com"
+	}
+	return &S3Target{
+		Endpoint:    endpoint,
+		Bucket:      u.Host,
+		Prefix:      strings.Trim(u.Path, "/"),
+		Region:      region,
+		Credentials: S3CredentialsFromEnv(),
+	}, nil
+}
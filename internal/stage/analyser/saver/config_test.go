@@ -0,0 +1,86 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package saver_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/stage/analyser/saver"
+)
+
+// TestOpenTarget tests OpenTarget on URLs that don't need network access to open.
+func TestOpenTarget(t *testing.T) {
+	// Not parallel, as we clear any S3 credentials in the environment.
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+
+	cases := map[string]struct {
+		url  string
+		want saver.Target
+		err  error
+	}{
+		"none":      {url: ""},
+		"path":      {url: "/srv/c4t", want: saver.DirTarget{Root: "/srv/c4t"}},
+		"file":      {url: "file:///srv/c4t", want: saver.DirTarget{Root: "/srv/c4t"}},
+		"file-none": {url: "file://", err: saver.ErrBadTarget},
+		"s3": {
+			url: "s3://saves/c4t/runs/",
+			want: &saver.S3Target{
+				Endpoint: "https://s3.us-east-1.amazonaws.com", Bucket: "saves", Prefix: "c4t/runs", Region: "us-east-1",
+			},
+		},
+		"s3-endpoint": {
+			url: "s3://saves?endpoint=http://localhost:9000&region=eu-west-2",
+			want: &saver.S3Target{
+				Endpoint: "http://localhost:9000", Bucket: "saves", Region: "eu-west-2",
+			},
+		},
+		"s3-none":   {url: "s3:///c4t", err: saver.ErrBadTarget},
+		"sftp-port": {url: "sftp://host:port/dir", err: saver.ErrBadTarget},
+		"bad":       {url: "gopher://example.com", err: saver.ErrBadTarget},
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			got, err := saver.OpenTarget(c.url, nil)
+			if c.err != nil {
+				assert.ErrorIs(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.want, got)
+		})
+	}
+}
+
+// TestConfig_toml tests that save configuration decodes from TOML.
+func TestConfig_toml(t *testing.T) {
+	t.Parallel()
+
+	const in = `
+format = "zip"
+target = "s3://saves"
+
+[retention]
+max_age = "168h"
+max_size = "10G"
+`
+	var cfg saver.Config
+	_, err := toml.NewDecoder(strings.NewReader(in)).Decode(&cfg)
+	require.NoError(t, err)
+	assert.Equal(t, saver.Config{
+		Format:    saver.FormatZip,
+		Target:    "s3://saves",
+		Retention: saver.Retention{MaxAge: 168 * time.Hour, MaxSize: 10 << 30},
+	}, cfg)
+}
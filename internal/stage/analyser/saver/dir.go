@@ -0,0 +1,63 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package saver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/c4-project/c4t/internal/helper/iohelp"
+)
+
+// DirWriter is an Archiver that copies files into a plain directory.
+//
+// Archive paths in the saver start with the subject name, which is also the base name of the directory; DirWriter
+// strips this so that, as with tarballs, the file ends up at the same place relative to the run directory.
+type DirWriter struct {
+	root string
+}
+
+// CreateDir creates the directory at path and opens a DirWriter into it.
+func CreateDir(path string) (*DirWriter, error) {
+	if err := iohelp.Mkdirs(path); err != nil {
+		return nil, fmt.Errorf("create %s: %w", path, err)
+	}
+	return &DirWriter{root: path}, nil
+}
+
+// Close does nothing.
+func (*DirWriter) Close() error {
+	return nil
+}
+
+// ArchiveFile copies the file at rpath to wpath within this directory, and with the flags mode.
+// If rpath is empty, no copying occurs.
+func (d *DirWriter) ArchiveFile(rpath, wpath string, mode int64) error {
+	if rpath == "" {
+		return nil
+	}
+	src, err := os.Open(rpath)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", rpath, err)
+	}
+	wpath = strings.TrimPrefix(wpath, filepath.Base(d.root)+"/")
+	dpath := filepath.Join(d.root, filepath.FromSlash(wpath))
+	if err := iohelp.Mkdirs(filepath.Dir(dpath)); err != nil {
+		_ = src.Close()
+		return err
+	}
+	dst, err := os.OpenFile(dpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.FileMode(mode))
+	if err != nil {
+		_ = src.Close()
+		return fmt.Errorf("creating %s: %w", dpath, err)
+	}
+	if _, err = iohelp.CopyClose(dst, src); err != nil {
+		return fmt.Errorf("archiving %s: %w", rpath, err)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package saver
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/helper/iohelp"
+)
+
+// ErrUnsafePath occurs when an archive being extracted contains a path that would escape the extraction directory.
+var ErrUnsafePath = errors.New("archive path escapes extraction directory")
+
+// FormatFromPath guesses the format of the saved subject archive at path from its suffix.
+// Directories are taken to be in FormatDir.
+func FormatFromPath(path string) (Format, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return FormatDir, nil
+	}
+	for f := FormatTGZ; f <= FormatLast; f++ {
+		if s := f.Suffix(); s != "" && strings.HasSuffix(path, s) {
+			return f, nil
+		}
+	}
+	return FormatTGZ, fmt.Errorf("%w: can't tell format of %s", ErrBadFormat, path)
+}
+
+// Extract unpacks the archive at path, which is in this format, into the directory dir.
+// Directories can't be extracted.
+func (f Format) Extract(path, dir string) error {
+	switch f {
+	case FormatTGZ:
+		return extractTGZ(path, dir)
+	case FormatTarZstd:
+		return extractTarZstd(path, dir)
+	case FormatZip:
+		return extractZip(path, dir)
+	default:
+		return fmt.Errorf("%w: can't extract %s archives", ErrBadFormat, f)
+	}
+}
+
+func extractTGZ(path, dir string) error {
+	af, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	gr, err := gzip.NewReader(af)
+	if err != nil {
+		_ = af.Close()
+		return fmt.Errorf("opening gzip %s: %w", path, err)
+	}
+	terr := extractTar(tar.NewReader(gr), dir)
+	gerr := gr.Close()
+	ferr := af.Close()
+	return errhelp.FirstError(terr, gerr, ferr)
+}
+
+func extractTarZstd(path, dir string) error {
+	cmd := exec.Command(ZstdCommand, "-q", "-d", "-c", path)
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("piping from %s: %w", ZstdCommand, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting %s for %s: %w", ZstdCommand, path, err)
+	}
+	terr := extractTar(tar.NewReader(out), dir)
+	// Drain the pipe so that zstd doesn't block if we stopped reading early.
+	_, _ = io.Copy(io.Discard, out)
+	if werr := cmd.Wait(); werr != nil {
+		return fmt.Errorf("running %s: %w", ZstdCommand, werr)
+	}
+	return terr
+}
+
+func extractTar(tr *tar.Reader, dir string) error {
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading archive: %w", err)
+		}
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := extractFile(tr, dir, h.Name, h.FileInfo().Mode()); err != nil {
+			return err
+		}
+	}
+}
+
+func extractZip(path, dir string) error {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("opening zip %s: %w", path, err)
+	}
+	for _, zf := range zr.File {
+		if zf.FileInfo().IsDir() {
+			continue
+		}
+		if err = extractZipFile(zf, dir); err != nil {
+			break
+		}
+	}
+	return errhelp.FirstError(err, zr.Close())
+}
+
+func extractZipFile(zf *zip.File, dir string) error {
+	r, err := zf.Open()
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", zf.Name, err)
+	}
+	eerr := extractFile(r, dir, zf.Name, zf.Mode())
+	return errhelp.FirstError(eerr, r.Close())
+}
+
+// extractFile copies the contents of r to the file at archive path wpath, relative to dir, with permissions mode.
+func extractFile(r io.Reader, dir, wpath string, mode os.FileMode) error {
+	rel := filepath.FromSlash(wpath)
+	if !filepath.IsLocal(rel) {
+		return fmt.Errorf("%w: %s", ErrUnsafePath, wpath)
+	}
+	dpath := filepath.Join(dir, rel)
+	if err := iohelp.Mkdirs(filepath.Dir(dpath)); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(dpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", dpath, err)
+	}
+	_, cerr := io.Copy(f, r)
+	return errhelp.FirstError(cerr, f.Close())
+}
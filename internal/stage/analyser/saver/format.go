@@ -0,0 +1,95 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package saver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/c4-project/c4t/internal/subject/normpath"
+)
+
+// Format is the type of archive formats in which the saver can store subjects.
+type Format uint8
+
+const (
+	// FormatTGZ stores each subject as a gzipped tarball.
+	// This is the default, and the only archive format that archive-transparent readers such as
+	// normpath.ReadSubjectFile understand.
+	FormatTGZ Format = iota
+	// FormatTarZstd stores each subject as a zstd-compressed tarball, using the external `zstd` program.
+	FormatTarZstd
+	// FormatZip stores each subject as a zip file.
+	FormatZip
+	// FormatDir stores each subject as a plain directory.
+	FormatDir
+
+	// FormatLast is the last valid format.
+	FormatLast = FormatDir
+)
+
+var (
+	formatNames    = [...]string{FormatTGZ: "tgz", FormatTarZstd: "tzst", FormatZip: "zip", FormatDir: "dir"}
+	formatSuffixes = [...]string{FormatTGZ: normpath.TarSuffix, FormatTarZstd: ".tar.zst", FormatZip: ".zip", FormatDir: ""}
+
+	// ErrBadFormat occurs when FormatFromString encounters an unknown format string.
+	ErrBadFormat = errors.New("unknown archive format")
+)
+
+// FormatFromString tries to resolve s to an archive format.
+func FormatFromString(s string) (Format, error) {
+	for i := FormatTGZ; i <= FormatLast; i++ {
+		if strings.EqualFold(s, formatNames[i]) {
+			return i, nil
+		}
+	}
+	return FormatTGZ, fmt.Errorf("%w: %q (want one of %s)", ErrBadFormat, s, strings.Join(formatNames[:], ", "))
+}
+
+// String gets the name of this format.
+func (f Format) String() string {
+	if FormatLast < f {
+		return fmt.Sprintf("Format(%d)", f)
+	}
+	return formatNames[f]
+}
+
+// Suffix gets the suffix appended to subject names to get the names of their archives in this format.
+func (f Format) Suffix() string {
+	if FormatLast < f {
+		return ""
+	}
+	return formatSuffixes[f]
+}
+
+// Create creates an archive in this format at path.
+func (f Format) Create(path string) (Archiver, error) {
+	switch f {
+	case FormatTGZ:
+		return CreateTGZ(path)
+	case FormatTarZstd:
+		return CreateTarZstd(path)
+	case FormatZip:
+		return CreateZip(path)
+	case FormatDir:
+		return CreateDir(path)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrBadFormat, f)
+	}
+}
+
+// MarshalText marshals a Format to text via its string representation.
+func (f Format) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText unmarshals a Format from text via its string representation.
+func (f *Format) UnmarshalText(text []byte) error {
+	var err error
+	*f, err = FormatFromString(string(text))
+	return err
+}
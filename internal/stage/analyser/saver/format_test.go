@@ -0,0 +1,147 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package saver_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/stage/analyser/saver"
+)
+
+// ExampleFormatFromString is a runnable example for FormatFromString.
+func ExampleFormatFromString() {
+	for _, s := range []string{"tgz", "TZST", "zip", "dir", "rar"} {
+		f, err := saver.FormatFromString(s)
+		if err != nil {
+			fmt.Println("error:", err)
+			continue
+		}
+		fmt.Printf("%s: %q\n", f, f.Suffix())
+	}
+
+	// Output:
+	// tgz: ".tar.gz"
+	// tzst: ".tar.zst"
+	// zip: ".zip"
+	// dir: ""
+	// error: unknown archive format: "rar" (want one of tgz, tzst, zip, dir)
+}
+
+// TestFormat_Create tests that each format produces an archive from which we can recover archived files.
+func TestFormat_Create(t *testing.T) {
+	t.Parallel()
+
+	src := filepath.Join(t.TempDir(), "input.c")
+	require.NoError(t, os.WriteFile(src, []byte("int main(void) { return 0; }"), 0644), "writing input file")
+
+	cases := map[saver.Format]func(t *testing.T, path string) map[string]string{
+		saver.FormatTGZ:     readTGZ,
+		saver.FormatTarZstd: readTarZstd,
+		saver.FormatZip:     readZip,
+		saver.FormatDir:     readDir,
+	}
+	for f, read := range cases {
+		f, read := f, read
+		t.Run(f.String(), func(t *testing.T) {
+			t.Parallel()
+			if f == saver.FormatTarZstd {
+				if _, err := exec.LookPath(saver.ZstdCommand); err != nil {
+					t.Skip("zstd not available")
+				}
+			}
+
+			path := filepath.Join(t.TempDir(), "foo"+f.Suffix())
+			ar, err := f.Create(path)
+			require.NoError(t, err, "creating archive")
+			require.NoError(t, ar.ArchiveFile(src, "foo/main.c", 0644), "archiving file")
+			require.NoError(t, ar.ArchiveFile("", "foo/ignored.c", 0644), "archiving empty path should be a no-op")
+			require.NoError(t, ar.Close(), "closing archive")
+
+			assert.Equal(t, map[string]string{"foo/main.c": "int main(void) { return 0; }"}, read(t, path))
+		})
+	}
+}
+
+func readTGZ(t *testing.T, path string) map[string]string {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	gr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	return readTar(t, gr)
+}
+
+func readTarZstd(t *testing.T, path string) map[string]string {
+	t.Helper()
+	out, err := exec.Command(saver.ZstdCommand, "-q", "-d", "-c", path).Output()
+	require.NoError(t, err, "decompressing")
+	return readTar(t, bytes.NewReader(out))
+}
+
+func readTar(t *testing.T, r io.Reader) map[string]string {
+	t.Helper()
+	files := map[string]string{}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		require.NoError(t, err)
+		bs, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = string(bs)
+	}
+}
+
+func readZip(t *testing.T, path string) map[string]string {
+	t.Helper()
+	zr, err := zip.OpenReader(path)
+	require.NoError(t, err)
+	defer func() { _ = zr.Close() }()
+	files := map[string]string{}
+	for _, zf := range zr.File {
+		r, err := zf.Open()
+		require.NoError(t, err)
+		bs, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+		files[zf.Name] = string(bs)
+	}
+	return files
+}
+
+func readDir(t *testing.T, path string) map[string]string {
+	t.Helper()
+	files := map[string]string{}
+	err := filepath.WalkDir(path, func(p string, de os.DirEntry, err error) error {
+		if err != nil || de.IsDir() {
+			return err
+		}
+		bs, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(filepath.Dir(path), p)
+		files[filepath.ToSlash(rel)] = string(bs)
+		return err
+	})
+	require.NoError(t, err)
+	return files
+}
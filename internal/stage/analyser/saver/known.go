@@ -0,0 +1,87 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package saver
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/helper/iohelp"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+)
+
+// KnownCluster records a failure cluster that has already been archived in a previous save.
+type KnownCluster struct {
+	// Signature is the signature of the cluster.
+	Signature analysis.Signature `json:"signature"`
+	// Representative is the compilation that was archived for the cluster.
+	Representative compilation.Name `json:"representative"`
+	// FirstSeen is the creation time of the plan in which the cluster was first seen.
+	FirstSeen time.Time `json:"first_seen"`
+	// LastSeen is the creation time of the plan in which the cluster was last seen.
+	LastSeen time.Time `json:"last_seen"`
+	// Hits is the number of compilations that have fallen into the cluster across all saves.
+	Hits int `json:"hits"`
+}
+
+// KnownClusters maps signature fingerprints to known clusters.
+type KnownClusters map[string]KnownCluster
+
+// LoadKnownClusters loads a known cluster map from the JSON file at path.
+// If the file doesn't exist, we return an empty map.
+func LoadKnownClusters(path string) (KnownClusters, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return KnownClusters{}, nil
+		}
+		return nil, err
+	}
+	var kc KnownClusters
+	derr := json.NewDecoder(f).Decode(&kc)
+	cerr := f.Close()
+	if kc == nil {
+		kc = KnownClusters{}
+	}
+	return kc, errhelp.FirstError(derr, cerr)
+}
+
+// Write atomically replaces the JSON file at path with this known cluster map.
+func (k KnownClusters) Write(path string) error {
+	return iohelp.WriteJSONFileAtomic(path, k)
+}
+
+// Observe merges the clusters in cs, seen in a plan created at time t, into this map.
+func (k KnownClusters) Observe(cs analysis.ClusterSet, t time.Time) {
+	for fp, c := range cs {
+		kc, ok := k[fp]
+		if !ok {
+			kc = KnownCluster{Signature: c.Signature, Representative: c.Representative, FirstSeen: t}
+		}
+		kc.LastSeen = t
+		kc.Hits += len(c.Members)
+		k[fp] = kc
+	}
+}
+
+// isDuplicate checks whether the subject sname is a duplicate with regards to the clusters in cs.
+//
+// A subject is a duplicate if every cluster it belongs to is either known, or represented by a different subject.
+// Subjects that don't belong to any cluster are never duplicates.
+func (k KnownClusters) isDuplicate(cs analysis.ClusterSet, sname string) bool {
+	fps := cs.OfSubject(sname)
+	for _, fp := range fps {
+		_, known := k[fp]
+		if !known && cs[fp].Representative.SubjectName == sname {
+			return false
+		}
+	}
+	return len(fps) != 0
+}
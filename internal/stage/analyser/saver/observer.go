@@ -25,6 +25,14 @@ const (
 	ArchiveFileMissing
 	// ArchiveFinish denotes the end of an archival run.
 	ArchiveFinish
+	// ArchiveSkipped states that the subject wasn't archived, as it only exhibits already-known failure clusters.
+	ArchiveSkipped
+	// ArchiveSent states that a saved run was sent to the save target.
+	// File contains the path of the run relative to the target.
+	ArchiveSent
+	// ArchivePruned states that a saved run was pruned by the retention policy.
+	// File contains the path of the run relative to the target (or save directory).
+	ArchivePruned
 )
 
 // ArchiveMessage represents an OnArchive message.
@@ -85,3 +93,30 @@ func OnArchiveFileMissing(sname, missing string, i int, obs ...Observer) {
 		Index:       i,
 	}, obs...)
 }
+
+// OnArchiveSkipped sends an archive 'skipped' message to every observer in obs.
+// This message includes the subject name sname.
+func OnArchiveSkipped(sname string, obs ...Observer) {
+	OnArchive(ArchiveMessage{
+		Kind:        ArchiveSkipped,
+		SubjectName: sname,
+	}, obs...)
+}
+
+// OnArchiveSent sends an archive 'sent' message to every observer in obs.
+// This message includes the path rpath of the sent run, relative to the target.
+func OnArchiveSent(rpath string, obs ...Observer) {
+	OnArchive(ArchiveMessage{
+		Kind: ArchiveSent,
+		File: rpath,
+	}, obs...)
+}
+
+// OnArchivePruned sends an archive 'pruned' message to every observer in obs.
+// This message includes the path rpath of the pruned run, relative to the target.
+func OnArchivePruned(rpath string, obs ...Observer) {
+	OnArchive(ArchiveMessage{
+		Kind: ArchivePruned,
+		File: rpath,
+	}, obs...)
+}
@@ -5,7 +5,12 @@
 
 package saver
 
-import "github.com/c4-project/c4t/internal/observing"
+import (
+	"time"
+
+	"github.com/c4-project/c4t/internal/observing"
+	"github.com/c4-project/c4t/internal/stage/mach/interpreter"
+)
 
 // Option is the type of options to New.
 type Option func(*Saver) error
@@ -32,3 +37,58 @@ func ObserveWith(obs ...Observer) Option {
 		return nil
 	}
 }
+
+// ArchiveSuffix sets the suffix appended to subject names to get the names of their archives.
+// This should match the format of the archives made by the saver's archive maker; see Format.Suffix.
+func ArchiveSuffix(suffix string) Option {
+	return func(s *Saver) error {
+		s.suffix = suffix
+		return nil
+	}
+}
+
+// SendTo makes the saver send each saved run to t, removing it from the save pathset once sent.
+// t can be nil, in which case saved runs stay in the save pathset.
+func SendTo(t Target) Option {
+	return func(s *Saver) error {
+		s.target = t
+		return nil
+	}
+}
+
+// Retain makes the saver prune saved runs according to r after saving; see PruneEvery.
+// Runs are pruned from the target, if there is one, and from the save pathset otherwise.
+func Retain(r Retention) Option {
+	return func(s *Saver) error {
+		s.retention = r
+		return nil
+	}
+}
+
+// PruneEvery sets the minimum interval between the saver's prunes to d.
+// Pruning lists the whole target, so savers that run often should avoid doing it on every save.
+// The saver always prunes on its first save; the default interval is DefaultPruneInterval.
+func PruneEvery(d time.Duration) Option {
+	return func(s *Saver) error {
+		s.pruneEvery = d
+		return nil
+	}
+}
+
+// SkipKnownClusters, if on, makes the saver skip archiving subjects whose failures all fall into clusters that have
+// either been archived in a previous save, or are represented by another subject in the same save.
+func SkipKnownClusters(on bool) Option {
+	return func(s *Saver) error {
+		s.skipKnown = on
+		return nil
+	}
+}
+
+// ReproduceWith makes the saver use cdriver to work out the exact compiler invocations in each saved subject's
+// reproduction bundle.  If cdriver is nil, the bundles describe each compiler job, but not how to invoke it.
+func ReproduceWith(cdriver interpreter.Driver) Option {
+	return func(s *Saver) error {
+		s.cdriver = cdriver
+		return nil
+	}
+}
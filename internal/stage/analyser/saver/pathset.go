@@ -21,29 +21,44 @@ import (
 
 const (
 	planBasename       = "plan"
+	knownBasename      = "clusters.json"
 	segFlagged         = "flagged"
 	segCompileFailures = "compile_fail"
 	segCompileTimeouts = "compile_timeout"
 	segRunFailures     = "run_fail"
 	segRunTimeouts     = "run_timeout"
+	segRunLimits       = "run_limit"
 )
 
 // Pathset contains the pre-computed paths for saving 'interesting' run results.
 type Pathset struct {
+	// Root is the root directory of the pathset.
+	Root string
+
+	// TargetPrefix, if non-empty, is a slashpath under which runs from this pathset are stored on save targets.
+	// This keeps apart runs from pathsets that share a target.
+	TargetPrefix string
+
 	// Dirs maps 'interesting' statuses to directories.
 	Dirs [status.Last + 1]string
+
+	// FileKnownClusters is the file recording the failure clusters already archived under this pathset.
+	FileKnownClusters string
 }
 
 // NewPathset creates a save pathset rooted at root.
 func NewPathset(root string) *Pathset {
 	return &Pathset{
+		Root: root,
 		Dirs: [...]string{
 			status.Flagged:        filepath.Join(root, segFlagged),
 			status.CompileFail:    filepath.Join(root, segCompileFailures),
 			status.CompileTimeout: filepath.Join(root, segCompileTimeouts),
 			status.RunFail:        filepath.Join(root, segRunFailures),
 			status.RunTimeout:     filepath.Join(root, segRunTimeouts),
+			status.RunLimit:       filepath.Join(root, segRunLimits),
 		},
+		FileKnownClusters: filepath.Join(root, knownBasename),
 	}
 }
 
@@ -88,7 +103,12 @@ func runRoot(root string, iterTime time.Time) string {
 
 // SubjectTarFile gets the path to which a tarball for subject sname should be saved.
 func (s *RunPathset) SubjectTarFile(sname string) string {
-	return s.subjectFile(sname + normpath.TarSuffix)
+	return s.SubjectArchiveFile(sname, normpath.TarSuffix)
+}
+
+// SubjectArchiveFile gets the path to which an archive with suffix suffix for subject sname should be saved.
+func (s *RunPathset) SubjectArchiveFile(sname, suffix string) string {
+	return s.subjectFile(sname + suffix)
 }
 
 func (s *RunPathset) subjectFile(fname string) string {
@@ -34,6 +34,7 @@ func ExampleNewPathset() {
 	// CompileTimeout: saved/compile_timeout
 	// RunFail: saved/run_fail
 	// RunTimeout: saved/run_timeout
+	// RunLimit: saved/run_limit
 }
 
 // ExamplePathset_SubjectRun is a runnable example for SubjectRun.
@@ -0,0 +1,282 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package saver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/recipe"
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/backend"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/stage/mach/interpreter"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/normpath"
+	"github.com/c4-project/c4t/internal/subject/obs"
+	"github.com/c4-project/c4t/internal/subject/status"
+)
+
+// ErrNotInPlan occurs when we try to make a reproduction manifest for a subject that isn't in the plan.
+var ErrNotInPlan = errors.New("subject not in plan")
+
+// Manifest describes how to reproduce the outcome of a saved subject.
+//
+// All paths in a manifest are normalised slash-paths, relative to the directory in which the subject's archive was
+// unpacked.
+type Manifest struct {
+	// Subject is the name of the subject.
+	Subject string `json:"subject"`
+	// Machine is the ID of the machine on which the subject was tested.
+	Machine id.ID `json:"machine"`
+	// Created is the creation time of the plan in which the subject was tested.
+	Created time.Time `json:"created"`
+	// Backend is the backend used to lift and run the subject.
+	Backend *backend.NamedSpec `json:"backend,omitempty"`
+	// Compilations maps each compiler ID to information about reproducing its compilation of the subject.
+	Compilations map[string]ReproCompilation `json:"compilations"`
+}
+
+// ReproCompilation describes how to reproduce one compilation of a saved subject.
+type ReproCompilation struct {
+	// Arch is the architecture of the machine, as targeted by the compiler.
+	Arch id.ID `json:"arch,omitempty"`
+	// Compiler is the compiler instance used for the compilation, if the plan knew about it.
+	Compiler *compiler.Instance `json:"compiler,omitempty"`
+	// RecipeID is the ID of the recipe used for the compilation, if any.
+	RecipeID id.ID `json:"recipe_id,omitempty"`
+	// Instructions are the instructions of the recipe used for the compilation.
+	Instructions []recipe.Instruction `json:"instructions,omitempty"`
+	// Binary is the path to the binary produced by the compilation, if the recipe produces one.
+	Binary string `json:"binary,omitempty"`
+	// Jobs are the compiler jobs that the recipe expands into.
+	Jobs []ReproJob `json:"jobs,omitempty"`
+	// Expected is the status a correct compiler should have produced.
+	Expected status.Status `json:"expected"`
+	// Observed is the status the compilation actually produced.
+	Observed status.Status `json:"observed"`
+	// Obs is the observation from running the compilation, if any.
+	Obs *obs.Obs `json:"obs,omitempty"`
+	// Unexpected contains the observed states that made the observation interesting, if any.
+	Unexpected []obs.State `json:"unexpected,omitempty"`
+}
+
+// ReproJob describes one compiler job in a reproduction.
+type ReproJob struct {
+	// Out is the file produced by the job.
+	Out string `json:"out"`
+	// In lists the files consumed by the job.
+	In []string `json:"in"`
+	// Invocations contains, if known, the exact commands used to run the job.
+	Invocations []service.RunInfo `json:"invocations,omitempty"`
+}
+
+// NewManifest makes a reproduction manifest for the subject name in the normalised plan np, analysed in a.
+//
+// If cdriver is non-nil, it is dry-run over each compilation's recipe to find the exact compiler invocations.
+func NewManifest(ctx context.Context, np *plan.Plan, a *analysis.Analysis, name string, cdriver interpreter.Driver) (*Manifest, error) {
+	s, ok := np.Corpus[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotInPlan, name)
+	}
+	m := Manifest{
+		Subject:      name,
+		Machine:      np.Machine.ID,
+		Created:      np.Metadata.Creation,
+		Backend:      np.Backend,
+		Compilations: make(map[string]ReproCompilation, len(s.Compilations)),
+	}
+	for cid, c := range s.Compilations {
+		rc, err := newReproCompilation(ctx, np, a, subject.Named{Name: name, Subject: s}, cid, c, cdriver)
+		if err != nil {
+			return nil, fmt.Errorf("compilation %s: %w", cid, err)
+		}
+		m.Compilations[cid.String()] = *rc
+	}
+	return &m, nil
+}
+
+func newReproCompilation(ctx context.Context, np *plan.Plan, a *analysis.Analysis, s subject.Named, cid id.ID, c compilation.Compilation, cdriver interpreter.Driver) (*ReproCompilation, error) {
+	rc := ReproCompilation{
+		Expected: status.Ok,
+		Observed: observedStatus(a, s.Name, cid, c),
+	}
+	if c.Run != nil && c.Run.Obs != nil {
+		rc.Obs = c.Run.Obs
+		rc.Unexpected = c.Run.Obs.Unexpected()
+	}
+	ci, ok := np.Compilers[cid]
+	if !ok {
+		return &rc, nil
+	}
+	rc.Compiler = &ci
+	rc.Arch = ci.Arch
+
+	rid, r, err := s.Recipe(ci.Arch)
+	if err != nil {
+		// The recipe isn't in the bundle, so we can't say how the compilation happened.
+		return &rc, nil
+	}
+	rc.RecipeID = rid
+	rc.Instructions = r.Instructions
+	if r.NeedsCompile() {
+		rc.Binary = path.Join(s.Name, normpath.DirCompiles, cid.String(), normpath.FileBin)
+		rc.Jobs, err = dryRunRecipe(ctx, rc.Binary, r, &ci, cdriver)
+	}
+	return &rc, err
+}
+
+// observedStatus gets the status of subject sname on compiler cid according to a, falling back to c's own results.
+func observedStatus(a *analysis.Analysis, sname string, cid id.ID, c compilation.Compilation) status.Status {
+	if a != nil {
+		if st, ok := a.Compilers[cid].Statuses[sname]; ok {
+			return st
+		}
+	}
+	var f status.Flag
+	if c.Compile != nil {
+		f |= c.Compile.Status.Flag()
+	}
+	if c.Run != nil {
+		f |= c.Run.Status.Flag()
+	}
+	return f.Status()
+}
+
+// dryRunRecipe interprets r, targeting ofile and using compiler ci, without running any compilers.
+func dryRunRecipe(ctx context.Context, ofile string, r recipe.Recipe, ci *compiler.Instance, cdriver interpreter.Driver) ([]ReproJob, error) {
+	jr := jobRecorder{driver: cdriver}
+	i, err := interpreter.New(ofile, r, &invocationRecorder{}, interpreter.CompileWith(&jr, ci))
+	if err != nil {
+		return nil, err
+	}
+	err = i.Interpret(ctx)
+	return jr.jobs, err
+}
+
+// jobRecorder is an interpreter driver that records, rather than runs, the jobs it is asked to run.
+type jobRecorder struct {
+	// driver is, if non-nil, the driver used to work out each job's invocations.
+	driver interpreter.Driver
+	// jobs holds the recorded jobs.
+	jobs []ReproJob
+}
+
+// RunCompiler records the job j.
+func (r *jobRecorder) RunCompiler(ctx context.Context, j compiler.Job, _ service.Runner) error {
+	rj := ReproJob{Out: j.Out, In: j.In}
+	if r.driver != nil {
+		var ir invocationRecorder
+		if err := r.driver.RunCompiler(ctx, j, &ir); err != nil {
+			return err
+		}
+		rj.Invocations = ir.runs
+	}
+	r.jobs = append(r.jobs, rj)
+	return nil
+}
+
+// invocationRecorder is a service runner that records, rather than runs, the services it is asked to run.
+type invocationRecorder struct {
+	runs []service.RunInfo
+}
+
+// WithStdout returns r unmodified.
+func (r *invocationRecorder) WithStdout(io.Writer) service.Runner {
+	return r
+}
+
+// WithStderr returns r unmodified.
+func (r *invocationRecorder) WithStderr(io.Writer) service.Runner {
+	return r
+}
+
+// WithGrace returns r unmodified.
+func (r *invocationRecorder) WithGrace(time.Duration) service.Runner {
+	return r
+}
+
+// Run records ri.
+func (r *invocationRecorder) Run(_ context.Context, ri service.RunInfo) error {
+	r.runs = append(r.runs, ri)
+	return nil
+}
+
+// CompilerIDs gets the IDs of the compilers in this manifest, in sorted order.
+func (m *Manifest) CompilerIDs() []string {
+	cids := make([]string, 0, len(m.Compilations))
+	for cid := range m.Compilations {
+		cids = append(cids, cid)
+	}
+	sort.Strings(cids)
+	return cids
+}
+
+// Write writes this manifest to w as JSON.
+func (m *Manifest) Write(w io.Writer) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "\t")
+	return e.Encode(m)
+}
+
+// ReadManifest reads a manifest from path.
+func ReadManifest(path string) (*Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	derr := json.NewDecoder(f).Decode(&m)
+	cerr := f.Close()
+	if derr != nil {
+		return nil, fmt.Errorf("decoding manifest %s: %w", path, derr)
+	}
+	return &m, cerr
+}
+
+// ReproOutcome is the outcome of reproducing one compilation from a manifest.
+type ReproOutcome struct {
+	// Compiler is the ID of the compiler.
+	Compiler string
+	// Observed is the status the compilation had when it was saved.
+	Observed status.Status
+	// Now is the status the compilation had when reproduced.
+	Now status.Status
+}
+
+// Reproduced gets whether the reproduction gave the same status as the original.
+func (o ReproOutcome) Reproduced() bool {
+	return o.Now == o.Observed
+}
+
+// Compare compares the bad compilations in this manifest against the analysis a of a plan reproducing them.
+// It returns one outcome per bad compilation, in compiler ID order.
+func (m *Manifest) Compare(a *analysis.Analysis) []ReproOutcome {
+	var outs []ReproOutcome
+	for _, cid := range m.CompilerIDs() {
+		rc := m.Compilations[cid]
+		if !rc.Observed.IsBad() {
+			continue
+		}
+		o := ReproOutcome{Compiler: cid, Observed: rc.Observed, Now: status.Unknown}
+		if st, ok := a.Compilers[id.FromString(cid)].Statuses[m.Subject]; ok {
+			o.Now = st
+		}
+		outs = append(outs, o)
+	}
+	return outs
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package saver_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/model/recipe"
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/model/service/compiler/optlevel"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/stage/analyser/saver"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/c4-project/c4t/internal/subject/obs"
+	"github.com/c4-project/c4t/internal/subject/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cimpl "github.com/c4-project/c4t/internal/serviceimpl/compiler"
+)
+
+// reproPlan makes a normalised plan with one subject, foo, which gcc flags and clang compiles fine.
+func reproPlan() *plan.Plan {
+	r, err := recipe.New("foo/recipes/x86", recipe.OutExe, recipe.AddFiles("run.c"), recipe.CompileAllCToExe())
+	if err != nil {
+		panic(err)
+	}
+	o := obs.Obs{
+		Flags: obs.Sat | obs.Exist,
+		States: []obs.State{
+			{Tag: obs.TagWitness, Values: obs.Valuation{"0:r0": "1"}},
+			{Tag: obs.TagCounter, Values: obs.Valuation{"0:r0": "0"}},
+		},
+	}
+	s := subject.NewOrPanic(
+		litmus.NewOrPanic("foo/orig.litmus"),
+		subject.WithRecipe(id.ArchX86, r),
+		subject.WithCompile(id.FromString("gcc"), compilation.CompileResult{Result: compilation.Result{Status: status.Ok}}),
+		subject.WithRun(id.FromString("gcc"), compilation.RunResult{Result: compilation.Result{Status: status.Flagged}, Obs: &o}),
+		subject.WithCompile(id.FromString("clang"), compilation.CompileResult{Result: compilation.Result{Status: status.Ok}}),
+		subject.WithRun(id.FromString("clang"), compilation.RunResult{Result: compilation.Result{Status: status.Ok}}),
+	)
+
+	gcc := compiler.MockX86Gcc()
+	gcc.SelectedOpt = &optlevel.Named{Name: "3"}
+	clang := compiler.MockX86Gcc()
+	clang.Run = service.NewRunInfo("clang")
+
+	p := plan.Mock()
+	p.Compilers = compiler.InstanceMap{id.FromString("gcc"): gcc, id.FromString("clang"): clang}
+	p.Corpus = corpus.Corpus{"foo": *s}
+	return p
+}
+
+// TestNewManifest tests NewManifest on a small plan, both with and without a compiler driver.
+func TestNewManifest(t *testing.T) {
+	t.Parallel()
+
+	p := reproPlan()
+	a, err := analysis.Analyse(context.Background(), p)
+	require.NoError(t, err, "analysing plan")
+
+	m, err := saver.NewManifest(context.Background(), p, a, "foo", &cimpl.CResolve)
+	require.NoError(t, err, "making manifest")
+
+	assert.Equal(t, "foo", m.Subject)
+	assert.Equal(t, id.FromString("localhost"), m.Machine)
+	assert.Equal(t, p.Backend, m.Backend)
+	assert.Equal(t, []string{"clang", "gcc"}, m.CompilerIDs())
+
+	gcc := m.Compilations["gcc"]
+	assert.Equal(t, id.ArchX86, gcc.Arch)
+	assert.Equal(t, status.Ok, gcc.Expected)
+	assert.Equal(t, status.Flagged, gcc.Observed)
+	assert.Equal(t, "foo/compiles/gcc/a.out", gcc.Binary)
+	assert.Equal(t, p.Corpus["foo"].Recipes[id.ArchX86].Instructions, gcc.Instructions)
+	require.Len(t, gcc.Unexpected, 1, "only the witness should be unexpected")
+	assert.Equal(t, obs.Valuation{"0:r0": "1"}, gcc.Unexpected[0].Values)
+	require.Len(t, gcc.Jobs, 1, "recipe should expand to one job")
+	assert.Equal(t, []string{"foo/recipes/x86/run.c"}, gcc.Jobs[0].In)
+	require.Len(t, gcc.Jobs[0].Invocations, 1, "job should have one invocation")
+	assert.Equal(t,
+		[]string{"gcc", "-pthread", "-std=gnu11", "-O3", "-o", "foo/compiles/gcc/a.out", "foo/recipes/x86/run.c"},
+		gcc.Jobs[0].Invocations[0].Invocation())
+
+	clang := m.Compilations["clang"]
+	assert.Equal(t, status.Ok, clang.Observed)
+	require.Len(t, clang.Jobs, 1, "recipe should expand to one job")
+	require.Len(t, clang.Jobs[0].Invocations, 1, "job should have one invocation")
+	assert.Equal(t, "clang", clang.Jobs[0].Invocations[0].Cmd)
+
+	t.Run("no-driver", func(t *testing.T) {
+		t.Parallel()
+		m, err := saver.NewManifest(context.Background(), p, a, "foo", nil)
+		require.NoError(t, err, "making manifest")
+		jobs := m.Compilations["gcc"].Jobs
+		require.Len(t, jobs, 1, "recipe should still expand to one job")
+		assert.Empty(t, jobs[0].Invocations, "there should be no invocations without a driver")
+	})
+	t.Run("missing", func(t *testing.T) {
+		t.Parallel()
+		_, err := saver.NewManifest(context.Background(), p, a, "bar", nil)
+		assert.ErrorIs(t, err, saver.ErrNotInPlan)
+	})
+}
+
+// ExampleManifest_WriteScript is a runnable example for WriteScript.
+func ExampleManifest_WriteScript() {
+	p := reproPlan()
+	m, err := saver.NewManifest(context.Background(), p, nil, "foo", &cimpl.CResolve)
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	if err := m.WriteScript(os.Stdout); err != nil {
+		fmt.Println("error:", err)
+	}
+
+	// Output:
+	// #!/bin/sh
+	// # Reproduction script for subject foo, as tested on machine localhost.
+	// #
+	// # This replays the compiler invocations that produced each compilation, then
+	// # runs any resulting binaries; use c4t-repro on the subject's archive to also
+	// # interpret the results through the backend.
+	// set -u
+	// cd "$(dirname "$0")/.." || exit 1
+	//
+	// # clang (arch x86): expected Ok, observed Ok
+	// mkdir -p 'foo/compiles/clang'
+	// 'clang' '-pthread' '-std=gnu11' '-o' 'foo/compiles/clang/a.out' 'foo/recipes/x86/run.c'
+	// './foo/compiles/clang/a.out'
+	//
+	// # gcc (arch x86): expected Ok, observed Flagged
+	// mkdir -p 'foo/compiles/gcc'
+	// 'gcc' '-pthread' '-std=gnu11' '-O3' '-o' 'foo/compiles/gcc/a.out' 'foo/recipes/x86/run.c'
+	// './foo/compiles/gcc/a.out'
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package saver
+
+import (
+	"io"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/c4-project/c4t/internal/model/service"
+)
+
+const reproScript = `#!/bin/sh
+# Reproduction script for subject {{ .Subject }}, as tested on machine {{ .Machine }}.
+#
+# This replays the compiler invocations that produced each compilation, then
+# runs any resulting binaries; use c4t-repro on the subject's archive to also
+# interpret the results through the backend.
+set -u
+cd "$(dirname "$0")/.." || exit 1
+{{ range $cid := .CompilerIDs }}{{ with index $.Compilations $cid }}
+# {{ $cid }}{{ with .Arch }} (arch {{ . }}){{ end }}: expected {{ .Expected }}, observed {{ .Observed }}
+{{- range .Jobs }}
+mkdir -p {{ dir .Out | quote }}
+{{- range .Invocations }}
+{{ command . }}
+{{- else }}
+# (invocation unknown) compile {{ range .In }}{{ . }} {{ end }}to {{ .Out }}
+{{- end }}
+{{- end }}
+{{- with .Binary }}
+{{ printf "./%s" . | quote }}
+{{- end }}
+{{ end }}{{ end -}}
+`
+
+var reproTemplate = template.Must(template.New("repro").Funcs(template.FuncMap{
+	"command": shellCommand,
+	"dir":     path.Dir,
+	"quote":   shellQuote,
+}).Parse(reproScript))
+
+// WriteScript writes to w a shell script that replays this manifest's compilations.
+// The script expects to be placed, as normpath.FileReproScript, inside the subject's unpacked archive directory.
+func (m *Manifest) WriteScript(w io.Writer) error {
+	return reproTemplate.Execute(w, m)
+}
+
+// shellCommand renders r as a quoted shell command line.
+func shellCommand(r service.RunInfo) string {
+	var parts []string
+	if envs := r.EnvStrings(); len(envs) != 0 {
+		parts = append(parts, "env")
+		for _, e := range envs {
+			parts = append(parts, shellQuote(e))
+		}
+	}
+	for _, a := range r.Invocation() {
+		parts = append(parts, shellQuote(a))
+	}
+	return strings.Join(parts, " ")
+}
+
+// shellQuote quotes s for use as a single word in a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
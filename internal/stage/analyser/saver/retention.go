@@ -0,0 +1,184 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package saver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/c4-project/c4t/internal/helper/iohelp"
+)
+
+// Retention is a policy for pruning old saved runs from a target.
+type Retention struct {
+	// MaxAge, if nonzero, is the age beyond which saved runs are pruned.
+	MaxAge time.Duration `toml:"max_age,omitzero" json:"max_age,omitempty"`
+	// MaxSize, if nonzero, is the total size beyond which the oldest saved runs are pruned.
+	MaxSize Size `toml:"max_size,omitzero" json:"max_size,omitempty"`
+}
+
+// IsZero gets whether this policy is the zero policy, which never prunes anything.
+func (r Retention) IsZero() bool {
+	return r.MaxAge == 0 && r.MaxSize == 0
+}
+
+// SavedRun describes a saved run on a target.
+type SavedRun struct {
+	// Path is the slashpath of the run's root directory relative to the target's root.
+	Path string
+	// Time is the creation time of the plan from which the run was saved.
+	//
+	// Runs are stored under the local time at which they were saved, so we read this in the local timezone.
+	Time time.Time
+	// Size is the total size, in bytes, of the run's files.
+	Size int64
+	// Files lists the slashpaths of the run's files relative to the target's root.
+	Files []string
+}
+
+// SavedRuns groups the stored files fs into saved runs, in ascending order of time and then path.
+// Files that aren't part of a saved run, such as the known cluster file, are ignored.
+func SavedRuns(fs []StoredFile) []SavedRun {
+	byPath := map[string]*SavedRun{}
+	for _, f := range fs {
+		rpath, t, ok := parseRunPath(f.Path)
+		if !ok {
+			continue
+		}
+		r, ok := byPath[rpath]
+		if !ok {
+			r = &SavedRun{Path: rpath, Time: t}
+			byPath[rpath] = r
+		}
+		r.Size += f.Size
+		r.Files = append(r.Files, f.Path)
+	}
+
+	runs := make([]SavedRun, 0, len(byPath))
+	for _, r := range byPath {
+		sort.Strings(r.Files)
+		runs = append(runs, *r)
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		if !runs[i].Time.Equal(runs[j].Time) {
+			return runs[i].Time.Before(runs[j].Time)
+		}
+		return runs[i].Path < runs[j].Path
+	})
+	return runs
+}
+
+// runTimeLayout is the layout of the date and time segments of a saved run's path, as laid out by runRoot.
+const runTimeLayout = "2006/1/2/15_04_05"
+
+// parseRunPath tries to interpret fpath as a file within a saved run, as laid out by Pathset.
+// If successful, it returns the run's path and time.
+func parseRunPath(fpath string) (string, time.Time, bool) {
+	segs := strings.Split(fpath, "/")
+	if len(segs) < 6 {
+		return "", time.Time{}, false
+	}
+	t, err := time.ParseInLocation(runTimeLayout, path.Join(segs[1:5]...), time.Local)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return path.Join(segs[:5]...), t, true
+}
+
+// Prune deletes from t any saved runs that this policy excludes at time now, returning the pruned runs.
+//
+// Runs older than MaxAge are pruned first; then, the oldest runs are pruned until the total size is within MaxSize.
+// The most recent run is never pruned.
+func (r Retention) Prune(ctx context.Context, t Target, now time.Time) ([]SavedRun, error) {
+	if r.IsZero() {
+		return nil, nil
+	}
+	fs, err := t.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing saved runs: %w", err)
+	}
+	runs := SavedRuns(fs)
+
+	var pruned []SavedRun
+	for _, run := range r.excluded(runs, now) {
+		if err := iohelp.CheckDone(ctx); err != nil {
+			return pruned, err
+		}
+		if err := deleteRun(ctx, t, run); err != nil {
+			return pruned, fmt.Errorf("pruning saved run %s: %w", run.Path, err)
+		}
+		pruned = append(pruned, run)
+	}
+	return pruned, nil
+}
+
+func (r Retention) excluded(runs []SavedRun, now time.Time) []SavedRun {
+	var total int64
+	for _, run := range runs {
+		total += run.Size
+	}
+
+	n := 0
+	for ; n < len(runs)-1; n++ {
+		tooOld := r.MaxAge != 0 && now.Sub(runs[n].Time) > r.MaxAge
+		tooBig := r.MaxSize != 0 && total > int64(r.MaxSize)
+		if !(tooOld || tooBig) {
+			break
+		}
+		total -= runs[n].Size
+	}
+	return runs[:n]
+}
+
+func deleteRun(ctx context.Context, t Target, run SavedRun) error {
+	for _, f := range run.Files {
+		if err := t.Delete(ctx, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Size is a size in bytes that can be unmarshalled from strings with binary unit suffixes, such as "512M".
+type Size int64
+
+// ErrBadSize occurs when ParseSize can't parse a size.
+var ErrBadSize = errors.New("bad size")
+
+var sizeUnits = []struct {
+	suffix string
+	scale  int64
+}{{"T", 1 << 40}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"", 1}}
+
+// ParseSize parses s as a number of bytes, optionally followed by one of the unit suffixes K, M, G, or T
+// (with an optional trailing B or iB, and case-insensitively), each denoting a power of 1024.
+func ParseSize(s string) (Size, error) {
+	us := strings.TrimSuffix(strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(s)), "B"), "I")
+	for _, u := range sizeUnits {
+		if !strings.HasSuffix(us, u.suffix) {
+			continue
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(strings.TrimSuffix(us, u.suffix)), 10, 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%w: %q", ErrBadSize, s)
+		}
+		return Size(n * u.scale), nil
+	}
+	return 0, fmt.Errorf("%w: %q", ErrBadSize, s)
+}
+
+// UnmarshalText unmarshals a Size from text using ParseSize.
+func (s *Size) UnmarshalText(text []byte) error {
+	var err error
+	*s, err = ParseSize(string(text))
+	return err
+}
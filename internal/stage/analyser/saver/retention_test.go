@@ -0,0 +1,136 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package saver_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/stage/analyser/saver"
+)
+
+// ExampleParseSize is a runnable example for ParseSize.
+func ExampleParseSize() {
+	for _, s := range []string{"512", "4K", "10MiB", "2gb", "-1", "lots"} {
+		n, err := saver.ParseSize(s)
+		if err != nil {
+			fmt.Println("error:", err)
+			continue
+		}
+		fmt.Println(n)
+	}
+
+	// Output:
+	// 512
+	// 4096
+	// 10485760
+	// 2147483648
+	// error: bad size: "-1"
+	// error: bad size: "lots"
+}
+
+// ExampleSavedRuns is a runnable example for SavedRuns.
+func ExampleSavedRuns() {
+	runs := saver.SavedRuns([]saver.StoredFile{
+		{Path: "clusters.json", Size: 100},
+		{Path: "run_fail/2021/3/4/10_00_00/plan.json.gz", Size: 10},
+		{Path: "flagged/2021/3/4/09_30_00/foo.tar.gz", Size: 20},
+		{Path: "flagged/2021/3/4/09_30_00/plan.json.gz", Size: 5},
+	})
+	for _, r := range runs {
+		fmt.Println(r.Path, r.Time.Format(time.DateTime), r.Size, len(r.Files))
+	}
+
+	// Output:
+	// flagged/2021/3/4/09_30_00 2021-03-04 09:30:00 25 2
+	// run_fail/2021/3/4/10_00_00 2021-03-04 10:00:00 10 1
+}
+
+// TestSavedRuns_localTime tests that SavedRuns reads run times in the local timezone, under which runs are saved.
+func TestSavedRuns_localTime(t *testing.T) {
+	t.Parallel()
+
+	runs := saver.SavedRuns([]saver.StoredFile{{Path: "flagged/2021/3/4/09_30_00/plan.json.gz", Size: 5}})
+	require.Len(t, runs, 1, "wrong number of runs")
+	want := time.Date(2021, time.March, 4, 9, 30, 0, 0, time.Local)
+	assert.True(t, want.Equal(runs[0].Time), "got %s, want %s", runs[0].Time, want)
+}
+
+// TestRetention_Prune tests pruning of saved runs in a directory target.
+func TestRetention_Prune(t *testing.T) {
+	t.Parallel()
+
+	// Run paths are in local time.
+	now := time.Date(2021, time.March, 10, 12, 0, 0, 0, time.Local)
+	// Each run has 100 bytes.
+	runs := []string{
+		"flagged/2021/3/1/12_00_00",
+		"compile_fail/2021/3/5/12_00_00",
+		"flagged/2021/3/9/12_00_00",
+		"flagged/2021/3/10/11_00_00",
+	}
+
+	cases := map[string]struct {
+		r    saver.Retention
+		want []string
+	}{
+		"zero":     {r: saver.Retention{}},
+		"age":      {r: saver.Retention{MaxAge: 72 * time.Hour}, want: runs[:2]},
+		"size":     {r: saver.Retention{MaxSize: 250}, want: runs[:2]},
+		"age+size": {r: saver.Retention{MaxAge: 7 * 24 * time.Hour, MaxSize: 350}, want: runs[:1]},
+		"keepLast": {r: saver.Retention{MaxAge: time.Minute}, want: runs[:3]},
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			root := t.TempDir()
+			for _, r := range runs {
+				writeRun(t, root, r, 100)
+			}
+			require.NoError(t, os.WriteFile(filepath.Join(root, "clusters.json"), make([]byte, 1000), 0644))
+
+			pruned, err := c.r.Prune(context.Background(), saver.DirTarget{Root: root}, now)
+			require.NoError(t, err, "pruning")
+
+			var got []string
+			for _, p := range pruned {
+				got = append(got, p.Path)
+			}
+			assert.Equal(t, c.want, got, "wrong runs pruned")
+			for _, r := range runs {
+				_, serr := os.Stat(filepath.Join(root, filepath.FromSlash(r)))
+				assert.Equal(t, contains(c.want, r), os.IsNotExist(serr), "run directory %s in wrong state", r)
+			}
+			assert.FileExists(t, filepath.Join(root, "clusters.json"), "non-run files shouldn't be pruned")
+		})
+	}
+}
+
+func writeRun(t *testing.T, root, run string, size int) {
+	t.Helper()
+	dir := filepath.Join(root, filepath.FromSlash(run))
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "plan.json.gz"), make([]byte, size/2), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.tar.gz"), make([]byte, size-size/2), 0644))
+}
+
+func contains(xs []string, x string) bool {
+	for _, y := range xs {
+		if x == y {
+			return true
+		}
+	}
+	return false
+}
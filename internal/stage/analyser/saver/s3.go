@@ -0,0 +1,204 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package saver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/stage/analyser/saver/sigv4"
+)
+
+// ErrS3Status occurs when an object store responds to a request with a non-success status.
+var ErrS3Status = errors.New("object store request failed")
+
+// S3Target is a target that stores files as objects in a bucket on an S3-compatible object store.
+//
+// The target uses path-style addressing, so it works with local stand-ins (such as MinIO) as well as with S3 proper.
+type S3Target struct {
+	// Endpoint is the base URL of the object store, for instance https://s3.eu-west-2.amazonaws.com.
+	Endpoint string
+	// Bucket is the name of the bucket in which objects are stored.
+	Bucket string
+	// Prefix is prepended, with a slash, to the key of every object stored by this target.
+	Prefix string
+	// Region is the region used when signing requests.
+	Region string
+	// Credentials are the credentials used when signing requests.
+	Credentials S3Credentials
+	// Client is the HTTP client used to make requests; if nil, we use http.DefaultClient.
+	Client *http.Client
+}
+
+// Put uploads the file at lpath to the object with key rpath, under the target prefix.
+func (s *S3Target) Put(ctx context.Context, rpath, lpath string) error {
+	sum, size, err := hashFile(lpath)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(lpath)
+	if err != nil {
+		return err
+	}
+	req, err := s.newRequest(ctx, http.MethodPut, s.key(rpath), nil, f)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set(headerContentSHA256, sum)
+	return s.do(req, nil)
+}
+
+// List lists every object under the target prefix.
+func (s *S3Target) List(ctx context.Context) ([]StoredFile, error) {
+	var (
+		files []StoredFile
+		token string
+	)
+	prefix := s.key("")
+	for {
+		q := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+		req, err := s.newRequest(ctx, http.MethodGet, "", q, nil)
+		if err != nil {
+			return files, err
+		}
+		var res s3ListResult
+		if err := s.do(req, &res); err != nil {
+			return files, err
+		}
+		for _, c := range res.Contents {
+			files = append(files, StoredFile{Path: strings.TrimPrefix(c.Key, prefix), Size: c.Size})
+		}
+		if !res.IsTruncated || res.NextContinuationToken == "" {
+			return files, nil
+		}
+		token = res.NextContinuationToken
+	}
+}
+
+// Delete deletes the object with key rpath, under the target prefix.
+func (s *S3Target) Delete(ctx context.Context, rpath string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, s.key(rpath), nil, nil)
+	if err != nil {
+		return err
+	}
+	return s.do(req, nil)
+}
+
+// Close does nothing.
+func (*S3Target) Close() error {
+	return nil
+}
+
+// key gets the object key for rpath.
+// If rpath is empty, this is the prefix under which every key of this target lies.
+func (s *S3Target) key(rpath string) string {
+	if s.Prefix == "" {
+		return rpath
+	}
+	return strings.TrimSuffix(s.Prefix, "/") + "/" + rpath
+}
+
+type s3ListResult struct {
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	Contents              []struct {
+		Key  string `xml:"Key"`
+		Size int64  `xml:"Size"`
+	} `xml:"Contents"`
+}
+
+func (s *S3Target) newRequest(ctx context.Context, method, key string, q url.Values, body io.Reader) (*http.Request, error) {
+	u, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("parsing endpoint %q: %w", s.Endpoint, err)
+	}
+	p := path.Join("/", u.Path, s.Bucket)
+	if key != "" {
+		p += "/" + key
+	}
+	u.Path = p
+	u.RawPath = sigv4.URIEncode(p, true)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(headerContentSHA256, sigv4.EmptyPayloadHash)
+	return req, nil
+}
+
+func (s *S3Target) do(req *http.Request, into interface{}) error {
+	s.Credentials.Sign(req, s.Region, time.Now())
+
+	cli := s.Client
+	if cli == nil {
+		cli = http.DefaultClient
+	}
+	resp, err := cli.Do(req)
+	if err != nil {
+		return err
+	}
+	var derr error
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		derr = fmt.Errorf("%w: %s %s: %s: %s", ErrS3Status, req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(msg)))
+	} else if into != nil {
+		derr = xml.NewDecoder(resp.Body).Decode(into)
+	}
+	cerr := resp.Body.Close()
+	return errhelp.FirstError(derr, cerr)
+}
+
+func hashFile(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	cerr := f.Close()
+	return hex.EncodeToString(h.Sum(nil)), n, errhelp.FirstError(err, cerr)
+}
+
+const headerContentSHA256 = "X-Amz-Content-Sha256"
+
+// S3Credentials are the credentials used to sign requests to an S3-compatible object store.
+type S3Credentials sigv4.Credentials
+
+// S3CredentialsFromEnv gets S3 credentials from the standard AWS environment variables.
+func S3CredentialsFromEnv() S3Credentials {
+	return S3Credentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
+
+// Sign signs r in place using AWS Signature Version 4, for the S3 service in region at time t.
+//
+// The payload hash must already be in r's X-Amz-Content-Sha256 header.
+// See sigv4.Credentials.Sign for the headers Sign adds and signs.
+func (c S3Credentials) Sign(r *http.Request, region string, t time.Time) {
+	sigv4.Credentials(c).Sign(r, "s3", region, r.Header.Get(headerContentSHA256), t)
+}
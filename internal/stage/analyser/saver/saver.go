@@ -7,11 +7,16 @@
 package saver
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path"
+	"path/filepath"
 	"time"
 
 	"github.com/c4-project/c4t/internal/subject/normaliser"
+	"github.com/c4-project/c4t/internal/subject/normpath"
 
 	"github.com/c4-project/c4t/internal/plan/analysis"
 
@@ -20,6 +25,8 @@ import (
 	"github.com/c4-project/c4t/internal/helper/iohelp"
 	"github.com/c4-project/c4t/internal/plan"
 	"github.com/c4-project/c4t/internal/subject/corpus"
+
+	"github.com/c4-project/c4t/internal/stage/mach/interpreter"
 )
 
 // Saver contains the state used when saving 'interesting' subjects.
@@ -32,8 +39,25 @@ type Saver struct {
 	observers []Observer
 	// paths contains the pathset used to save subjects for a particular machine.
 	paths *Pathset
+	// skipKnown makes the saver skip archiving subjects that only exhibit known failure clusters.
+	skipKnown bool
+	// suffix is the suffix appended to subject names to get the names of their archives.
+	suffix string
+	// target, if non-nil, is the target to which saved runs are sent.
+	target Target
+	// retention is the policy for pruning old saved runs.
+	retention Retention
+	// pruneEvery is the minimum interval between prunes.
+	pruneEvery time.Duration
+	// lastPrune is the time at which the saver last pruned, or zero if it hasn't yet.
+	lastPrune time.Time
+	// cdriver is, if non-nil, the compiler driver used to work out compiler invocations for reproduction bundles.
+	cdriver interpreter.Driver
 }
 
+// DefaultPruneInterval is the default minimum interval between prunes; see PruneEvery.
+const DefaultPruneInterval = time.Hour
+
 // ErrArchiveMakerNil is the error produced when the archive maker supplied to New is nil.
 var ErrArchiveMakerNil = errors.New("archive maker function nil")
 
@@ -43,6 +67,8 @@ func New(paths *Pathset, archiveMaker func(path string) (Archiver, error), ops .
 		paths:        paths,
 		archiveMaker: archiveMaker,
 		norm:         normaliser.NewCorpus(""),
+		suffix:       normpath.TarSuffix,
+		pruneEvery:   DefaultPruneInterval,
 	}
 	if s.paths == nil {
 		return nil, iohelp.ErrPathsetNil
@@ -54,8 +80,8 @@ func New(paths *Pathset, archiveMaker func(path string) (Archiver, error), ops .
 	return &s, err
 }
 
-// Run runs the saving stage over the analyser a.
-func (s *Saver) Run(a analysis.Analysis) error {
+// Run runs the saving stage over the analysis a.
+func (s *Saver) Run(ctx context.Context, a analysis.Analysis) error {
 	p := a.Plan
 	if p == nil {
 		return fmt.Errorf("when saving analysis: %w", plan.ErrNil)
@@ -67,14 +93,107 @@ func (s *Saver) Run(a analysis.Analysis) error {
 		return err
 	}
 
+	known, err := s.loadKnown()
+	if err != nil {
+		return err
+	}
+
 	for st, c := range a.ByStatus {
-		if err := s.runBucket(st, c, np, creation); err != nil {
+		rp, err := s.runBucket(ctx, st, s.dedup(c, a.Clusters, known), np, &a)
+		if err != nil {
 			return err
 		}
+		if err := s.send(ctx, rp); err != nil {
+			return err
+		}
+	}
+	if err := s.saveKnown(known, a.Clusters, creation); err != nil {
+		return err
+	}
+	return s.prune(ctx, time.Now())
+}
+
+// send sends the saved run at rp, if any, to the target, if any, then removes its local copy.
+func (s *Saver) send(ctx context.Context, rp *RunPathset) error {
+	if rp == nil || s.target == nil {
+		return nil
+	}
+	rel, err := filepath.Rel(s.paths.Root, rp.DirRoot)
+	if err != nil {
+		return err
+	}
+	rel = filepath.ToSlash(rel)
+	if err := sendRun(ctx, withPrefix(s.target, s.paths.TargetPrefix), rp.DirRoot, rel); err != nil {
+		return fmt.Errorf("sending saved run %s: %w", rel, err)
+	}
+	OnArchiveSent(path.Join(s.paths.TargetPrefix, rel), s.observers...)
+	if err := os.RemoveAll(rp.DirRoot); err != nil {
+		return err
+	}
+	// Tidy up any date directories that the run leaves empty.
+	for dir := filepath.Dir(rp.DirRoot); dir != s.paths.Root; dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
 	}
 	return nil
 }
 
+// prune applies the retention policy to the target, or the save pathset if there is no target, at time now.
+// It does nothing if the saver last pruned less than its prune interval before now.
+func (s *Saver) prune(ctx context.Context, now time.Time) error {
+	if s.retention.IsZero() || (!s.lastPrune.IsZero() && now.Sub(s.lastPrune) < s.pruneEvery) {
+		return nil
+	}
+	s.lastPrune = now
+
+	var t Target = DirTarget{Root: s.paths.Root}
+	if s.target != nil {
+		t = withPrefix(s.target, s.paths.TargetPrefix)
+	}
+	pruned, err := s.retention.Prune(ctx, t, now)
+	for _, r := range pruned {
+		OnArchivePruned(path.Join(s.paths.TargetPrefix, r.Path), s.observers...)
+	}
+	return err
+}
+
+// loadKnown loads the known failure clusters, if we're skipping them.
+func (s *Saver) loadKnown() (KnownClusters, error) {
+	if !s.skipKnown {
+		return nil, nil
+	}
+	return LoadKnownClusters(s.paths.FileKnownClusters)
+}
+
+// dedup removes from c any subjects that are duplicates given the clusters cs and the known clusters known.
+func (s *Saver) dedup(c corpus.Corpus, cs analysis.ClusterSet, known KnownClusters) corpus.Corpus {
+	if known == nil {
+		return c
+	}
+	dc := make(corpus.Corpus, len(c))
+	for name, sub := range c {
+		if known.isDuplicate(cs, name) {
+			OnArchiveSkipped(name, s.observers...)
+			continue
+		}
+		dc[name] = sub
+	}
+	return dc
+}
+
+// saveKnown merges the clusters cs, seen in a plan created at creation, into known and saves them.
+func (s *Saver) saveKnown(known KnownClusters, cs analysis.ClusterSet, creation time.Time) error {
+	if known == nil || len(cs) == 0 {
+		return nil
+	}
+	known.Observe(cs, creation)
+	if err := iohelp.Mkdirs(filepath.Dir(s.paths.FileKnownClusters)); err != nil {
+		return err
+	}
+	return known.Write(s.paths.FileKnownClusters)
+}
+
 func (s *Saver) normalisePlan(p *plan.Plan) (*plan.Plan, error) {
 	var err error
 
@@ -83,20 +202,22 @@ func (s *Saver) normalisePlan(p *plan.Plan) (*plan.Plan, error) {
 	return &np, err
 }
 
-func (s *Saver) runBucket(st status.Status, c corpus.Corpus, np *plan.Plan, creation time.Time) error {
+// runBucket saves the subjects in c, which have status st in analysis a, returning the pathset of the saved run if
+// there was one.
+func (s *Saver) runBucket(ctx context.Context, st status.Status, c corpus.Corpus, np *plan.Plan, a *analysis.Analysis) (*RunPathset, error) {
 	if !st.IsBad() || len(c) == 0 {
-		return nil
+		return nil, nil
 	}
-	paths, err := s.paths.SubjectRun(st, creation)
+	paths, err := s.paths.SubjectRun(st, np.Metadata.Creation)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	b := bucketSaver{
 		parent:   s,
 		s:        st,
 		plan:     np,
+		analysis: a,
 		paths:    paths,
-		creation: creation,
 	}
-	return b.save(c)
+	return paths, b.save(ctx, c)
 }
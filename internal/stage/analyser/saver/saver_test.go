@@ -0,0 +1,165 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package saver_test
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/stage/analyser/saver"
+	"github.com/c4-project/c4t/internal/stage/analyser/saver/mocks"
+	"github.com/c4-project/c4t/internal/subject/status"
+)
+
+// TestSaver_Run_skipKnownClusters tests that a saver skipping known clusters doesn't archive the same failures twice.
+func TestSaver_Run_skipKnownClusters(t *testing.T) {
+	t.Parallel()
+
+	an, err := analysis.Analyse(context.Background(), plan.Mock())
+	require.NoError(t, err, "analysing mock plan")
+
+	var (
+		ar  mocks.Archiver
+		obs mocks.Observer
+	)
+	ar.Test(t)
+	obs.Test(t)
+	ar.On("ArchiveFile", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	ar.On("Close").Return(nil)
+	obs.On("OnArchive", mock.Anything).Return()
+
+	var archived []string
+	ps := saver.NewPathset(t.TempDir())
+	s, err := saver.New(ps, func(path string) (saver.Archiver, error) {
+		archived = append(archived, filepath.Base(path))
+		return &ar, nil
+	}, saver.SkipKnownClusters(true), saver.ObserveWith(&obs))
+	require.NoError(t, err, "constructing saver")
+
+	require.NoError(t, s.Run(context.Background(), *an), "first save")
+	sort.Strings(archived)
+	assert.Equal(t, []string{"bar.tar.gz", "barbaz.tar.gz", "baz.tar.gz"}, archived, "first save should archive all")
+	assert.FileExists(t, ps.FileKnownClusters, "known clusters should have been recorded")
+
+	archived = nil
+	require.NoError(t, s.Run(context.Background(), *an), "second save")
+	assert.Empty(t, archived, "second save should archive nothing")
+	obs.AssertCalled(t, "OnArchive", saver.ArchiveMessage{Kind: saver.ArchiveSkipped, SubjectName: "baz"})
+
+	known, err := saver.LoadKnownClusters(ps.FileKnownClusters)
+	require.NoError(t, err, "loading known clusters")
+	assert.Len(t, known, len(an.Clusters), "wrong number of known clusters")
+}
+
+// TestSaver_Run_sendTo tests that a saver with a target sends its saved runs there, and clears its save directory.
+func TestSaver_Run_sendTo(t *testing.T) {
+	t.Parallel()
+
+	an, err := analysis.Analyse(context.Background(), plan.Mock())
+	require.NoError(t, err, "analysing mock plan")
+
+	var obs mocks.Observer
+	obs.Test(t)
+	obs.On("OnArchive", mock.Anything).Return()
+
+	ps := saver.NewPathset(t.TempDir())
+	ps.TargetPrefix = "localhost"
+	tgt := saver.DirTarget{Root: t.TempDir()}
+	s, err := saver.New(ps, saver.FormatZip.Create,
+		saver.ArchiveSuffix(saver.FormatZip.Suffix()),
+		saver.SendTo(tgt),
+		saver.ObserveWith(&obs),
+	)
+	require.NoError(t, err, "constructing saver")
+	require.NoError(t, s.Run(context.Background(), *an), "saving")
+
+	// Runs should be under the target prefix.
+	sub := saver.DirTarget{Root: filepath.Join(tgt.Root, "localhost")}
+	files, err := sub.List(context.Background())
+	require.NoError(t, err, "listing target")
+	runs := saver.SavedRuns(files)
+	require.Len(t, runs, 3, "should be one run per bad status")
+	for _, r := range runs {
+		assert.Contains(t, r.Files, r.Path+"/plan.json.gz", "run should include its plan")
+		obs.AssertCalled(t, "OnArchive", saver.ArchiveMessage{Kind: saver.ArchiveSent, File: "localhost/" + r.Path})
+	}
+	zpath := path.Join(runs[0].Path, "bar.zip")
+	assert.Contains(t, files, saver.StoredFile{
+		Path: zpath,
+		Size: fileSize(t, filepath.Join(sub.Root, filepath.FromSlash(zpath))),
+	}, "archives should use the configured suffix")
+
+	for _, d := range ps.DirList() {
+		entries, err := os.ReadDir(d)
+		if err == nil {
+			assert.Empty(t, entries, "sent runs should be removed from %s", d)
+		}
+	}
+}
+
+// TestSaver_Run_pruneEvery tests that a saver doesn't prune more often than its prune interval.
+func TestSaver_Run_pruneEvery(t *testing.T) {
+	t.Parallel()
+
+	an, err := analysis.Analyse(context.Background(), plan.Mock())
+	require.NoError(t, err, "analysing mock plan")
+
+	cases := map[string]struct {
+		every  time.Duration
+		pruned bool
+	}{
+		"always":  {every: 0, pruned: true},
+		"default": {every: saver.DefaultPruneInterval, pruned: false},
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ps := saver.NewPathset(t.TempDir())
+			s, err := saver.New(ps, saver.FormatDir.Create,
+				saver.ArchiveSuffix(saver.FormatDir.Suffix()),
+				saver.Retain(saver.Retention{MaxAge: 24 * time.Hour}),
+				saver.PruneEvery(c.every),
+			)
+			require.NoError(t, err, "constructing saver")
+			require.NoError(t, s.Run(context.Background(), *an), "first save")
+
+			// This run is well past the retention policy, but the second save should only prune it if it is allowed
+			// to prune again so soon.
+			rp, err := ps.SubjectRun(status.Flagged, time.Date(2000, time.January, 1, 0, 0, 0, 0, time.Local))
+			require.NoError(t, err, "getting old run pathset")
+			old := rp.SubjectArchiveFile("old", ".tar.gz")
+			require.NoError(t, os.MkdirAll(filepath.Dir(old), 0755), "making old run")
+			require.NoError(t, os.WriteFile(old, []byte("old"), 0644), "writing old run")
+
+			require.NoError(t, s.Run(context.Background(), *an), "second save")
+			if c.pruned {
+				assert.NoFileExists(t, old, "old run should have been pruned")
+			} else {
+				assert.FileExists(t, old, "old run shouldn't have been pruned")
+			}
+		})
+	}
+}
+
+func fileSize(t *testing.T, path string) int64 {
+	t.Helper()
+	info, err := os.Stat(path)
+	require.NoError(t, err, "stat", path)
+	return info.Size()
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package sigv4 implements the parts of AWS Signature Version 4 that c4t needs to talk to S3-compatible object stores.
+package sigv4
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// HeaderDate is the header in which Sign puts the signing time.
+	HeaderDate = "X-Amz-Date"
+	// HeaderSecurityToken is the header in which Sign puts any session token.
+	HeaderSecurityToken = "X-Amz-Security-Token"
+	// EmptyPayloadHash is the hex-encoded SHA-256 hash of an empty payload.
+	EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	dateFormat = "20060102T150405Z"
+	dayFormat  = "20060102"
+	algorithm  = "AWS4-HMAC-SHA256"
+)
+
+// Credentials are the credentials used to sign requests.
+type Credentials struct {
+	// AccessKeyID is the access key ID.
+	AccessKeyID string
+	// SecretAccessKey is the secret access key.
+	SecretAccessKey string
+	// SessionToken is the session token, if the credentials are temporary.
+	SessionToken string
+}
+
+// Sign signs r in place, for service in region at time t, given the hex-encoded SHA-256 hash payloadHash of r's body.
+//
+// Sign adds the date header, a security token header if the credentials have a session token, and the
+// authorisation header; it doesn't otherwise change r.  It signs the host, and any Range, Content-Type, Content-MD5,
+// and X-Amz headers.  It encodes r's path once, as S3 expects, rather than twice, as most other services expect.
+func (c Credentials) Sign(r *http.Request, service, region, payloadHash string, t time.Time) {
+	t = t.UTC()
+	r.Header.Set(HeaderDate, t.Format(dateFormat))
+	if c.SessionToken != "" {
+		r.Header.Set(HeaderSecurityToken, c.SessionToken)
+	}
+
+	names, canonHeaders := canonicalHeaders(r)
+	canonReq := strings.Join([]string{
+		r.Method,
+		canonicalPath(r.URL.Path),
+		canonicalQuery(r.URL.Query()),
+		canonHeaders,
+		names,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{t.Format(dayFormat), region, service, "aws4_request"}, "/")
+	toSign := strings.Join([]string{algorithm, t.Format(dateFormat), scope, hexSHA256(canonReq)}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.SecretAccessKey), t.Format(dayFormat))
+	for _, k := range []string{region, service, "aws4_request"} {
+		key = hmacSHA256(key, k)
+	}
+	r.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		algorithm, c.AccessKeyID, scope, names, hex.EncodeToString(hmacSHA256(key, toSign)),
+	))
+}
+
+// canonicalHeaders gets the signed header names and canonical header block of r, without modifying r.
+func canonicalHeaders(r *http.Request) (names string, canon string) {
+	host := r.Host
+	if host == "" {
+		host = r.URL.Host
+	}
+	hs := map[string]string{"host": host}
+	for k, vs := range r.Header {
+		lk := strings.ToLower(k)
+		if !isSigned(lk) {
+			continue
+		}
+		trimmed := make([]string, len(vs))
+		for i, v := range vs {
+			trimmed[i] = strings.Join(strings.Fields(v), " ")
+		}
+		hs[lk] = strings.Join(trimmed, ",")
+	}
+	keys := make([]string, 0, len(hs))
+	for k := range hs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		sb.WriteString(k + ":" + hs[k] + "\n")
+	}
+	return strings.Join(keys, ";"), sb.String()
+}
+
+// isSigned gets whether we sign the header with lowercase name lk.
+func isSigned(lk string) bool {
+	return strings.HasPrefix(lk, "x-amz-") || lk == "range" || lk == "content-type" || lk == "content-md5"
+}
+
+func canonicalPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	return URIEncode(p, true)
+}
+
+func canonicalQuery(q url.Values) string {
+	parts := make([]string, 0, len(q))
+	for k, vs := range q {
+		for _, v := range vs {
+			parts = append(parts, URIEncode(k, false)+"="+URIEncode(v, false))
+		}
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "&")
+}
+
+// URIEncode percent-encodes every byte of s other than RFC 3986 unreserved characters (and slashes, if keepSlash).
+func URIEncode(s string, keepSlash bool) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			sb.WriteByte(c)
+		case c == '/' && keepSlash:
+			sb.WriteByte(c)
+		default:
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func hexSHA256(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
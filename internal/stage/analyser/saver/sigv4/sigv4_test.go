@@ -0,0 +1,90 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package sigv4_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/stage/analyser/saver/sigv4"
+)
+
+// testCreds are the credentials used in the AWS Signature Version 4 test suite.
+var testCreds = sigv4.Credentials{
+	AccessKeyID:     "AKIDEXAMPLE",
+	SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+}
+
+// TestCredentials_Sign tests signing against vectors from the AWS Signature Version 4 test suite.
+func TestCredentials_Sign(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		method string
+		url    string
+		want   string
+	}{
+		"get-vanilla": {
+			method: http.MethodGet,
+			url:    "https://example.amazonaws.com/",
+			want:   "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		"get-vanilla-query-order-key-case": {
+			method: http.MethodGet,
+			url:    "https://example.amazonaws.com/?Param2=value2&Param1=value1",
+			want:   "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+		},
+		"post-vanilla": {
+			method: http.MethodPost,
+			url:    "https://example.amazonaws.com/",
+			want:   "5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b",
+		},
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequest(c.method, c.url, nil)
+			require.NoError(t, err, "making request")
+			testCreds.Sign(req, "service", "us-east-1", sigv4.EmptyPayloadHash, time.Date(2015, time.August, 30, 12, 36, 0, 0, time.UTC))
+
+			assert.Equal(t, "20150830T123600Z", req.Header.Get(sigv4.HeaderDate))
+			assert.Equal(t,
+				"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+					"SignedHeaders=host;x-amz-date, Signature="+c.want,
+				req.Header.Get("Authorization"))
+		})
+	}
+}
+
+// TestCredentials_Sign_headers tests that signing trims the whitespace in signed header values without changing them.
+func TestCredentials_Sign_headers(t *testing.T) {
+	t.Parallel()
+
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	require.NoError(t, err, "making request")
+	req.Header.Add("X-Amz-Meta-Foo", "  a   b ")
+	req.Header.Add("X-Amz-Meta-Foo", "c")
+	testCreds.Sign(req, "service", "us-east-1", sigv4.EmptyPayloadHash, time.Date(2015, time.August, 30, 12, 36, 0, 0, time.UTC))
+
+	assert.Equal(t, []string{"  a   b ", "c"}, req.Header.Values("X-Amz-Meta-Foo"), "signing shouldn't change headers")
+	assert.Contains(t, req.Header.Get("Authorization"), "SignedHeaders=host;x-amz-date;x-amz-meta-foo,")
+}
+
+func ExampleURIEncode() {
+	fmt.Println(sigv4.URIEncode("/my dir/a+b~c.txt", true))
+	fmt.Println(sigv4.URIEncode("/my dir/a+b~c.txt", false))
+
+	// Output:
+	// /my%20dir/a%2Bb~c.txt
+	// %2Fmy%20dir%2Fa%2Bb~c.txt
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package saver
+
+import (
+	"archive/tar"
+	"fmt"
+	"os"
+
+	"github.com/c4-project/c4t/internal/helper/iohelp"
+)
+
+// archiveTarFile tars the file at rpath to wpath within tw, and with the flags mode.
+func archiveTarFile(tw *tar.Writer, rpath, wpath string, mode int64) error {
+	if err := tarFileHeader(tw, rpath, wpath, mode); err != nil {
+		return err
+	}
+	return tarFileContents(tw, rpath)
+}
+
+func tarFileHeader(tw *tar.Writer, rpath string, wpath string, mode int64) error {
+	hdr, err := makeTarFileHeader(rpath, wpath, mode)
+	if err != nil {
+		return fmt.Errorf("making archive header for %s: %w", rpath, err)
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("writing archive header for %s: %w", rpath, err)
+	}
+	return nil
+}
+
+func tarFileContents(tw *tar.Writer, rpath string) error {
+	f, err := os.Open(rpath)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", rpath, err)
+	}
+	if _, err = iohelp.CopyCloseSrc(tw, f); err != nil {
+		return fmt.Errorf("archiving %s: %w", rpath, err)
+	}
+	return nil
+}
+
+func makeTarFileHeader(rpath, wpath string, mode int64) (*tar.Header, error) {
+	info, err := os.Stat(rpath)
+	if err != nil {
+		return nil, fmt.Errorf("can't stat %s: %w", rpath, err)
+	}
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return nil, fmt.Errorf("can't get header for %s: %w", rpath, err)
+	}
+	hdr.Name = wpath
+	hdr.Mode = mode
+	return hdr, nil
+}
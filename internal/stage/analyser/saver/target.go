@@ -0,0 +1,223 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package saver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/c4-project/c4t/internal/copier"
+	"github.com/c4-project/c4t/internal/helper/iohelp"
+	"github.com/pkg/sftp"
+)
+
+// Target is the interface of places to which the saver can send saved runs.
+//
+// All paths on a target are slashpaths relative to its root.
+type Target interface {
+	// Put stores the local file at lpath on the target at rpath.
+	Put(ctx context.Context, rpath, lpath string) error
+	// List lists every file stored on the target.
+	List(ctx context.Context) ([]StoredFile, error)
+	// Delete deletes the file stored on the target at rpath.
+	Delete(ctx context.Context, rpath string) error
+
+	// Closer captures that a Target can be closed; this should free all resources attached to the target.
+	io.Closer
+}
+
+// StoredFile describes a file stored on a target.
+type StoredFile struct {
+	// Path is the slashpath of the file relative to the target's root.
+	Path string
+	// Size is the size of the file, in bytes.
+	Size int64
+}
+
+// DirTarget is a target that stores files in a local directory.
+type DirTarget struct {
+	// Root is the root directory of the target.
+	Root string
+}
+
+// Put copies the file at lpath to rpath under the target directory.
+func (d DirTarget) Put(ctx context.Context, rpath, lpath string) error {
+	return copier.SendMapping(ctx, copier.Local{}, map[string]string{d.local(rpath): lpath})
+}
+
+// List walks the target directory.
+func (d DirTarget) List(ctx context.Context) ([]StoredFile, error) {
+	var files []StoredFile
+	err := filepath.WalkDir(d.Root, func(p string, de fs.DirEntry, err error) error {
+		if err != nil {
+			return rescueNotExist(err)
+		}
+		if err := iohelp.CheckDone(ctx); err != nil {
+			return err
+		}
+		if de.IsDir() {
+			return nil
+		}
+		info, err := de.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(d.Root, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, StoredFile{Path: filepath.ToSlash(rel), Size: info.Size()})
+		return nil
+	})
+	return files, err
+}
+
+// Delete deletes the file at rpath under the target directory, along with any directories it leaves empty.
+func (d DirTarget) Delete(_ context.Context, rpath string) error {
+	return deleteAndPrune(os.Remove, rpath, d.local)
+}
+
+// Close does nothing.
+func (DirTarget) Close() error {
+	return nil
+}
+
+func (d DirTarget) local(rpath string) string {
+	return filepath.Join(d.Root, filepath.FromSlash(rpath))
+}
+
+// SFTPTarget is a target that stores files in a directory on an SFTP host.
+type SFTPTarget struct {
+	// Client is the SFTP client.
+	Client *sftp.Client
+	// Root is the root directory of the target on the host.
+	Root string
+	// closer, if non-nil, is closed alongside the client; usually this is the underlying SSH connection.
+	closer io.Closer
+}
+
+// Put sends the file at lpath to rpath under the target directory.
+func (s *SFTPTarget) Put(ctx context.Context, rpath, lpath string) error {
+	return copier.SendMapping(ctx, (*copier.SFTP)(s.Client), map[string]string{s.remote(rpath): lpath})
+}
+
+// List walks the target directory.
+func (s *SFTPTarget) List(ctx context.Context) ([]StoredFile, error) {
+	var files []StoredFile
+	w := s.Client.Walk(s.Root)
+	for w.Step() {
+		if err := iohelp.CheckDone(ctx); err != nil {
+			return files, err
+		}
+		if err := w.Err(); err != nil {
+			if err = rescueNotExist(err); err != nil {
+				return files, err
+			}
+			continue
+		}
+		if w.Stat().IsDir() {
+			continue
+		}
+		rel := strings.TrimPrefix(strings.TrimPrefix(w.Path(), s.Root), "/")
+		files = append(files, StoredFile{Path: rel, Size: w.Stat().Size()})
+	}
+	return files, nil
+}
+
+// Delete deletes the file at rpath under the target directory, along with any directories it leaves empty.
+func (s *SFTPTarget) Delete(_ context.Context, rpath string) error {
+	return deleteAndPrune(s.Client.Remove, rpath, s.remote)
+}
+
+// Close closes the SFTP client, and then any underlying connection.
+func (s *SFTPTarget) Close() error {
+	err := s.Client.Close()
+	if s.closer == nil {
+		return err
+	}
+	if cerr := s.closer.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (s *SFTPTarget) remote(rpath string) string {
+	return path.Join(s.Root, rpath)
+}
+
+// deleteAndPrune uses remove to delete the file at rpath, then tries to remove each of its parent directories
+// until one can't be removed (usually because it isn't empty).  abs maps target-relative paths to absolute ones.
+func deleteAndPrune(remove func(string) error, rpath string, abs func(string) string) error {
+	if err := remove(abs(rpath)); err != nil {
+		return err
+	}
+	for dir := path.Dir(rpath); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if remove(abs(dir)) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+func rescueNotExist(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// sendRun sends every file in the local run directory dir to t, placing it at rel within the target.
+func sendRun(ctx context.Context, t Target, dir, rel string) error {
+	return filepath.WalkDir(dir, func(p string, de fs.DirEntry, err error) error {
+		if err != nil || de.IsDir() {
+			return err
+		}
+		frel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		return t.Put(ctx, path.Join(rel, filepath.ToSlash(frel)), p)
+	})
+}
+
+// prefixTarget restricts a target to the files under a prefix.
+type prefixTarget struct {
+	Target
+	prefix string
+}
+
+// withPrefix restricts t to the files under prefix, if prefix is non-empty.
+func withPrefix(t Target, prefix string) Target {
+	if prefix == "" {
+		return t
+	}
+	return prefixTarget{Target: t, prefix: prefix}
+}
+
+func (p prefixTarget) Put(ctx context.Context, rpath, lpath string) error {
+	return p.Target.Put(ctx, path.Join(p.prefix, rpath), lpath)
+}
+
+func (p prefixTarget) List(ctx context.Context) ([]StoredFile, error) {
+	all, err := p.Target.List(ctx)
+	var files []StoredFile
+	for _, f := range all {
+		if rel := strings.TrimPrefix(f.Path, p.prefix+"/"); rel != f.Path {
+			files = append(files, StoredFile{Path: rel, Size: f.Size})
+		}
+	}
+	return files, err
+}
+
+func (p prefixTarget) Delete(ctx context.Context, rpath string) error {
+	return p.Target.Delete(ctx, path.Join(p.prefix, rpath))
+}
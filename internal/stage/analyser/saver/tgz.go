@@ -11,8 +11,6 @@ import (
 	"fmt"
 	"io"
 	"os"
-
-	"github.com/c4-project/c4t/internal/helper/iohelp"
 )
 
 // TGZWriter is a wrapper over a gzipped archive writer.
@@ -68,44 +66,5 @@ func (t *TGZWriter) ArchiveFile(rpath, wpath string, mode int64) error {
 		return nil
 	}
 
-	if err := t.tarFileHeader(rpath, wpath, mode); err != nil {
-		return err
-	}
-	return t.tarFileContents(rpath)
-}
-
-func (t *TGZWriter) tarFileHeader(rpath string, wpath string, mode int64) error {
-	hdr, err := makeTarFileHeader(rpath, wpath, mode)
-	if err != nil {
-		return fmt.Errorf("making archive header for %s: %w", rpath, err)
-	}
-	if err := t.tw.WriteHeader(hdr); err != nil {
-		return fmt.Errorf("writing archive header for %s: %w", rpath, err)
-	}
-	return nil
-}
-
-func (t *TGZWriter) tarFileContents(rpath string) error {
-	f, err := os.Open(rpath)
-	if err != nil {
-		return fmt.Errorf("opening %s: %w", rpath, err)
-	}
-	if _, err = iohelp.CopyCloseSrc(t.tw, f); err != nil {
-		return fmt.Errorf("archiving %s: %w", rpath, err)
-	}
-	return nil
-}
-
-func makeTarFileHeader(rpath, wpath string, mode int64) (*tar.Header, error) {
-	info, err := os.Stat(rpath)
-	if err != nil {
-		return nil, fmt.Errorf("can't stat %s: %w", rpath, err)
-	}
-	hdr, err := tar.FileInfoHeader(info, "")
-	if err != nil {
-		return nil, fmt.Errorf("can't get header for %s: %w", rpath, err)
-	}
-	hdr.Name = wpath
-	hdr.Mode = mode
-	return hdr, nil
+	return archiveTarFile(t.tw, rpath, wpath, mode)
 }
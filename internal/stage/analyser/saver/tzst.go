@@ -0,0 +1,63 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package saver
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os/exec"
+)
+
+// ZstdCommand is the name of the program used to compress zstd tarballs.
+const ZstdCommand = "zstd"
+
+// TarZstdWriter is a wrapper over a tarball piped through an external zstd process.
+type TarZstdWriter struct {
+	tw  *tar.Writer
+	in  io.WriteCloser
+	cmd *exec.Cmd
+}
+
+// CreateTarZstd creates the file at path and opens a TarZstdWriter into it.
+func CreateTarZstd(path string) (*TarZstdWriter, error) {
+	cmd := exec.Command(ZstdCommand, "-q", "-f", "-o", path)
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("piping to %s: %w", ZstdCommand, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting %s for %s: %w", ZstdCommand, path, err)
+	}
+	return &TarZstdWriter{tw: tar.NewWriter(in), in: in, cmd: cmd}, nil
+}
+
+// Close closes the tarball, then waits for the zstd process to finish compressing it.
+func (t *TarZstdWriter) Close() error {
+	terr := t.tw.Close()
+	ierr := t.in.Close()
+	werr := t.cmd.Wait()
+
+	if terr != nil {
+		return fmt.Errorf("closing archive: %w", terr)
+	}
+	if ierr != nil {
+		return fmt.Errorf("closing pipe to %s: %w", ZstdCommand, ierr)
+	}
+	if werr != nil {
+		return fmt.Errorf("running %s: %w", ZstdCommand, werr)
+	}
+	return nil
+}
+
+// ArchiveFile tars the file at rpath to wpath within this archive, and with the flags mode.
+// If rpath is empty, no tarring occurs.
+func (t *TarZstdWriter) ArchiveFile(rpath, wpath string, mode int64) error {
+	if rpath == "" {
+		return nil
+	}
+	return archiveTarFile(t.tw, rpath, wpath, mode)
+}
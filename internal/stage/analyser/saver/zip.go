@@ -0,0 +1,81 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package saver
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/c4-project/c4t/internal/helper/iohelp"
+)
+
+// ZipWriter is a wrapper over a zip archive writer.
+type ZipWriter struct {
+	zw *zip.Writer
+	bw io.WriteCloser
+}
+
+// NewZipWriter creates a new zip writer on top of the basic writer w.
+func NewZipWriter(w io.WriteCloser) *ZipWriter {
+	return &ZipWriter{zw: zip.NewWriter(w), bw: w}
+}
+
+// CreateZip creates the file at path and opens a ZipWriter into it.
+func CreateZip(path string) (*ZipWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("create %s: %w", path, err)
+	}
+	return NewZipWriter(f), nil
+}
+
+// Close closes the archive and then its underlying writer, returning the first error.
+func (z *ZipWriter) Close() error {
+	zerr := z.zw.Close()
+	berr := z.bw.Close()
+
+	if zerr != nil {
+		return fmt.Errorf("closing archive: %w", zerr)
+	}
+	if berr != nil {
+		return fmt.Errorf("closing file: %w", berr)
+	}
+	return nil
+}
+
+// ArchiveFile zips the file at rpath to wpath within this archive, and with the flags mode.
+// If rpath is empty, no zipping occurs.
+func (z *ZipWriter) ArchiveFile(rpath, wpath string, mode int64) error {
+	if rpath == "" {
+		return nil
+	}
+	info, err := os.Stat(rpath)
+	if err != nil {
+		return fmt.Errorf("can't stat %s: %w", rpath, err)
+	}
+	hdr, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return fmt.Errorf("can't get header for %s: %w", rpath, err)
+	}
+	hdr.Name = wpath
+	hdr.Method = zip.Deflate
+	hdr.SetMode(os.FileMode(mode))
+
+	w, err := z.zw.CreateHeader(hdr)
+	if err != nil {
+		return fmt.Errorf("writing archive header for %s: %w", rpath, err)
+	}
+	f, err := os.Open(rpath)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", rpath, err)
+	}
+	if _, err = iohelp.CopyCloseSrc(w, f); err != nil {
+		return fmt.Errorf("archiving %s: %w", rpath, err)
+	}
+	return nil
+}
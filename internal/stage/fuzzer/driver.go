@@ -7,6 +7,9 @@ package fuzzer
 
 import (
 	"context"
+	"fmt"
+
+	"github.com/c4-project/c4t/internal/model/service"
 
 	"github.com/c4-project/c4t/internal/model/service/fuzzer"
 
@@ -36,3 +39,20 @@ func (a AggregateDriver) Fuzz(ctx context.Context, job fuzzer.Job) error {
 func (a AggregateDriver) DumpStats(ctx context.Context, s *litmus.Statset, path string) error {
 	return a.Stat.DumpStats(ctx, s, path)
 }
+
+// ResolveDriver gets the driver to use for fuzzing with the config cfg.
+// If cfg names a fuzzer style, this uses r to resolve it into a fuzzer that runs services using sr, and uses def only
+// to dump statistics; otherwise, it returns def.
+func ResolveDriver(def Driver, cfg *fuzzer.Config, r fuzzer.Resolver, sr service.Runner) (Driver, error) {
+	if cfg == nil || cfg.Style.IsEmpty() {
+		return def, nil
+	}
+	if r == nil {
+		return nil, fmt.Errorf("%w: can't resolve fuzzer style %s", ErrResolverNil, cfg.Style)
+	}
+	f, err := fuzzer.ResolveAndInstantiate(*cfg, r, sr)
+	if err != nil {
+		return nil, err
+	}
+	return AggregateDriver{Single: f, Stat: def}, nil
+}
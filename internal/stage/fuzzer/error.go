@@ -10,4 +10,7 @@ import "errors"
 var (
 	// ErrDriverNil occurs when the fuzzer tries to use the nil pointer as its single-fuzz driver.
 	ErrDriverNil = errors.New("driver nil")
+
+	// ErrResolverNil occurs when a fuzzer config names a fuzzer style, but there is no fuzzer resolver to resolve it.
+	ErrResolverNil = errors.New("fuzzer resolver nil")
 )
@@ -46,6 +46,20 @@ type SubjectPather interface {
 
 //go:generate mockery --name=SubjectPather
 
+// Tuner is the interface of things that pick the values of adaptively tuned fuzzer parameters.
+type Tuner interface {
+	// Tune picks a value for each tuned parameter, using rng for any random decisions.
+	Tune(rng *rand.Rand) map[string]string
+}
+
+// FixedParams is a Tuner that always picks the same parameter values, such as when replaying a past cycle.
+type FixedParams map[string]string
+
+// Tune returns f.
+func (f FixedParams) Tune(*rand.Rand) map[string]string {
+	return f
+}
+
 // Fuzzer holds the state required for the fuzzing stage of the tester.
 type Fuzzer struct {
 	// driver holds the fuzzer's low-level implementation structs.
@@ -58,6 +72,8 @@ type Fuzzer struct {
 	quantities quantity.FuzzSet
 	// config sets various options on the fuzzer itself.
 	config *fuzzer.Config
+	// tuner, if non-nil, picks values for tuned parameters at the start of each run.
+	tuner Tuner
 }
 
 // New constructs a fuzzer with the config c and plan p.
@@ -109,7 +125,7 @@ func (f *Fuzzer) Run(ctx context.Context, p *plan.Plan) (*plan.Plan, error) {
 	}
 
 	rng := p.Metadata.Rand()
-	fcs, ferr := f.fuzzCorpus(ctx, rng, p.Corpus, p.Machine.Machine)
+	fcs, ferr := f.fuzzCorpus(ctx, rng, f.tune(p), p.Corpus, p.Machine.Machine)
 	if ferr != nil {
 		return nil, ferr
 	}
@@ -117,6 +133,23 @@ func (f *Fuzzer) Run(ctx context.Context, p *plan.Plan) (*plan.Plan, error) {
 	return f.sampleAndUpdatePlan(fcs, rng, *p)
 }
 
+// tunerSeedMask is XORed into the plan seed to seed the tuner's random number generator.
+//
+// It spells 'tuner' in ASCII; any nonzero value would do.
+const tunerSeedMask int64 = 0x74756e6572
+
+// tune picks values for any tuned parameters for the run on plan p.
+//
+// The tuner gets its own random number generator, seeded from the plan, so that tuning is reproducible and doesn't
+// disturb the seeds given to the fuzzer.  Its seed differs from the plan seed, so that its draws don't simply repeat
+// those that pick the fuzzer seeds and sample the corpus.
+func (f *Fuzzer) tune(p *plan.Plan) map[string]string {
+	if f.tuner == nil {
+		return nil
+	}
+	return f.tuner.Tune(rand.New(rand.NewSource(p.Metadata.Seed ^ tunerSeedMask)))
+}
+
 // sampleAndUpdatePlan samples fcs, updates the fresh plan copy p with it, and returns a pointer to it.
 func (f *Fuzzer) sampleAndUpdatePlan(fcs corpus.Corpus, rng *rand.Rand, p plan.Plan) (*plan.Plan, error) {
 	// TODO(@MattWindsor91): add some observer calls here?
@@ -139,32 +172,35 @@ func (f *Fuzzer) count(c corpus.Corpus) (nsubjects, nruns int) {
 }
 
 // fuzzCorpus actually does the business of fuzzing.
-func (f *Fuzzer) fuzzCorpus(ctx context.Context, rng *rand.Rand, c corpus.Corpus, m machine.Machine) (corpus.Corpus, error) {
+func (f *Fuzzer) fuzzCorpus(ctx context.Context, rng *rand.Rand, tuned map[string]string, c corpus.Corpus, m machine.Machine) (corpus.Corpus, error) {
 	_, nfuzzes := f.count(c)
 
 	seeds := corpusSeeds(rng, c)
 
 	mf := builder.Manifest{Name: "fuzz", NReqs: nfuzzes}
-	bc := builder.Config{Manifest: mf, Observers: f.observers}
+	// Different cycles can produce the same test, and there's no point keeping more than one of them.
+	bc := builder.Config{Manifest: mf, Observers: f.observers, Dedup: true}
 	return builder.ParBuild(ctx, f.quantities.NWorkers, c, bc, func(ctx context.Context, s subject.Named, ch chan<- builder.Request) error {
-		return f.makeInstance(s, seeds[s.Name], m, ch).Fuzz(ctx)
+		return f.makeInstance(s, seeds[s.Name], tuned, m, ch).Fuzz(ctx)
 	})
 }
 
 // corpusSeeds generates a seed for each subject in c using rng.
 //
 // This is necessary to avoid a race on the random number generator inside the parallel builder.
+// We hand out seeds in name order, so that they depend only on the plan seed and not on map iteration order.
 func corpusSeeds(rng *rand.Rand, c corpus.Corpus) map[string]int64 {
 	seeds := make(map[string]int64)
-	for n := range c {
+	for _, n := range c.Names() {
 		seeds[n] = rng.Int63()
 	}
 	return seeds
 }
 
-func (f *Fuzzer) makeInstance(s subject.Named, seed int64, m machine.Machine, resCh chan<- builder.Request) *Instance {
+func (f *Fuzzer) makeInstance(s subject.Named, seed int64, tuned map[string]string, m machine.Machine, resCh chan<- builder.Request) *Instance {
 	return &Instance{
-		Config:        f.config,
+		Config:        f.config.WithParams(tuned),
+		Tuned:         tuned,
 		Driver:        f.driver,
 		Subject:       s,
 		SubjectCycles: f.quantities.SubjectCycles,
@@ -13,6 +13,8 @@ import (
 
 	"github.com/c4-project/c4t/internal/timing"
 
+	fuzzer2 "github.com/c4-project/c4t/internal/model/service/fuzzer"
+
 	"github.com/c4-project/c4t/internal/quantity"
 
 	"github.com/c4-project/c4t/internal/plan/stage"
@@ -228,3 +230,47 @@ func TestFuzzer_Run_nop(t *testing.T) {
 	mp.AssertExpectations(t)
 	md.AssertExpectations(t)
 }
+
+// TestFuzzer_Run_tuned tests that the fuzzer passes tuned parameters to its driver, and records them on its output.
+func TestFuzzer_Run_tuned(t *testing.T) {
+	t.Parallel()
+
+	md := new(mocks2.StatDumper)
+	md.Test(t)
+	mp := new(mocks.SubjectPather)
+	mp.Test(t)
+	ms := new(mocks.SingleFuzzer)
+	ms.Test(t)
+
+	tuned := fuzzer.FixedParams{"action.var.make": "10"}
+	f, err := fuzzer.New(
+		fuzzer.AggregateDriver{Single: ms, Stat: md},
+		mp,
+		fuzzer.UseConfig(&fuzzer2.Config{Params: map[string]string{"action.var.make": "0", "int.fixed": "1"}}),
+		fuzzer.TuneWith(tuned),
+	)
+	require.NoError(t, err, "unexpected error in New")
+
+	mp.On("Prepare").Return(nil).Once()
+	mp.On("SubjectLitmus", mock.Anything).Return("fuzz.litmus")
+	mp.On("SubjectTrace", mock.Anything).Return("fuzz.trace.txt")
+	md.On("DumpStats", mock.Anything, mock.Anything, "fuzz.litmus").Return(nil)
+	ms.On("Fuzz", mock.Anything, mock.MatchedBy(func(j fuzzer2.Job) bool {
+		return j.Config != nil && j.Config.Params["action.var.make"] == "10" && j.Config.Params["int.fixed"] == "1"
+	})).Return(nil)
+
+	p := makePlan()
+	p.Metadata.ConfirmStage(stage.Plan, timing.SpanFromInstant(time.Now()))
+
+	p2, err := f.Run(context.Background(), p)
+	require.NoError(t, err, "unexpected error in Run")
+	require.NotEmpty(t, p2.Corpus, "fuzzer should produce subjects")
+	for name, s := range p2.Corpus {
+		require.NotNil(t, s.Fuzz, "subject %s should have fuzz information", name)
+		assert.Equal(t, map[string]string(tuned), s.Fuzz.Params, "subject %s should record tuned parameters", name)
+	}
+
+	mp.AssertExpectations(t)
+	md.AssertExpectations(t)
+	ms.AssertExpectations(t)
+}
@@ -45,6 +45,9 @@ type Instance struct {
 	// Config is the specific configuration, if any, for the fuzzer.
 	Config *fuzzer.Config
 
+	// Tuned contains the values of any adaptively tuned parameters in Config, to be recorded on fuzzed subjects.
+	Tuned map[string]string
+
 	// Pathset points to the pathset to use to work out where to store fuzz output.
 	Pathset SubjectPather
 
@@ -98,12 +101,17 @@ func (j *Instance) fuzzCycle(ctx context.Context, cycle int) error {
 	if err != nil {
 		return nil
 	}
+	// Fingerprints only serve to deduplicate the fuzzed corpus, so a test without one is no great loss.
+	_ = l.PopulateFingerprintFromFile()
 
 	fz := subject.Fuzz{
 		Duration: dur,
 		Litmus:   *l,
 		Trace:    jb.OutTrace,
+		Params:   j.Tuned,
 	}
+	// Likewise, the hash only serves to check replays of this cycle.
+	_ = fz.PopulateHash()
 
 	nsub := j.fuzzedSubject(sc, &fz)
 	return builder.AddRequest(&nsub).SendTo(ctx, j.ResCh)
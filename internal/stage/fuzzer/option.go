@@ -43,6 +43,15 @@ func OverrideQuantities(qs quantity.FuzzSet) Option {
 	}
 }
 
+// TuneWith makes the fuzzer ask t for the values of tuned parameters at the start of each run.
+// t can be nil, in which case no parameters are tuned.
+func TuneWith(t Tuner) Option {
+	return func(f *Fuzzer) error {
+		f.tuner = t
+		return nil
+	}
+}
+
 // UseConfig populates settings for the fuzzer from the configuration cfg.
 func UseConfig(cfg *fuzzer.Config) Option {
 	// TODO(@MattWindsor91): this should probably install specific settings instead of copying itself.
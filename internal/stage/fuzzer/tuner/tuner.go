@@ -0,0 +1,233 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package tuner contains an adaptive tuner for fuzzer parameters.
+//
+// The tuner treats each tunable parameter as an independent multi-armed bandit whose arms are the parameter's
+// candidate values.  Each cycle, it picks one value per parameter, usually the one with the best reward rate so far
+// but sometimes (with probability epsilon) one at random; the fuzzer records the picked values on each fuzzed
+// subject, and the tuner rewards them according to whether those subjects turned out to be interesting.
+//
+// The tuner can persist its statistics to a file, so that what it learns carries over between runs of the director.
+package tuner
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"os"
+	"sync"
+
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/helper/iohelp"
+	"github.com/c4-project/c4t/internal/model/service/fuzzer"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/subject/status"
+)
+
+// Arm holds the statistics for one candidate value of a tuned parameter.
+type Arm struct {
+	// Value is the candidate value.
+	Value string `json:"value"`
+	// Pulls is the number of fuzzed subjects tested with this value.
+	Pulls uint64 `json:"pulls"`
+	// Rewards is the number of those subjects that were interesting.
+	Rewards uint64 `json:"rewards"`
+}
+
+// Mean gets the reward rate of this arm, or 0 if it has never been pulled.
+func (a Arm) Mean() float64 {
+	if a.Pulls == 0 {
+		return 0
+	}
+	return float64(a.Rewards) / float64(a.Pulls)
+}
+
+// Bandit is an epsilon-greedy bandit tuner over a set of fuzzer parameters.
+// It is safe for concurrent use, and so can be shared between director instances.
+type Bandit struct {
+	mu      sync.Mutex
+	epsilon float64
+	keys    []string
+	arms    map[string][]Arm
+	// path, if non-empty, is the file to which Learn saves the bandit's statistics.
+	path string
+}
+
+// New constructs a bandit tuner from the tuning configuration cfg.
+func New(cfg *fuzzer.TuneConfig) (*Bandit, error) {
+	b := Bandit{
+		epsilon: cfg.EpsilonOrDefault(),
+		keys:    cfg.Keys(),
+		arms:    make(map[string][]Arm, len(cfg.Params)),
+	}
+	for _, k := range b.keys {
+		vs, err := cfg.Params[k].Arms(k)
+		if err != nil {
+			return nil, err
+		}
+		arms := make([]Arm, len(vs))
+		for i, v := range vs {
+			arms[i] = Arm{Value: v}
+		}
+		b.arms[k] = arms
+	}
+	return &b, nil
+}
+
+// Tune picks a value for each tuned parameter, using rng for any random decisions.
+// Given the same statistics and the same random sequence, it always picks the same values.
+func (b *Bandit) Tune(rng *rand.Rand) map[string]string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ps := make(map[string]string, len(b.keys))
+	for _, k := range b.keys {
+		arms := b.arms[k]
+		if len(arms) == 0 {
+			continue
+		}
+		// Always drawing the exploration roll keeps the random sequence aligned across parameters.
+		if rng.Float64() < b.epsilon {
+			ps[k] = arms[rng.Intn(len(arms))].Value
+		} else {
+			ps[k] = arms[best(rng, arms)].Value
+		}
+	}
+	return ps
+}
+
+// best picks the index of an arm with the highest reward rate, preferring untried arms and breaking ties with rng.
+func best(rng *rand.Rand, arms []Arm) int {
+	var (
+		cands []int
+		top   float64
+	)
+	for i, a := range arms {
+		score := a.Mean()
+		if a.Pulls == 0 {
+			// Untried arms beat every tried one.
+			score = 2
+		}
+		switch {
+		case len(cands) == 0 || top < score:
+			cands, top = []int{i}, score
+		case score == top:
+			cands = append(cands, i)
+		}
+	}
+	return cands[rng.Intn(len(cands))]
+}
+
+// Record records that a subject was fuzzed with parameters ps, and whether it was interesting.
+// Parameters that aren't being tuned, or have values the tuner doesn't know, are ignored.
+func (b *Bandit) Record(ps map[string]string, interesting bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for k, v := range ps {
+		arms := b.arms[k]
+		for i := range arms {
+			if arms[i].Value != v {
+				continue
+			}
+			arms[i].Pulls++
+			if interesting {
+				arms[i].Rewards++
+			}
+		}
+	}
+}
+
+// OnAnalysis rewards the parameters of each tuned, fuzzed subject in a according to whether it was flagged.
+// (Mutants are only killed by flagged compilations, so this also rewards mutant kills.)
+func (b *Bandit) OnAnalysis(a analysis.Analysis) {
+	if a.Plan == nil {
+		return
+	}
+	flagged := a.ByStatus[status.Flagged]
+	for n, s := range a.Plan.Corpus {
+		if s.Fuzz == nil || len(s.Fuzz.Params) == 0 {
+			continue
+		}
+		_, interesting := flagged[n]
+		b.Record(s.Fuzz.Params, interesting)
+	}
+}
+
+// Learn rewards parameters according to the analysis a, as with OnAnalysis, then saves the bandit's statistics if
+// it is persisting them.
+func (b *Bandit) Learn(a analysis.Analysis) error {
+	b.OnAnalysis(a)
+
+	b.mu.Lock()
+	path := b.path
+	b.mu.Unlock()
+	if path == "" {
+		return nil
+	}
+	return b.Save(path)
+}
+
+// PersistTo loads any statistics saved in the JSON file at path into this bandit, and makes Learn save the
+// bandit's statistics back there.
+// The file need not exist yet.
+//
+// Saved statistics for parameters or values that are no longer being tuned are ignored, and dropped on the next
+// save.
+func (b *Bandit) PersistTo(path string) error {
+	saved, err := loadArms(path)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for k, arms := range b.arms {
+		merge(arms, saved[k])
+	}
+	b.path = path
+	return nil
+}
+
+// merge copies the statistics of each arm in saved into the arm in arms with the same value, if any.
+func merge(arms, saved []Arm) {
+	for _, s := range saved {
+		for i := range arms {
+			if arms[i].Value == s.Value {
+				arms[i].Pulls, arms[i].Rewards = s.Pulls, s.Rewards
+			}
+		}
+	}
+}
+
+// loadArms loads saved bandit statistics from the JSON file at path, which need not exist.
+func loadArms(path string) (map[string][]Arm, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var arms map[string][]Arm
+	derr := json.NewDecoder(f).Decode(&arms)
+	cerr := f.Close()
+	return arms, errhelp.FirstError(derr, cerr)
+}
+
+// Save atomically replaces the JSON file at path with this bandit's statistics.
+func (b *Bandit) Save(path string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return iohelp.WriteJSONFileAtomic(path, b.arms)
+}
+
+// Arms gets a copy of the statistics for each candidate value of the tuned parameter key.
+func (b *Bandit) Arms(key string) []Arm {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]Arm(nil), b.arms[key]...)
+}
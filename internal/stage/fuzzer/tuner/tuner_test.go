@@ -0,0 +1,173 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package tuner_test
+
+import (
+	"math"
+	"math/rand"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/model/service/fuzzer"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/stage/fuzzer/tuner"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/c4-project/c4t/internal/subject/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tuneConfig(epsilon float64) *fuzzer.TuneConfig {
+	return &fuzzer.TuneConfig{
+		Epsilon: epsilon,
+		Params: map[string]fuzzer.TuneSpace{
+			"action.var.make":               {Min: 0, Max: 20, Step: 10},
+			"bool.mem.unsafe-weaken-orders": {},
+		},
+	}
+}
+
+// TestNew_badSpace tests that New rejects tuning spaces that can't be expanded.
+func TestNew_badSpace(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]fuzzer.TuneSpace{
+		"backwards":     {Min: 5, Max: 1},
+		"negative-step": {Min: 1, Max: 5, Step: -1},
+		"too-big":       {Min: 0, Max: fuzzer.MaxTuneArms},
+		"huge":          {Min: math.MinInt, Max: math.MaxInt, Step: 2},
+	}
+	for name, s := range cases {
+		s := s
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := tuner.New(&fuzzer.TuneConfig{Params: map[string]fuzzer.TuneSpace{"int.foo": s}})
+			assert.ErrorIs(t, err, fuzzer.ErrBadTuneSpace)
+		})
+	}
+}
+
+// TestNew_maxSpace tests that New accepts a tuning space of the largest allowed size.
+func TestNew_maxSpace(t *testing.T) {
+	t.Parallel()
+
+	b, err := tuner.New(&fuzzer.TuneConfig{Params: map[string]fuzzer.TuneSpace{
+		"int.foo": {Min: math.MaxInt - 2*(fuzzer.MaxTuneArms-1), Max: math.MaxInt, Step: 2},
+	}})
+	require.NoError(t, err, "making tuner")
+	arms := b.Arms("int.foo")
+	require.Len(t, arms, fuzzer.MaxTuneArms)
+	assert.Equal(t, strconv.Itoa(math.MaxInt), arms[len(arms)-1].Value, "range should end at its maximum")
+}
+
+// TestBandit_Tune_reproducible tests that the same seed gives the same parameters.
+func TestBandit_Tune_reproducible(t *testing.T) {
+	t.Parallel()
+
+	b, err := tuner.New(tuneConfig(0.5))
+	require.NoError(t, err, "making tuner")
+	for seed := int64(0); seed < 10; seed++ {
+		p1 := b.Tune(rand.New(rand.NewSource(seed)))
+		p2 := b.Tune(rand.New(rand.NewSource(seed)))
+		assert.Equal(t, p1, p2, "tuning with seed %d should be reproducible", seed)
+		assert.Len(t, p1, 2, "every tuned parameter should get a value")
+	}
+}
+
+// TestBandit_Tune_exploit tests that, once every arm has been tried, a greedy bandit picks the most rewarding one.
+func TestBandit_Tune_exploit(t *testing.T) {
+	t.Parallel()
+
+	// This epsilon is tiny enough that the bandit should never explore in this test.
+	b, err := tuner.New(tuneConfig(1e-12))
+	require.NoError(t, err, "making tuner")
+
+	rng := rand.New(rand.NewSource(1))
+	seen := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		ps := b.Tune(rng)
+		v := ps["action.var.make"]
+		assert.False(t, seen[v], "bandit should try each untried arm before repeating one")
+		seen[v] = true
+		b.Record(ps, v == "10")
+	}
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, "10", b.Tune(rng)["action.var.make"], "bandit should exploit the rewarding arm")
+	}
+	arms := b.Arms("action.var.make")
+	require.Len(t, arms, 3)
+	assert.Equal(t, tuner.Arm{Value: "10", Pulls: 1, Rewards: 1}, arms[1])
+}
+
+// TestBandit_OnAnalysis tests that analyses reward the parameters of flagged fuzzed subjects.
+func TestBandit_OnAnalysis(t *testing.T) {
+	t.Parallel()
+
+	b, err := tuner.New(tuneConfig(0))
+	require.NoError(t, err, "making tuner")
+
+	fuzzed := func(v string) subject.Subject {
+		return subject.Subject{Fuzz: &subject.Fuzz{Params: map[string]string{"action.var.make": v}}}
+	}
+	p := plan.Mock()
+	p.Corpus = corpus.Corpus{
+		"foo_0": fuzzed("0"),
+		"foo_1": fuzzed("20"),
+		"bar_0": fuzzed("20"),
+		"baz":   {},
+	}
+	b.OnAnalysis(analysis.Analysis{
+		Plan:     p,
+		ByStatus: map[status.Status]corpus.Corpus{status.Flagged: {"foo_1": fuzzed("20"), "baz": {}}},
+	})
+
+	assert.Equal(t,
+		[]tuner.Arm{{Value: "0", Pulls: 1}, {Value: "10"}, {Value: "20", Pulls: 2, Rewards: 1}},
+		b.Arms("action.var.make"))
+	assert.Equal(t,
+		[]tuner.Arm{{Value: "true"}, {Value: "false"}},
+		b.Arms("bool.mem.unsafe-weaken-orders"),
+		"untouched parameters should keep empty statistics")
+}
+
+// TestBandit_PersistTo tests that a bandit's statistics survive being saved and loaded into a new bandit.
+func TestBandit_PersistTo(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "tuner.json")
+
+	b1, err := tuner.New(tuneConfig(0))
+	require.NoError(t, err, "making first tuner")
+	require.NoError(t, b1.PersistTo(path), "persisting first tuner to nonexistent file")
+	b1.Record(map[string]string{"action.var.make": "10", "bool.mem.unsafe-weaken-orders": "false"}, true)
+	b1.Record(map[string]string{"action.var.make": "20"}, false)
+	require.NoError(t, b1.Learn(analysis.Analysis{}), "saving first tuner")
+
+	// The second tuner has a different space: its new values start untried, and its lost values are ignored.
+	b2, err := tuner.New(&fuzzer.TuneConfig{Params: map[string]fuzzer.TuneSpace{
+		"action.var.make": {Min: 10, Max: 30, Step: 10},
+	}})
+	require.NoError(t, err, "making second tuner")
+	require.NoError(t, b2.PersistTo(path), "loading into second tuner")
+
+	assert.Equal(t,
+		[]tuner.Arm{{Value: "10", Pulls: 1, Rewards: 1}, {Value: "20", Pulls: 1}, {Value: "30"}},
+		b2.Arms("action.var.make"))
+	assert.Empty(t, b2.Arms("bool.mem.unsafe-weaken-orders"), "untuned parameter shouldn't be loaded")
+}
+
+// TestBandit_Learn_notPersisted tests that Learn doesn't write anything for a bandit that isn't persisting.
+func TestBandit_Learn_notPersisted(t *testing.T) {
+	t.Parallel()
+
+	b, err := tuner.New(tuneConfig(0))
+	require.NoError(t, err, "making tuner")
+	assert.NoError(t, b.Learn(analysis.Analysis{}))
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package budget learns how long test harness iterations take, and chooses iteration parameters to fit a time budget.
+package budget
+
+import (
+	"time"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+)
+
+// DefaultIterations are the iteration parameters used for harnesses whose speed we don't yet know.
+// They match litmus7's own defaults.
+var DefaultIterations = compilation.Iterations{Size: 100000, Count: 10}
+
+// MaxCount is the largest number of batches we will ask a harness to run, however fast it is.
+// It stops a harness whose recorded timing is implausibly short from being asked to run indefinitely.
+const MaxCount = 10000
+
+// budgetKey identifies a subject-compiler pairing for budgeting purposes.
+type budgetKey struct {
+	subject  string
+	compiler id.ID
+}
+
+// Budgeter learns how long harness iterations take, and uses this to choose iteration parameters that make each run
+// take roughly a target amount of wall-clock time.
+type Budgeter struct {
+	// perSubject maps each subject-compiler pairing to its last observed time, in seconds, per iteration.
+	perSubject map[budgetKey]float64
+	// perCompiler maps each compiler to its last observed mean time, in seconds, per iteration.
+	// We use it for subjects we haven't yet seen.
+	perCompiler map[id.ID]float64
+}
+
+// New constructs a budgeter that knows no timings yet.
+func New() *Budgeter {
+	return &Budgeter{perSubject: map[budgetKey]float64{}, perCompiler: map[id.ID]float64{}}
+}
+
+// Assign records, in p, iteration parameters for each subject-compiler pairing that aim to fit within budget.
+func (b *Budgeter) Assign(p *plan.Plan, budget time.Duration) {
+	for name, s := range p.Corpus {
+		for cid := range p.Compilers {
+			it := b.Iterations(name, cid, budget)
+			s.SetIterations(cid, it)
+		}
+		p.Corpus[name] = s
+	}
+}
+
+// Iterations chooses iteration parameters for subject sname on compiler cid that aim to fit within budget.
+//
+// If we know nothing about the pairing's timing, nor that of any subject on the same compiler, we use
+// DefaultIterations.
+func (b *Budgeter) Iterations(sname string, cid id.ID, budget time.Duration) compilation.Iterations {
+	k := budgetKey{subject: sname, compiler: cid}
+	it := DefaultIterations
+	est, ok := b.perSubject[k]
+	if !ok {
+		est, ok = b.perCompiler[k.compiler]
+	}
+	if !ok || est <= 0 {
+		return it
+	}
+
+	n := int(budget.Seconds() / est)
+	if n < it.Size {
+		// Even one batch of the usual size would overrun, so shrink the batch instead.
+		if n < 1 {
+			n = 1
+		}
+		return compilation.Iterations{Size: n, Count: 1}
+	}
+	it.Count = n / it.Size
+	if MaxCount < it.Count {
+		it.Count = MaxCount
+	}
+	return it
+}
+
+// Learn updates the budgeter's timing estimates using the run results in p.
+func (b *Budgeter) Learn(p *plan.Plan) {
+	sums := map[id.ID]float64{}
+	counts := map[id.ID]int{}
+
+	for name, s := range p.Corpus {
+		for cid, cc := range s.Compilations {
+			est, ok := PerIteration(cc)
+			if !ok {
+				continue
+			}
+			b.perSubject[budgetKey{subject: name, compiler: cid}] = est
+			sums[cid] += est
+			counts[cid]++
+		}
+	}
+
+	for cid, sum := range sums {
+		b.perCompiler[cid] = sum / float64(counts[cid])
+	}
+}
+
+// PerIteration works out the time, in seconds, that each iteration of cc's run took, if cc has enough information.
+func PerIteration(cc compilation.Compilation) (float64, bool) {
+	if cc.Iterations == nil || cc.Run == nil || !cc.Run.Status.CountsForTiming() {
+		return 0, false
+	}
+	total := cc.Iterations.Total()
+	if 1 < cc.Run.Runs {
+		total *= cc.Run.Runs
+	}
+	if total <= 0 {
+		return 0, false
+	}
+	return cc.Run.Timespan.Duration().Seconds() / float64(total), true
+}
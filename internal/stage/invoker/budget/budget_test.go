@@ -0,0 +1,151 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package budget_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/stage/invoker/budget"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/c4-project/c4t/internal/subject/status"
+	"github.com/c4-project/c4t/internal/timing"
+)
+
+var (
+	gcc   = id.FromString("gcc")
+	clang = id.FromString("clang")
+)
+
+// timedCompilation makes a compilation that ran the iterations it, runs times, in d with status s.
+func timedCompilation(it compilation.Iterations, runs int, d time.Duration, s status.Status) compilation.Compilation {
+	return compilation.Compilation{
+		Iterations: &it,
+		Run: &compilation.RunResult{
+			Result: compilation.Result{Status: s, Timespan: timing.SpanFromDuration(timing.MockDate, d)},
+			Runs:   runs,
+		},
+	}
+}
+
+// timedPlan makes a plan in which, on gcc, 'foo' ran 10,000 iterations in 1s and 'bar' ran 10,000 in 3s.
+func timedPlan() *plan.Plan {
+	it := compilation.Iterations{Size: 1000, Count: 10}
+	return &plan.Plan{
+		Compilers: compiler.InstanceMap{gcc: {}},
+		Corpus: corpus.Corpus{
+			"foo": subject.Subject{Compilations: compilation.Map{gcc: timedCompilation(it, 0, time.Second, status.Ok)}},
+			"bar": subject.Subject{Compilations: compilation.Map{gcc: timedCompilation(it, 0, 3*time.Second, status.Flagged)}},
+		},
+	}
+}
+
+// TestBudgeter_Iterations tests Budgeter.Iterations on a budgeter that has learned timedPlan.
+func TestBudgeter_Iterations(t *testing.T) {
+	t.Parallel()
+
+	b := budget.New()
+	b.Learn(timedPlan())
+
+	cases := map[string]struct {
+		sname    string
+		cid      id.ID
+		budget   time.Duration
+		expected compilation.Iterations
+	}{
+		"learned-exact":    {sname: "foo", cid: gcc, budget: 10 * time.Second, expected: compilation.Iterations{Size: 100000, Count: 1}},
+		"learned-batches":  {sname: "foo", cid: gcc, budget: 50 * time.Second, expected: compilation.Iterations{Size: 100000, Count: 5}},
+		"learned-slow":     {sname: "bar", cid: gcc, budget: 60 * time.Second, expected: compilation.Iterations{Size: 100000, Count: 2}},
+		"shrunk-batch":     {sname: "foo", cid: gcc, budget: time.Second, expected: compilation.Iterations{Size: 10000, Count: 1}},
+		"clamped-min":      {sname: "foo", cid: gcc, budget: time.Nanosecond, expected: compilation.Iterations{Size: 1, Count: 1}},
+		"clamped-max":      {sname: "foo", cid: gcc, budget: 1000 * time.Hour, expected: compilation.Iterations{Size: 100000, Count: budget.MaxCount}},
+		"compiler-mean":    {sname: "baz", cid: gcc, budget: 20 * time.Second, expected: compilation.Iterations{Size: 100000, Count: 1}},
+		"unknown-compiler": {sname: "foo", cid: clang, budget: time.Second, expected: budget.DefaultIterations},
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, c.expected, b.Iterations(c.sname, c.cid, c.budget))
+		})
+	}
+}
+
+// TestBudgeter_Iterations_noHistory tests that a budgeter that hasn't learned anything uses the default iterations.
+func TestBudgeter_Iterations_noHistory(t *testing.T) {
+	t.Parallel()
+
+	b := budget.New()
+	// Learning from a plan with no usable timings shouldn't change this.
+	b.Learn(&plan.Plan{Corpus: corpus.Corpus{
+		"foo": subject.Subject{Compilations: compilation.Map{
+			gcc: timedCompilation(compilation.Iterations{Size: 1000, Count: 10}, 0, time.Second, status.RunTimeout),
+		}},
+	}})
+
+	for _, d := range []time.Duration{time.Nanosecond, time.Second, time.Hour} {
+		assert.Equal(t, budget.DefaultIterations, b.Iterations("foo", gcc, d), "budget %s", d)
+	}
+}
+
+// TestBudgeter_Assign tests that Budgeter.Assign sets iterations for every subject-compiler pairing in a plan.
+func TestBudgeter_Assign(t *testing.T) {
+	t.Parallel()
+
+	b := budget.New()
+	b.Learn(timedPlan())
+
+	p := timedPlan()
+	p.Compilers[clang] = compiler.Instance{}
+	b.Assign(p, 10*time.Second)
+
+	for sname, s := range p.Corpus {
+		for cid := range p.Compilers {
+			cc, err := s.Compilation(cid)
+			require.NoError(t, err, "no compilation for %s/%s", sname, cid)
+			require.NotNil(t, cc.Iterations, "no iterations for %s/%s", sname, cid)
+			assert.Equal(t, b.Iterations(sname, cid, 10*time.Second), *cc.Iterations, "iterations for %s/%s", sname, cid)
+		}
+	}
+}
+
+// TestPerIteration tests PerIteration on various compilations.
+func TestPerIteration(t *testing.T) {
+	t.Parallel()
+
+	it := compilation.Iterations{Size: 1000, Count: 10}
+	cases := map[string]struct {
+		in     compilation.Compilation
+		ok     bool
+		expect float64
+	}{
+		"no-iterations": {in: compilation.Compilation{Run: &compilation.RunResult{}}},
+		"no-run":        {in: compilation.Compilation{Iterations: &it}},
+		"timeout":       {in: timedCompilation(it, 0, time.Second, status.RunTimeout)},
+		"failed":        {in: timedCompilation(it, 0, time.Second, status.RunFail)},
+		"no-total":      {in: timedCompilation(compilation.Iterations{}, 0, time.Second, status.Ok)},
+		"ok":            {in: timedCompilation(it, 0, time.Second, status.Ok), ok: true, expect: 0.0001},
+		"flagged":       {in: timedCompilation(it, 0, 2*time.Second, status.Flagged), ok: true, expect: 0.0002},
+		"repeated":      {in: timedCompilation(it, 4, 2*time.Second, status.Ok), ok: true, expect: 0.00005},
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := budget.PerIteration(c.in)
+			require.Equal(t, c.ok, ok, "ok")
+			assert.InDelta(t, c.expect, got, 1e-12)
+		})
+	}
+}
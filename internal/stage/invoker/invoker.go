@@ -11,6 +11,7 @@ import (
 	"github.com/c4-project/c4t/internal/copier"
 	"github.com/c4-project/c4t/internal/plan/stage"
 	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/stage/invoker/budget"
 	"github.com/c4-project/c4t/internal/stage/invoker/runner"
 	"github.com/c4-project/c4t/internal/stage/mach/observer"
 )
@@ -37,6 +38,8 @@ type Invoker struct {
 	rfac runner.Factory
 	// allowReinvoke permits re-invokation on plans that already have a reinvoke stage.
 	allowReinvoke bool
+	// budget learns harness timings across invocations, to choose iteration parameters for runs.
+	budget *budget.Budgeter
 }
 
 // New constructs a new Invoker with local directory ldir, runner factory fac, and options o.
@@ -45,7 +48,7 @@ func New(ldir string, fac runner.Factory, o ...Option) (*Invoker, error) {
 		return nil, ErrDirEmpty
 	}
 
-	invoker := Invoker{ldir: ldir, rfac: fac, pqo: NopPlanQuantityOverrider{}}
+	invoker := Invoker{ldir: ldir, rfac: fac, pqo: NopPlanQuantityOverrider{}, budget: budget.New()}
 	if err := Options(o...)(&invoker); err != nil {
 		return nil, err
 	}
@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/c4-project/c4t/internal/helper/errhelp"
 	"github.com/c4-project/c4t/internal/stage/invoker/runner"
@@ -31,6 +32,13 @@ func (m *Invoker) Run(ctx context.Context, p *plan.Plan) (*plan.Plan, error) {
 	if err := m.checkPlan(p); err != nil {
 		return nil, err
 	}
+	qs, err := m.calcQuantities(p)
+	if err != nil {
+		return nil, err
+	}
+	if qs.Runner.Budget.IsActive() {
+		m.budget.Assign(p, time.Duration(qs.Runner.Budget))
+	}
 	run, err := m.rfac.MakeRunner(m.ldir, p, m.copyObservers...)
 	if err != nil {
 		return nil, fmt.Errorf("while spawning runner: %w", err)
@@ -39,10 +47,6 @@ func (m *Invoker) Run(ctx context.Context, p *plan.Plan) (*plan.Plan, error) {
 	if err != nil {
 		return nil, fmt.Errorf("while copying files to machine: %w", err)
 	}
-	qs, err := m.calcQuantities(p)
-	if err != nil {
-		return nil, err
-	}
 	ps, err := run.Start(ctx, qs)
 	if err != nil {
 		return nil, fmt.Errorf("while starting command: %w", err)
@@ -51,7 +55,15 @@ func (m *Invoker) Run(ctx context.Context, p *plan.Plan) (*plan.Plan, error) {
 	if err != nil {
 		return nil, err
 	}
-	return run.Recv(ctx, p, np)
+	return m.learn(run.Recv(ctx, p, np))
+}
+
+// learn feeds the run timings in p, if any, back into the invoker's budgeter.
+func (m *Invoker) learn(p *plan.Plan, err error) (*plan.Plan, error) {
+	if err == nil {
+		m.budget.Learn(p)
+	}
+	return p, err
 }
 
 func (m *Invoker) awaitResults(ctx context.Context, rp *plan.Plan, ps *remote.Pipeset, runner runner.Runner) (*plan.Plan, error) {
@@ -0,0 +1,60 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+//go:build linux
+
+package runner
+
+import (
+	"os/exec"
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// startPinned starts cmd with its CPU affinity restricted to cpus.
+//
+// Child processes inherit the affinity mask of the thread that forks them, so we start cmd from a goroutine locked to
+// a thread whose mask we've restricted.
+// Once cmd has started, we restore the thread's original mask and unlock it.
+// If we can't restore the mask, we leave the thread locked, so the Go runtime discards it when the goroutine exits
+// rather than reusing a thread with a restricted mask.
+func startPinned(cmd *exec.Cmd, cpus []int) error {
+	if len(cpus) == 0 {
+		return cmd.Start()
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		errCh <- startOnPinnedThread(cmd, cpus)
+	}()
+	return <-errCh
+}
+
+// startOnPinnedThread starts cmd with the current, locked, thread's affinity restricted to cpus.
+func startOnPinnedThread(cmd *exec.Cmd, cpus []int) error {
+	var orig unix.CPUSet
+	if err := unix.SchedGetaffinity(0, &orig); err != nil {
+		runtime.UnlockOSThread()
+		return err
+	}
+
+	var set unix.CPUSet
+	set.Zero()
+	for _, c := range cpus {
+		set.Set(c)
+	}
+	if err := unix.SchedSetaffinity(0, &set); err != nil {
+		// The mask hasn't changed, so the thread is safe to reuse.
+		runtime.UnlockOSThread()
+		return err
+	}
+	err := cmd.Start()
+	if rerr := unix.SchedSetaffinity(0, &orig); rerr == nil {
+		runtime.UnlockOSThread()
+	}
+	return err
+}
@@ -0,0 +1,15 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+//go:build !linux
+
+package runner
+
+import "os/exec"
+
+// startPinned starts cmd; CPU affinity isn't supported on this platform, so cpus only limits concurrency.
+func startPinned(cmd *exec.Cmd, _ []int) error {
+	return cmd.Start()
+}
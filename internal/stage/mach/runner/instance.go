@@ -7,6 +7,7 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os/exec"
 	"time"
@@ -27,13 +28,15 @@ import (
 
 	"github.com/c4-project/c4t/internal/subject/obs"
 
+	"github.com/c4-project/c4t/internal/stage/mach/runner/pinner"
+
 	"github.com/c4-project/c4t/internal/subject"
 )
 
 // Instance contains all state required to perform a runner operation for a given subject.
 type Instance struct {
 	// backend is the backend used to produce the recipes being run.
-	backend backend.ObsParser
+	backend backend.Backend
 
 	// resCh is the channel to which we're sending the run result.
 	resCh chan<- builder.Request
@@ -43,31 +46,34 @@ type Instance struct {
 
 	// quantities is the set of quantities used to parametrise the running job.
 	quantities quantity.BatchSet
+
+	// pinner, if non-nil, allocates CPUs to which each run of this subject is pinned.
+	pinner *pinner.Pinner
 }
 
 // Run runs the instance with context ctx.
 func (n *Instance) Run(ctx context.Context) error {
 	for cid, cc := range n.subject.Compilations {
 		name := compilation.Name{CompilerID: cid, SubjectName: n.subject.Name}
-		if err := n.runCompile(ctx, name, cc.Compile); err != nil {
+		if err := n.runCompile(ctx, name, cc); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (n *Instance) runCompile(ctx context.Context, name compilation.Name, c *compilation.CompileResult) error {
-	if c == nil {
+func (n *Instance) runCompile(ctx context.Context, name compilation.Name, cc compilation.Compilation) error {
+	if cc.Compile == nil {
 		return fmt.Errorf("%w: %s", subject.ErrMissingCompile, name)
 	}
-	run, err := n.runCompileInner(ctx, name, c)
+	run, err := n.runCompileInner(ctx, name, cc.Compile, n.iterationArgs(cc.Iterations))
 	if err != nil {
 		return err
 	}
 	return builder.RunRequest(name, run).SendTo(ctx, n.resCh)
 }
 
-func (n *Instance) runCompileInner(ctx context.Context, name compilation.Name, c *compilation.CompileResult) (compilation.RunResult, error) {
+func (n *Instance) runCompileInner(ctx context.Context, name compilation.Name, c *compilation.CompileResult, args []string) (compilation.RunResult, error) {
 	if !c.Status.IsOk() {
 		return compilation.RunResult{Result: compilation.Result{Status: c.Status}}, nil
 	}
@@ -79,20 +85,57 @@ func (n *Instance) runCompileInner(ctx context.Context, name compilation.Name, c
 		}, fmt.Errorf("%w: %s", ErrNoBin, name)
 	}
 
+	cpus, err := n.acquireCPUs(ctx)
+	if err != nil {
+		return compilation.RunResult{}, err
+	}
+	defer n.releaseCPUs(cpus)
+
 	start := time.Now()
-	o, runErr := n.runAndParseBin(ctx, name, bin)
-	s, err := statusOfRun(o, runErr)
+	r, err := n.runRepeatedly(ctx, name, bin, args, cpus)
+	r.Timespan = timing.SpanSince(start)
+	return r, err
+}
 
-	return n.makeResult(start, s, o), err
+// runRepeatedly runs bin the configured number of times, aggregating the observations of each run.
+//
+// If any run fails or times out, we stop there, and the result takes on that run's status.  Any observation from that
+// run counts as partial, and so doesn't affect the merged verdict, flakiness, or state frequencies.
+func (n *Instance) runRepeatedly(ctx context.Context, name compilation.Name, bin string, args []string, cpus []int) (compilation.RunResult, error) {
+	nruns := n.quantities.NRuns
+	if nruns <= 1 {
+		o, runErr := n.runAndParseBin(ctx, name, bin, args, cpus)
+		s, err := statusOfRun(o, runErr)
+		return compilation.RunResult{Result: compilation.Result{Status: s}, Obs: o}, err
+	}
+
+	obss := make([]obs.Obs, 0, nruns)
+	for i := 1; i <= nruns; i++ {
+		o, runErr := n.runAndParseBin(ctx, name, bin, args, cpus)
+		if o != nil {
+			if runErr != nil {
+				o.Flags |= obs.Partial
+			}
+			obss = append(obss, *o)
+		}
+		if runErr != nil {
+			s, err := status.FromRunError(runErr)
+			return makeRepeatedResult(s, i, obss), err
+		}
+	}
+	r := makeRepeatedResult(status.Ok, nruns, obss)
+	r.Status = r.Obs.Status()
+	return r, nil
 }
 
-func (n *Instance) makeResult(start time.Time, s status.Status, o *obs.Obs) compilation.RunResult {
+// makeRepeatedResult makes a run result with status s, after runs attempted runs, by merging the observations obss.
+func makeRepeatedResult(s status.Status, runs int, obss []obs.Obs) compilation.RunResult {
+	o, flaky := obs.Merge(obss...)
 	return compilation.RunResult{
-		Result: compilation.Result{
-			Timespan: timing.SpanSince(start),
-			Status:   s,
-		},
-		Obs: o,
+		Result: compilation.Result{Status: s},
+		Obs:    &o,
+		Runs:   runs,
+		Flaky:  flaky,
 	}
 }
 
@@ -103,23 +146,59 @@ func statusOfRun(o *obs.Obs, runErr error) (status.Status, error) {
 	return o.Status(), nil
 }
 
-// runAndParseBin runs the binary at bin and parses its result into an observation struct.
-func (n *Instance) runAndParseBin(ctx context.Context, name compilation.Name, bin string) (*obs.Obs, error) {
+// acquireCPUs allocates CPUs for a run of this instance's subject, if pinning is enabled.
+func (n *Instance) acquireCPUs(ctx context.Context) ([]int, error) {
+	if n.pinner == nil {
+		return nil, nil
+	}
+	return n.pinner.Acquire(ctx, threadsOf(&n.subject.Subject))
+}
+
+// releaseCPUs frees CPUs allocated by acquireCPUs.
+func (n *Instance) releaseCPUs(cpus []int) {
+	if n.pinner != nil {
+		n.pinner.Release(cpus)
+	}
+}
+
+// iterationArgs gets the arguments, if any, that make the backend's binaries perform the iterations in it.
+func (n *Instance) iterationArgs(it *compilation.Iterations) []string {
+	ia, ok := n.backend.(backend.IterationArger)
+	if it == nil || !ok {
+		return nil
+	}
+	return ia.IterationArgs(*it)
+}
+
+// runAndParseBin runs the binary at bin, with arguments args, and parses its result into an observation struct.
+func (n *Instance) runAndParseBin(ctx context.Context, name compilation.Name, bin string, args []string, cpus []int) (*obs.Obs, error) {
 	tctx, cancel := n.quantities.Timeout.OnContext(ctx)
 	defer cancel()
 
-	cmd := exec.CommandContext(tctx, bin)
-	obsr, err := cmd.StdoutPipe()
+	lbin, largs, err := limitCommand(bin, args, n.quantities.Limits)
 	if err != nil {
-		return nil, n.liftError(name, "opening pipe for", err)
+		return nil, n.liftError(name, "limiting", err)
 	}
-	if err := cmd.Start(); err != nil {
+
+	var o obs.Obs
+	cmd := exec.CommandContext(tctx, lbin, largs...)
+	cmd.WaitDelay = waitDelay
+	// We parse using the outer context, so that a run timeout doesn't stop us collecting the partial observation.
+	str := n.streamObs(ctx, cmd, &o)
+	if err := startPinned(cmd, cpus); err != nil {
+		_ = str.finish()
 		return nil, n.liftError(name, "starting", err)
 	}
 
-	var o obs.Obs
-	perr := n.backend.ParseObs(tctx, obsr, &o)
 	werr := cmd.Wait()
+	perr := str.finish()
+	if werr != nil && exceededLimits(cmd.ProcessState, n.quantities.Limits) {
+		werr = fmt.Errorf("%w: %w", status.ErrRunLimit, werr)
+	}
+	if errors.Is(tctx.Err(), context.DeadlineExceeded) {
+		// Whatever we managed to parse before the timeout is still useful, but it is incomplete.
+		o.Flags |= obs.Partial
+	}
 
 	return &o, errhelp.TimeoutOrFirstError(tctx, werr, perr)
 }
@@ -135,3 +214,12 @@ func (n *Instance) liftError(name compilation.Name, stage string, err error) err
 		Inner:       err,
 	}
 }
+
+// threadsOf gets the number of threads in the best litmus test for s, or 0 if this is not known.
+func threadsOf(s *subject.Subject) int {
+	l, err := s.BestLitmus()
+	if err != nil || l.Stats == nil {
+		return 0
+	}
+	return l.Stats.Threads
+}
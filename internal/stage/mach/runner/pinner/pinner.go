@@ -0,0 +1,114 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package pinner hands out disjoint sets of CPUs to concurrently running test binaries.
+package pinner
+
+import (
+	"context"
+	"sync"
+)
+
+// Pinner hands out disjoint sets of CPUs to concurrently running binaries.
+//
+// Each acquisition blocks until enough CPUs are free, so that concurrent harnesses never contend for the same core.
+type Pinner struct {
+	// cpus is the pool of CPU IDs the pinner manages.
+	cpus []int
+
+	// mu guards the fields below.
+	mu sync.Mutex
+	// used records, for each CPU in cpus (by index), whether it is currently allocated.
+	used []bool
+	// wake is closed, and replaced, whenever CPUs are released.
+	wake chan struct{}
+}
+
+// New constructs a pinner over at most ncores of the CPUs on which this process is allowed to run.
+// If ncores is non-positive, or exceeds the number of such CPUs, we use all of them.
+func New(ncores int) *Pinner {
+	return NewOver(UsableCPUs(), ncores)
+}
+
+// NewOver constructs a pinner over at most ncores of the CPU IDs in cpus.
+// If ncores is non-positive, or exceeds the length of cpus, we use all of them.
+func NewOver(cpus []int, ncores int) *Pinner {
+	if ncores <= 0 || len(cpus) < ncores {
+		ncores = len(cpus)
+	}
+	pool := make([]int, ncores)
+	copy(pool, cpus)
+	return &Pinner{cpus: pool, used: make([]bool, ncores), wake: make(chan struct{})}
+}
+
+// Size gets the number of CPUs the pinner manages.
+func (p *Pinner) Size() int {
+	return len(p.cpus)
+}
+
+// Acquire blocks until n CPUs are free, then allocates and returns their IDs.
+// The number of CPUs is clamped to between 1 and the number of CPUs managed by the pinner.
+func (p *Pinner) Acquire(ctx context.Context, n int) ([]int, error) {
+	for {
+		cpus, wake := p.tryAcquire(n)
+		if cpus != nil {
+			return cpus, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-wake:
+		}
+	}
+}
+
+func (p *Pinner) tryAcquire(n int) ([]int, <-chan struct{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	n = clampCPUs(n, len(p.used))
+	idxs := make([]int, 0, n)
+	for i, u := range p.used {
+		if !u {
+			idxs = append(idxs, i)
+		}
+		if len(idxs) == n {
+			cpus := make([]int, n)
+			for j, i := range idxs {
+				p.used[i] = true
+				cpus[j] = p.cpus[i]
+			}
+			return cpus, nil
+		}
+	}
+	return nil, p.wake
+}
+
+// Release frees each CPU in cpus, waking any waiting acquisitions.
+// CPUs not managed by the pinner are ignored.
+func (p *Pinner) Release(cpus []int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, c := range cpus {
+		for i, pc := range p.cpus {
+			if pc == c {
+				p.used[i] = false
+			}
+		}
+	}
+	close(p.wake)
+	p.wake = make(chan struct{})
+}
+
+func clampCPUs(n, max int) int {
+	if n < 1 {
+		return 1
+	}
+	if max < n {
+		return max
+	}
+	return n
+}
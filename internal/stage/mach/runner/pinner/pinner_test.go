@@ -0,0 +1,153 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package pinner_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/stage/mach/runner/pinner"
+)
+
+// ExampleNewOver is a runnable example for NewOver.
+func ExampleNewOver() {
+	// A process restricted to CPUs 2, 5, and 7, pinning onto at most two of them.
+	p := pinner.NewOver([]int{2, 5, 7}, 2)
+	cpus, _ := p.Acquire(context.Background(), 2)
+	fmt.Println(p.Size(), cpus)
+
+	// Output:
+	// 2 [2 5]
+}
+
+// TestNewOver_size tests that NewOver clamps its pool to the available CPUs.
+func TestNewOver_size(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		ncores, want int
+	}{
+		"zero":     {ncores: 0, want: 4},
+		"negative": {ncores: -1, want: 4},
+		"fewer":    {ncores: 3, want: 3},
+		"exact":    {ncores: 4, want: 4},
+		"more":     {ncores: 8, want: 4},
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, c.want, pinner.NewOver([]int{0, 1, 2, 3}, c.ncores).Size())
+		})
+	}
+}
+
+// TestNew_usable tests that New only pins onto CPUs this process can use.
+func TestNew_usable(t *testing.T) {
+	t.Parallel()
+
+	usable := pinner.UsableCPUs()
+	require.NotEmpty(t, usable, "no usable CPUs")
+
+	p := pinner.New(0)
+	cpus, err := p.Acquire(context.Background(), p.Size())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, usable, cpus)
+}
+
+// TestPinner_Acquire_clamp tests that Acquire clamps the number of CPUs it allocates.
+func TestPinner_Acquire_clamp(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		n    int
+		want []int
+	}{
+		"zero":     {n: 0, want: []int{10}},
+		"negative": {n: -2, want: []int{10}},
+		"some":     {n: 2, want: []int{10, 12}},
+		"too-many": {n: 5, want: []int{10, 12, 14}},
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := pinner.NewOver([]int{10, 12, 14}, 0).Acquire(context.Background(), c.n)
+			require.NoError(t, err)
+			assert.Equal(t, c.want, got)
+		})
+	}
+}
+
+// TestPinner_Acquire_disjoint tests that concurrent acquisitions get disjoint CPUs, and that released CPUs are reused.
+func TestPinner_Acquire_disjoint(t *testing.T) {
+	t.Parallel()
+
+	p := pinner.NewOver([]int{3, 4, 5, 6}, 0)
+	ctx := context.Background()
+
+	a, err := p.Acquire(ctx, 2)
+	require.NoError(t, err)
+	b, err := p.Acquire(ctx, 2)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int{3, 4, 5, 6}, append(append([]int{}, a...), b...))
+
+	p.Release(a)
+	c, err := p.Acquire(ctx, 2)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, a, c)
+}
+
+// TestPinner_Acquire_blocks tests that Acquire waits until enough CPUs are released.
+func TestPinner_Acquire_blocks(t *testing.T) {
+	t.Parallel()
+
+	p := pinner.NewOver([]int{0, 1}, 0)
+	ctx := context.Background()
+
+	a, err := p.Acquire(ctx, 1)
+	require.NoError(t, err)
+
+	got := make(chan []int)
+	go func() {
+		cpus, err := p.Acquire(ctx, 2)
+		assert.NoError(t, err)
+		got <- cpus
+	}()
+
+	select {
+	case cpus := <-got:
+		t.Fatalf("acquired %v while CPUs were still in use", cpus)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	p.Release(a)
+	select {
+	case cpus := <-got:
+		assert.Equal(t, []int{0, 1}, cpus)
+	case <-time.After(5 * time.Second):
+		t.Fatal("acquisition didn't wake after release")
+	}
+}
+
+// TestPinner_Acquire_cancel tests that a blocked Acquire gives up when its context is cancelled.
+func TestPinner_Acquire_cancel(t *testing.T) {
+	t.Parallel()
+
+	p := pinner.NewOver([]int{0}, 0)
+	_, err := p.Acquire(context.Background(), 1)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = p.Acquire(ctx, 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
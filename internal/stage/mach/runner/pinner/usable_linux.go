@@ -0,0 +1,32 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+//go:build linux
+
+package pinner
+
+import (
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// UsableCPUs gets the IDs of the CPUs in this process's affinity mask, in ascending order.
+//
+// These needn't be 0 to runtime.NumCPU()-1: under taskset, cgroups, or container CPU sets, the process may only be
+// allowed to run on an arbitrary subset of the machine's CPUs.
+func UsableCPUs() []int {
+	var set unix.CPUSet
+	if err := unix.SchedGetaffinity(0, &set); err != nil || set.Count() == 0 {
+		return firstCPUs(runtime.NumCPU())
+	}
+	cpus := make([]int, 0, set.Count())
+	for c := 0; len(cpus) < set.Count(); c++ {
+		if set.IsSet(c) {
+			cpus = append(cpus, c)
+		}
+	}
+	return cpus
+}
@@ -0,0 +1,15 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+//go:build !linux
+
+package pinner
+
+import "runtime"
+
+// UsableCPUs gets the IDs of the CPUs visible to this process; on this platform, we assume these are numbered from 0.
+func UsableCPUs() []int {
+	return firstCPUs(runtime.NumCPU())
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+//go:build linux
+
+package runner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/c4-project/c4t/internal/quantity"
+)
+
+// limitBin is the program through which we run binaries to apply resource limits.
+const limitBin = "prlimit"
+
+// limitCommand gets the program and arguments that run bin, with arguments args, under the resource limits in lim.
+//
+// Go can't set rlimits between fork and exec, and setting them on the started process would let it run unlimited
+// until then.
+// Instead, we run the binary through util-linux's prlimit(1), which sets the limits on itself and then execs the
+// binary in the same process; this keeps the binary's exit status, and lets us kill it directly.
+func limitCommand(bin string, args []string, lim quantity.LimitSet) (string, []string, error) {
+	if !lim.IsActive() {
+		return bin, args, nil
+	}
+	lbin, err := exec.LookPath(limitBin)
+	if err != nil {
+		return "", nil, fmt.Errorf("resource limits need %s: %w", limitBin, err)
+	}
+	largs := append(limitArgs(lim), "--", bin)
+	return lbin, append(largs, args...), nil
+}
+
+// limitArgs gets the prlimit arguments for each active limit in lim.
+func limitArgs(lim quantity.LimitSet) []string {
+	var largs []string
+	if 0 < lim.AddressSpaceMiB {
+		largs = append(largs, limitArg("as", uint64(lim.AddressSpaceMiB)<<20, 0))
+	}
+	if lim.CPUTime.IsActive() {
+		// rlimits work in whole seconds, so we round up.
+		// We leave a second of headroom so that the binary gets SIGXCPU before SIGKILL.
+		largs = append(largs, limitArg("cpu", uint64((time.Duration(lim.CPUTime)+time.Second-1)/time.Second), 1))
+	}
+	if 0 < lim.OpenFiles {
+		largs = append(largs, limitArg("nofile", uint64(lim.OpenFiles), 0))
+	}
+	if 0 < lim.Processes {
+		largs = append(largs, limitArg("nproc", uint64(lim.Processes), 0))
+	}
+	return largs
+}
+
+// limitArg gets the prlimit argument setting the soft limit for res to soft, and its hard limit headroom above it.
+func limitArg(res string, soft, headroom uint64) string {
+	return "--" + res + "=" + strconv.FormatUint(soft, 10) + ":" + strconv.FormatUint(soft+headroom, 10)
+}
+
+// exceededLimits checks whether the process whose final state is ps was killed for exceeding the CPU time limit in lim.
+//
+// CPU time is the only limit whose breaches we can detect, as the kernel signals them: SIGXCPU at the soft limit, and
+// SIGKILL at the hard one.
+// Breaching the other limits just makes the offending system call fail (with ENOMEM, EMFILE, or EAGAIN), and the
+// binary reports that however it likes; those runs get the same status as any other failed run.
+func exceededLimits(ps *os.ProcessState, lim quantity.LimitSet) bool {
+	if ps == nil || !lim.CPUTime.IsActive() {
+		return false
+	}
+	ws, ok := ps.Sys().(syscall.WaitStatus)
+	if !ok || !ws.Signaled() {
+		return false
+	}
+	switch ws.Signal() {
+	case syscall.SIGXCPU:
+		return true
+	case syscall.SIGKILL:
+		// This is the hard CPU limit, unless something else killed the process first.
+		return time.Duration(lim.CPUTime) <= ps.UserTime()+ps.SystemTime()
+	default:
+		return false
+	}
+}
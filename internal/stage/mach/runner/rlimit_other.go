@@ -0,0 +1,24 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+//go:build !linux
+
+package runner
+
+import (
+	"os"
+
+	"github.com/c4-project/c4t/internal/quantity"
+)
+
+// limitCommand returns bin and args unchanged, as resource limits aren't supported on this platform.
+func limitCommand(bin string, args []string, _ quantity.LimitSet) (string, []string, error) {
+	return bin, args, nil
+}
+
+// exceededLimits always returns false, as resource limits aren't supported on this platform.
+func exceededLimits(*os.ProcessState, quantity.LimitSet) bool {
+	return false
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/c4-project/c4t/internal/quantity"
 	"github.com/c4-project/c4t/internal/stage/mach/observer"
+	"github.com/c4-project/c4t/internal/stage/mach/runner/pinner"
 
 	"github.com/c4-project/c4t/internal/plan/stage"
 
@@ -81,10 +82,11 @@ func (r *Runner) Run(ctx context.Context, p *plan.Plan) (*plan.Plan, error) {
 		return nil, err
 	}
 
+	pin := r.pinner(p)
 	bcfg := r.builderConfig(p)
 	c, err := builder.ParBuild(ctx, r.quantities.NWorkers, p.Corpus, bcfg,
 		func(ctx context.Context, named subject.Named, requests chan<- builder.Request) error {
-			return r.instance(requests, named, b).Run(ctx)
+			return r.instance(requests, named, b, pin).Run(ctx)
 		})
 	if err != nil {
 		return nil, err
@@ -116,10 +118,18 @@ func checkPlan(p *plan.Plan) error {
 	return p.Metadata.RequireStage(stage.Compile)
 }
 
-func (r *Runner) instance(requests chan<- builder.Request, named subject.Named, backend backend.ObsParser) *Instance {
+// pinner gets a CPU pinner sized to the machine targeted by p, or nil if pinning is disabled.
+func (r *Runner) pinner(p *plan.Plan) *pinner.Pinner {
+	if !r.quantities.PinCPUs {
+		return nil
+	}
+	return pinner.New(p.Machine.Cores)
+}
 
+func (r *Runner) instance(requests chan<- builder.Request, named subject.Named, backend backend.Backend, pin *pinner.Pinner) *Instance {
 	return &Instance{
 		backend:    backend,
+		pinner:     pin,
 		quantities: r.quantities,
 		resCh:      requests,
 		subject:    &named,
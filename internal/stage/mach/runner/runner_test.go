@@ -0,0 +1,168 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package runner_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/machine"
+	"github.com/c4-project/c4t/internal/model/litmus"
+	backend2 "github.com/c4-project/c4t/internal/model/service/backend"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/stage"
+	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/serviceimpl/backend"
+	"github.com/c4-project/c4t/internal/stage/mach/runner"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/c4-project/c4t/internal/subject/obs"
+	"github.com/c4-project/c4t/internal/subject/status"
+	"github.com/c4-project/c4t/internal/timing"
+)
+
+var testCompiler = id.FromString("gcc")
+
+// fakeBinPlan makes a compiled plan whose one subject's binary is a shell script with body script.
+func fakeBinPlan(t *testing.T, script string) *plan.Plan {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake binaries are shell scripts")
+	}
+
+	bin := filepath.Join(t.TempDir(), "a.out")
+	require.NoError(t, os.WriteFile(bin, []byte("#!/bin/sh\n"+script), 0o755), "writing fake binary")
+
+	s := subject.NewOrPanic(
+		litmus.NewOrPanic("foo.litmus", litmus.WithThreads(1)),
+		subject.WithCompile(testCompiler, compilation.CompileResult{
+			Result: compilation.Result{Status: status.Ok},
+			Files:  compilation.CompileFileset{Bin: bin},
+		}),
+	)
+
+	md := plan.NewMetadata(0)
+	md.ConfirmStage(stage.Compile, timing.Span{})
+	return &plan.Plan{
+		Metadata: *md,
+		Machine:  machine.Named{ID: id.FromString("localhost")},
+		Backend: &backend2.NamedSpec{
+			ID:   id.FromString("litmus"),
+			Spec: backend2.Spec{Style: id.FromString("herdtools.litmus")},
+		},
+		Compilers: compiler.InstanceMap{testCompiler: {}},
+		Corpus:    corpus.Corpus{"foo": *s},
+	}
+}
+
+// runFakeBin runs the plan p with quantities qs, and returns the run result of its one subject.
+func runFakeBin(t *testing.T, p *plan.Plan, qs quantity.BatchSet) *compilation.RunResult {
+	t.Helper()
+
+	r, err := runner.New(&backend.Resolve, runner.NewPathset(t.TempDir()), runner.OverrideQuantities(qs))
+	require.NoError(t, err, "constructing runner")
+	p, err = r.Run(context.Background(), p)
+	require.NoError(t, err, "running plan")
+
+	s := p.Corpus["foo"]
+	rr, err := s.RunResult(testCompiler)
+	require.NoError(t, err, "getting run result")
+	return rr
+}
+
+// TestRunner_Run_partialTimeout tests that a binary that times out after printing some states keeps those states.
+func TestRunner_Run_partialTimeout(t *testing.T) {
+	t.Parallel()
+
+	// 'exec' so that killing the binary on timeout closes its output straight away.
+	p := fakeBinPlan(t, `echo 'Test foo Allowed'
+echo 'Histogram (2 states)'
+echo '5     :>0:r0=0; x=1;'
+exec sleep 100
+`)
+	rr := runFakeBin(t, p, quantity.BatchSet{Timeout: quantity.Timeout(500 * time.Millisecond), NWorkers: 1})
+
+	assert.Equal(t, status.RunTimeout, rr.Status, "status")
+	require.NotNil(t, rr.Obs, "no partial observation")
+	assert.True(t, rr.Obs.Flags.Has(obs.Partial), "observation should be marked partial")
+	require.Len(t, rr.Obs.States, 1, "should keep the state printed before the timeout")
+	assert.Equal(t, obs.Valuation{"0:r0": "0", "x": "1"}, rr.Obs.States[0].Values)
+}
+
+// TestRunner_Run_silentTimeout tests the timeout behaviour of binaries that, like litmus7 harnesses, print nothing
+// until they finish: the result is an empty observation, marked partial.
+func TestRunner_Run_silentTimeout(t *testing.T) {
+	t.Parallel()
+
+	p := fakeBinPlan(t, "exec sleep 100\n")
+	rr := runFakeBin(t, p, quantity.BatchSet{Timeout: quantity.Timeout(500 * time.Millisecond), NWorkers: 1})
+
+	assert.Equal(t, status.RunTimeout, rr.Status, "status")
+	require.NotNil(t, rr.Obs, "no partial observation")
+	assert.True(t, rr.Obs.Flags.Has(obs.Partial), "observation should be marked partial")
+	assert.Empty(t, rr.Obs.States, "silent binary shouldn't produce states")
+}
+
+// TestRunner_Run_repeatedFail tests that, when one of several runs fails, the result counts every attempted run.
+func TestRunner_Run_repeatedFail(t *testing.T) {
+	t.Parallel()
+
+	// The binary passes twice, then fails partway through, keeping count in a file next to itself.
+	p := fakeBinPlan(t, `count="$0.count"
+n=$(( $(cat "$count" 2>/dev/null || echo 0) + 1 ))
+echo "$n" > "$count"
+echo 'Test foo Allowed'
+echo 'Histogram (1 states)'
+echo '5     :>0:r0=0; x=1;'
+[ "$n" -ge 3 ] && exit 1
+echo 'No'
+echo 'Witnesses'
+echo 'Positive: 0, Negative: 5'
+echo 'Condition exists (0:r0=1) is NOT validated'
+echo 'Observation foo Never 0 5'
+`)
+	rr := runFakeBin(t, p, quantity.BatchSet{Timeout: quantity.Timeout(10 * time.Second), NWorkers: 1, NRuns: 5})
+
+	assert.Equal(t, status.RunFail, rr.Status, "status")
+	assert.Equal(t, 3, rr.Runs, "runs should count the failed attempt")
+	assert.False(t, rr.Flaky, "the failed attempt shouldn't make the verdict flaky")
+	require.NotNil(t, rr.Obs, "observation")
+	assert.True(t, rr.Obs.Flags.IsUnsat(), "verdict should come from the complete runs")
+	require.Len(t, rr.Obs.States, 1, "states")
+	assert.Equal(t, 1.0, rr.Obs.States[0].Frequency, "frequency should count only the complete runs")
+	assert.Equal(t, uint64(15), rr.Obs.States[0].Occurrences, "occurrences should include the failed attempt")
+	assert.True(t, rr.Obs.Flags.IsPartial(), "the failed attempt should make the observation partial")
+}
+
+// TestRunner_Run_cpuLimit tests that a binary that exceeds its CPU time limit gets the RunLimit status.
+func TestRunner_Run_cpuLimit(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS != "linux" {
+		t.Skip("resource limits are Linux only")
+	}
+	if _, err := exec.LookPath("prlimit"); err != nil {
+		t.Skip("resource limits need prlimit")
+	}
+
+	p := fakeBinPlan(t, "while :; do :; done\n")
+	rr := runFakeBin(t, p, quantity.BatchSet{
+		Timeout:  quantity.Timeout(30 * time.Second),
+		NWorkers: 1,
+		Limits:   quantity.LimitSet{CPUTime: quantity.Timeout(time.Second)},
+	})
+	assert.Equal(t, status.RunLimit, rr.Status, "status")
+}
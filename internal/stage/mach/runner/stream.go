@@ -0,0 +1,53 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package runner
+
+import (
+	"context"
+	"io"
+	"os/exec"
+	"time"
+
+	"github.com/c4-project/c4t/internal/subject/obs"
+)
+
+// waitDelay is the time we wait, after a binary exits or is killed, for anything it left running to close its output.
+const waitDelay = 5 * time.Second
+
+// obsStream parses a running binary's observation while the binary is still producing it.
+type obsStream struct {
+	// pw is the pipe into which the binary's standard output is copied.
+	pw *io.PipeWriter
+	// errc receives the parser's error once parsing finishes.
+	errc chan error
+}
+
+// streamObs sets up cmd so that its standard output is parsed into o as it arrives.
+//
+// This only salvages states from binaries that print them as they go.
+// litmus7 harnesses print their whole histogram when they exit, so a harness killed on timeout leaves o empty
+// (but still marked partial).
+//
+// The caller must call finish on the returned stream once cmd has been waited on (or has failed to start).
+func (n *Instance) streamObs(ctx context.Context, cmd *exec.Cmd, o *obs.Obs) *obsStream {
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+
+	s := obsStream{pw: pw, errc: make(chan error, 1)}
+	go func() {
+		err := n.backend.ParseObs(ctx, pr, o)
+		// Drain anything the parser didn't consume, so that the binary never blocks writing to the pipe.
+		_, _ = io.Copy(io.Discard, pr)
+		s.errc <- err
+	}()
+	return &s
+}
+
+// finish signals the end of the binary's output to the parser, and returns the parser's error.
+func (s *obsStream) finish() error {
+	_ = s.pw.Close()
+	return <-s.errc
+}
@@ -55,10 +55,15 @@ func (p *Perturber) perturbCompilers(rng *rand.Rand, pn *plan.Plan) error {
 func (c *compilerPerturber) Perturb(cfgs compiler.InstanceMap) (compiler.InstanceMap, error) {
 	compiler.OnCompilerConfigStart(len(cfgs), c.observers...)
 
+	// Iterating in ID order, rather than map order, keeps the perturbation reproducible from the plan seed.
+	ids, err := id.MapKeys(cfgs)
+	if err != nil {
+		return nil, err
+	}
+
 	ncfgs := make(compiler.InstanceMap, len(cfgs))
-	i := 0
-	for n, cfg := range cfgs {
-		nc, err := c.perturbCompiler(n, cfg.Compiler)
+	for i, n := range ids {
+		nc, err := c.perturbCompiler(n, cfgs[n].Compiler)
 		if err != nil {
 			return nil, err
 		}
@@ -68,7 +73,6 @@ func (c *compilerPerturber) Perturb(cfgs compiler.InstanceMap) (compiler.Instanc
 		}
 		ncfgs[nid] = nc.Instance
 		compiler.OnCompilerConfigStep(i, *nc, c.observers...)
-		i++
 	}
 
 	compiler.OnCompilerConfigEnd(c.observers...)
@@ -8,6 +8,7 @@ package perturber
 import (
 	"math/rand"
 
+	"github.com/c4-project/c4t/internal/quantity"
 	"github.com/c4-project/c4t/internal/subject/corpus"
 	"github.com/c4-project/c4t/internal/subject/corpus/builder"
 
@@ -15,7 +16,7 @@ import (
 )
 
 func (p *Perturber) sampleCorpus(rng *rand.Rand, pn *plan.Plan) error {
-	nc, err := pn.Corpus.Sample(rng, p.quantities.CorpusSize)
+	nc, err := p.sample(rng, pn.Corpus)
 	if err != nil {
 		return err
 	}
@@ -26,6 +27,29 @@ func (p *Perturber) sampleCorpus(rng *rand.Rand, pn *plan.Plan) error {
 	return nil
 }
 
+func (p *Perturber) sample(rng *rand.Rand, c corpus.Corpus) (corpus.Corpus, error) {
+	if len(p.quantities.StratifyBy) != 0 {
+		return p.stratifiedSample(rng, c, p.quantities.CorpusSize)
+	}
+	return sampleWith(rng, c, p.quantities.CorpusSize, p.weight(c))
+}
+
+// weight gets the weighting function for sampling c, or nil if sampling is uniform.
+func (p *Perturber) weight(c corpus.Corpus) func(string) float64 {
+	if p.quantities.Weighting == quantity.WeightUniform {
+		return nil
+	}
+	return NewWeigher(c, p.quantities.Weighting, p.yields).Weight
+}
+
+// sampleWith samples want subjects from c, weighting them by weight if it is non-nil.
+func sampleWith(rng *rand.Rand, c corpus.Corpus, want int, weight func(string) float64) (corpus.Corpus, error) {
+	if weight == nil {
+		return c.Sample(rng, want)
+	}
+	return c.WeightedSample(rng, want, weight)
+}
+
 func (p *Perturber) announceCorpus(c corpus.Corpus) {
 	// TODO(@MattWindsor91): the fact that we're reusing the builder observations here is sus.
 	obs := lowerToBuilder(p.observers)
@@ -61,3 +61,12 @@ func UseFullCompilerIDs(use bool) Option {
 		return nil
 	}
 }
+
+// UseYields sets the source of historical subject yields used when the corpus weighting takes yield into account.
+// If ys is nil, every subject is taken to be untried.
+func UseYields(ys YieldSource) Option {
+	return func(p *Perturber) error {
+		p.yields = ys
+		return nil
+	}
+}
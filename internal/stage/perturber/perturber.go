@@ -32,7 +32,9 @@ type Perturber struct {
 	useFullIDs bool
 	// quantities contains quantity information for this planner.
 	quantities quantity.PerturbSet
-	seed       int64
+	// yields, if non-nil, is the source of historical subject yields used in yield-weighted corpus sampling.
+	yields YieldSource
+	seed   int64
 }
 
 // New constructs a new perturber with the given compiler inspector and options.
@@ -0,0 +1,147 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package perturber
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+
+	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+)
+
+// StratumValue gets the value that subject s has for the stratum st.
+func StratumValue(s subject.Subject, st quantity.Stratum) string {
+	stats := s.Source.Stats
+	switch st {
+	case quantity.StratumThreads:
+		if stats == nil {
+			return "0"
+		}
+		return strconv.Itoa(stats.Threads)
+	case quantity.StratumRMW:
+		if stats.HasRMW() {
+			return "yes"
+		}
+		return "no"
+	case quantity.StratumMemOrders:
+		return stats.MemOrderMix()
+	default:
+		return ""
+	}
+}
+
+// Strata groups the subjects of c into strata by the features in by, keyed by quantity.StratumKey.
+func Strata(c corpus.Corpus, by []quantity.Stratum) map[string]corpus.Corpus {
+	strata := map[string]corpus.Corpus{}
+	for n, s := range c {
+		s := s
+		k := quantity.StratumKey(by, func(st quantity.Stratum) string { return StratumValue(s, st) })
+		if strata[k] == nil {
+			strata[k] = corpus.Corpus{}
+		}
+		strata[k][n] = s
+	}
+	return strata
+}
+
+// AllocateStrata decides how many of want subjects to sample from each stratum, given the size of each stratum.
+//
+// Strata with quotas get up to their quota first.  The remainder goes evenly to the strata without quotas, and
+// then, if those strata run out of subjects, evenly to any stratum with subjects left.
+func AllocateStrata(sizes map[string]int, want int, quotas map[string]int) map[string]int {
+	keys := make([]string, 0, len(sizes))
+	for k := range sizes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	alloc := make(map[string]int, len(sizes))
+	var free []string
+	for _, k := range keys {
+		q, ok := quotas[k]
+		if !ok {
+			free = append(free, k)
+			continue
+		}
+		n := minInt(minInt(q, sizes[k]), want)
+		alloc[k] = n
+		want -= n
+	}
+	want = shareOut(alloc, sizes, free, want)
+	shareOut(alloc, sizes, keys, want)
+	return alloc
+}
+
+// shareOut allocates want subjects round-robin across the strata keys, until they have no room left; it returns
+// the number of subjects it couldn't allocate.
+func shareOut(alloc, sizes map[string]int, keys []string, want int) int {
+	for progress := true; 0 < want && progress; {
+		progress = false
+		for _, k := range keys {
+			if want == 0 {
+				break
+			}
+			if alloc[k] < sizes[k] {
+				alloc[k]++
+				want--
+				progress = true
+			}
+		}
+	}
+	return want
+}
+
+func minInt(x, y int) int {
+	if x < y {
+		return x
+	}
+	return y
+}
+
+// stratifiedSample samples want subjects from c, sampling each stratum separately according to AllocateStrata.
+func (p *Perturber) stratifiedSample(rng *rand.Rand, c corpus.Corpus, want int) (corpus.Corpus, error) {
+	if len(c) == 0 {
+		return nil, corpus.ErrNone
+	}
+	if want <= 0 || len(c) <= want {
+		return c, nil
+	}
+
+	// Weights come from the whole corpus, so that novelty is still relative to every subject.
+	weight := p.weight(c)
+
+	strata := Strata(c, p.quantities.StratifyBy)
+	sizes := make(map[string]int, len(strata))
+	for k, sc := range strata {
+		sizes[k] = len(sc)
+	}
+	alloc := AllocateStrata(sizes, want, p.quantities.Quotas)
+
+	keys := make([]string, 0, len(strata))
+	for k := range strata {
+		keys = append(keys, k)
+	}
+	// Sorting keeps the sample reproducible for a given random number generator.
+	sort.Strings(keys)
+
+	sample := make(corpus.Corpus, want)
+	for _, k := range keys {
+		if alloc[k] == 0 {
+			continue
+		}
+		sc, err := sampleWith(rng, strata[k], alloc[k], weight)
+		if err != nil {
+			return nil, err
+		}
+		for n, s := range sc {
+			sample[n] = s
+		}
+	}
+	return sample, nil
+}
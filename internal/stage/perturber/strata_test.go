@@ -0,0 +1,121 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package perturber_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/stage/perturber"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/stretchr/testify/assert"
+)
+
+// ExampleAllocateStrata is a runnable example for AllocateStrata.
+func ExampleAllocateStrata() {
+	sizes := map[string]int{"threads=2": 50, "threads=3": 3, "threads=4": 20}
+	alloc := perturber.AllocateStrata(sizes, 12, map[string]int{"threads=4": 2})
+	for _, k := range []string{"threads=2", "threads=3", "threads=4"} {
+		fmt.Println(k, alloc[k])
+	}
+
+	// Output:
+	// threads=2 7
+	// threads=3 3
+	// threads=4 2
+}
+
+// TestAllocateStrata tests AllocateStrata on various allocation problems.
+func TestAllocateStrata(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		sizes  map[string]int
+		want   int
+		quotas map[string]int
+		alloc  map[string]int
+	}{
+		"even": {
+			sizes: map[string]int{"a": 10, "b": 10},
+			want:  6,
+			alloc: map[string]int{"a": 3, "b": 3},
+		},
+		"small stratum": {
+			sizes: map[string]int{"a": 10, "b": 1, "c": 10},
+			want:  7,
+			alloc: map[string]int{"a": 3, "b": 1, "c": 3},
+		},
+		"quota": {
+			sizes:  map[string]int{"a": 10, "b": 10},
+			want:   6,
+			quotas: map[string]int{"b": 5},
+			alloc:  map[string]int{"a": 1, "b": 5},
+		},
+		"quota too big": {
+			sizes:  map[string]int{"a": 10, "b": 3},
+			want:   6,
+			quotas: map[string]int{"b": 5},
+			alloc:  map[string]int{"a": 3, "b": 3},
+		},
+		"quotas exceed want": {
+			sizes:  map[string]int{"a": 10, "b": 10, "c": 10},
+			want:   6,
+			quotas: map[string]int{"a": 4, "b": 4},
+			alloc:  map[string]int{"a": 4, "b": 2},
+		},
+		"free strata full": {
+			sizes:  map[string]int{"a": 1, "b": 10},
+			want:   6,
+			quotas: map[string]int{"b": 2},
+			alloc:  map[string]int{"a": 1, "b": 5},
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, c.alloc, perturber.AllocateStrata(c.sizes, c.want, c.quotas))
+		})
+	}
+}
+
+// TestStrata tests grouping a small corpus into strata.
+func TestStrata(t *testing.T) {
+	t.Parallel()
+
+	withStats := func(threads int, rmw bool, mo string) subject.Subject {
+		s := litmus.Statset{Threads: threads}
+		if rmw {
+			s.AtomicStatements.AddType(id.FromString("xchg"), 1)
+		}
+		s.AtomicExpressions.AddMemOrder(id.FromString(mo), 1)
+		return subject.Subject{Source: litmus.Litmus{Stats: &s}}
+	}
+	c := corpus.Corpus{
+		"mp":   withStats(2, false, "memory_order_relaxed"),
+		"sb":   withStats(2, false, "memory_order_relaxed"),
+		"iriw": withStats(4, false, "memory_order_seq_cst"),
+		"rmw":  withStats(2, true, "memory_order_acquire"),
+		"none": {},
+	}
+
+	got := perturber.Strata(c, []quantity.Stratum{quantity.StratumThreads, quantity.StratumRMW, quantity.StratumMemOrders})
+	want := map[string][]string{
+		"threads=2,rmw=no,mem-orders=rlx":  {"mp", "sb"},
+		"threads=4,rmw=no,mem-orders=sc":   {"iriw"},
+		"threads=2,rmw=yes,mem-orders=ra":  {"rmw"},
+		"threads=0,rmw=no,mem-orders=none": {"none"},
+	}
+	assert.Len(t, got, len(want))
+	for k, ns := range want {
+		assert.ElementsMatch(t, ns, got[k].Names(), "stratum %s", k)
+	}
+}
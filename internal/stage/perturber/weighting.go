@@ -0,0 +1,88 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package perturber
+
+import (
+	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+)
+
+// YieldSource is the interface of things that know how productive each corpus subject has been in past cycles.
+type YieldSource interface {
+	// SubjectYield gets the number of times fuzzed descendants of the subject named name have been tested, and how
+	// many of those tests were productive (flagged, or killed a mutant).
+	SubjectYield(name string) (tested, productive uint64)
+}
+
+// Weigher computes sampling weights for the subjects of a corpus.
+type Weigher struct {
+	// Weighting is the weighting strategy.
+	Weighting quantity.Weighting
+	// Yields, if non-nil, is the source of historical yields for yield weighting.
+	Yields YieldSource
+
+	// features maps each subject name to its test features.
+	features map[string][]string
+	// featureCounts maps each test feature to the number of subjects in the corpus that have it.
+	featureCounts map[string]int
+}
+
+// NewWeigher constructs a Weigher for corpus c, with weighting strategy w and yield source ys.
+func NewWeigher(c corpus.Corpus, w quantity.Weighting, ys YieldSource) *Weigher {
+	wr := Weigher{Weighting: w, Yields: ys}
+	if w.UsesNovelty() {
+		wr.countFeatures(c)
+	}
+	return &wr
+}
+
+func (w *Weigher) countFeatures(c corpus.Corpus) {
+	w.features = make(map[string][]string, len(c))
+	w.featureCounts = map[string]int{}
+	for n, s := range c {
+		fs := s.Source.Stats.Features()
+		w.features[n] = fs
+		for _, f := range fs {
+			w.featureCounts[f]++
+		}
+	}
+}
+
+// Weight gets the sampling weight of the subject named name.
+// Weights are always positive, so that every subject retains some chance of selection.
+func (w *Weigher) Weight(name string) float64 {
+	weight := 1.0
+	if w.Weighting.UsesYield() {
+		weight *= w.yield(name)
+	}
+	if w.Weighting.UsesNovelty() {
+		weight *= w.novelty(name)
+	}
+	return weight
+}
+
+// yield gets the Laplace-smoothed productive rate of the subject named name, so that untried subjects score 1/2.
+func (w *Weigher) yield(name string) float64 {
+	var tested, productive uint64
+	if w.Yields != nil {
+		tested, productive = w.Yields.SubjectYield(name)
+	}
+	return (float64(productive) + 1) / (float64(tested) + 2)
+}
+
+// novelty gets the mean, over the features of the subject named name, of the inverse proportion of the corpus
+// sharing each feature; subjects with rare features score highly, and subjects with no features score 1.
+func (w *Weigher) novelty(name string) float64 {
+	fs := w.features[name]
+	if len(fs) == 0 {
+		return 1
+	}
+	total := 0.0
+	for _, f := range fs {
+		total += float64(len(w.features)) / float64(w.featureCounts[f])
+	}
+	return total / float64(len(fs))
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package perturber_test
+
+import (
+	"testing"
+
+	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/stage/perturber"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/stretchr/testify/assert"
+)
+
+// yieldMap is a YieldSource backed by a map from names to tested/productive pairs.
+type yieldMap map[string][2]uint64
+
+func (y yieldMap) SubjectYield(name string) (tested, productive uint64) {
+	return y[name][0], y[name][1]
+}
+
+// weighCorpus builds a corpus where foo and bar share their features, and baz has a feature of its own.
+func weighCorpus() corpus.Corpus {
+	withStats := func(s litmus.Statset) subject.Subject {
+		return subject.Subject{Source: litmus.Litmus{Stats: &s}}
+	}
+	return corpus.Corpus{
+		"foo": withStats(litmus.Statset{Threads: 2}),
+		"bar": withStats(litmus.Statset{Threads: 2}),
+		"baz": withStats(litmus.Statset{Threads: 2, Returns: 1}),
+		"qux": {},
+	}
+}
+
+// TestWeigher_Weight tests Weigher.Weight across each weighting strategy.
+func TestWeigher_Weight(t *testing.T) {
+	t.Parallel()
+
+	ys := yieldMap{"foo": {8, 8}, "bar": {8, 0}}
+	cases := map[string]struct {
+		weighting quantity.Weighting
+		want      map[string]float64
+	}{
+		"uniform": {
+			weighting: quantity.WeightUniform,
+			want:      map[string]float64{"foo": 1, "bar": 1, "baz": 1, "qux": 1},
+		},
+		"yield": {
+			weighting: quantity.WeightYield,
+			want:      map[string]float64{"foo": 0.9, "bar": 0.1, "baz": 0.5, "qux": 0.5},
+		},
+		"novelty": {
+			weighting: quantity.WeightNovelty,
+			want:      map[string]float64{"foo": 4.0 / 3.0, "bar": 4.0 / 3.0, "baz": (4.0/3.0 + 4.0) / 2.0, "qux": 1},
+		},
+		"mixed": {
+			weighting: quantity.WeightMixed,
+			want:      map[string]float64{"foo": 0.9 * 4.0 / 3.0, "bar": 0.1 * 4.0 / 3.0, "baz": 0.5 * (4.0/3.0 + 4.0) / 2.0, "qux": 0.5},
+		},
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := perturber.NewWeigher(weighCorpus(), c.weighting, ys)
+			for n, want := range c.want {
+				assert.InDelta(t, want, w.Weight(n), 1e-9, "wrong weight for %s", n)
+			}
+		})
+	}
+}
+
+// TestWeigher_Weight_noYields tests that yield weighting treats every subject as untried without a yield source.
+func TestWeigher_Weight_noYields(t *testing.T) {
+	t.Parallel()
+
+	w := perturber.NewWeigher(weighCorpus(), quantity.WeightYield, nil)
+	for n := range weighCorpus() {
+		assert.InDelta(t, 0.5, w.Weight(n), 1e-9, "wrong weight for %s", n)
+	}
+}
@@ -7,6 +7,11 @@ package planner
 
 import (
 	"context"
+	"fmt"
+
+	"github.com/c4-project/c4t/internal/helper/iohelp"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/litmus"
 
 	"github.com/c4-project/c4t/internal/quantity"
 
@@ -53,9 +58,9 @@ type CorpusPlanner struct {
 
 // Plan probes each subject in this planner's corpus file list, producing a Corpus proper.
 // It does not sample; sampling is left to the perturb stage.
+// It does, however, drop any test that is semantically the same as one it has already probed.
 func (p *CorpusPlanner) Plan(ctx context.Context) (corpus.Corpus, error) {
 	cfg := p.makeBuilderConfig()
-	// TODO(@MattWindsor91): perform corpus pruning
 	return builder.ParBuild(ctx, p.Quantities.NWorkers, corpus.New(p.Files...), cfg,
 		func(ctx context.Context, named subject.Named, requests chan<- builder.Request) error {
 			// TODO(@MattWindsor91): make it so we don't get the litmus file through the *name* of the subject!
@@ -69,6 +74,7 @@ func (p *CorpusPlanner) makeBuilderConfig() builder.Config {
 	return builder.Config{
 		Init:      nil,
 		Observers: p.Observers,
+		Dedup:     true,
 		Manifest: builder.Manifest{
 			Name:  "plan",
 			NReqs: len(p.Files),
@@ -110,9 +116,36 @@ func expandDir(d string, curr []string) ([]string, error) {
 }
 
 func (p *CorpusPlanner) probeSubject(ctx context.Context, f string, ch chan<- builder.Request) error {
-	s, err := p.Prober.ProbeSubject(ctx, f)
+	s, err := p.probe(ctx, f)
 	if err != nil {
 		return err
 	}
 	return builder.AddRequest(s).SendTo(ctx, ch)
 }
+
+// probe probes the C litmus test at filepath f, using its statistics file instead of the prober if it has one.
+func (p *CorpusPlanner) probe(ctx context.Context, f string) (*subject.Named, error) {
+	if !yos.ExistFile(litmus.StatsFile(f)) {
+		return p.Prober.ProbeSubject(ctx, f)
+	}
+	return ProbeRecorded(f)
+}
+
+// ProbeRecorded probes the C litmus test at filepath f using only its statistics file, and so without needing c4f.
+//
+// Tests with statistics files come from generators such as c4t-gen, which name each test after its file.
+func ProbeRecorded(f string) (*subject.Named, error) {
+	l, err := litmus.New(f,
+		litmus.WithArch(id.ArchC),
+		litmus.ReadStatsFromFile(),
+		litmus.ReadFingerprintFromFile(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("reading recorded stats for %s: %w", f, err)
+	}
+	s, err := subject.New(l)
+	if err != nil {
+		return nil, err
+	}
+	return s.AddName(iohelp.ExtlessFile(f)), nil
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/c4-project/c4t/internal/quantity"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/c4-project/c4t/internal/model/litmus"
@@ -21,6 +22,7 @@ import (
 	"github.com/c4-project/c4t/internal/helper/testhelp"
 
 	"github.com/c4-project/c4t/internal/helper/iohelp"
+	"github.com/c4-project/c4t/internal/litmusgen"
 	"github.com/c4-project/c4t/internal/stage/planner"
 	"github.com/c4-project/c4t/internal/subject"
 )
@@ -79,6 +81,35 @@ func TestCorpusPlanner_Plan_ProbeError(t *testing.T) {
 	testhelp.ExpectErrorIs(t, err, tp.err, "Plan with error returned by prober")
 }
 
+// TestCorpusPlanner_Plan_generated tests that planning generated tests uses their recorded statistics, and so
+// doesn't need a prober (and, in turn, c4f).
+func TestCorpusPlanner_Plan_generated(t *testing.T) {
+	t.Parallel()
+
+	mp, err := litmusgen.LookupShape("MP")
+	require.NoError(t, err, "looking up shape")
+	ts := litmusgen.Config{Shapes: []litmusgen.Shape{mp}, Uniform: true}.Generate()
+	files, err := litmusgen.WriteAll(t.TempDir(), ts)
+	require.NoError(t, err, "generating tests")
+
+	// This prober stands in for c4f not being installed.
+	tp := TestProber{err: errors.New("c4f-c: executable file not found in $PATH")}
+	p := planner.CorpusPlanner{Files: files, Prober: &tp, Quantities: quantity.PlanSet{NWorkers: 2}}
+	c, err := p.Plan(context.Background())
+	require.NoError(t, err, "planning generated tests")
+	require.Len(t, c, len(ts), "corpus size mismatch")
+
+	for _, tt := range ts {
+		s, ok := c[tt.Name()]
+		require.True(t, ok, "generated test %s missing from corpus", tt.Name())
+		assert.Equal(t, tt.Stats(), s.Source.Stats, "stats of %s", tt.Name())
+		assert.True(t, s.Source.IsC(), "%s should be a C test", tt.Name())
+		assert.NotEmpty(t, s.Source.Fingerprint, "%s should have a fingerprint", tt.Name())
+		_, probed := tp.probes.Load(s.Source.Path)
+		assert.False(t, probed, "%s shouldn't have been probed", tt.Name())
+	}
+}
+
 // makeCorpusPlanner builds a test CorpusPlanner using tp as the prober.
 func makeCorpusPlanner(tp planner.SubjectProber) *planner.CorpusPlanner {
 	in := []string{"foo.litmus", "bar.litmus", "baz.litmus", "foobar.litmus", "foobaz.litmus", "barbaz.litmus"}
@@ -61,13 +61,13 @@ func ExampleSet_DumpMutationCSV() {
 	_ = s.DumpMutationCSV(w, true)
 
 	// Output:
-	// Machine,Index,Name,Selections,Hits,Kills,Ok,Filtered,Flagged,CompileFail,CompileTimeout,RunFail,RunTimeout
-	// foo,2,,1,0,0,0,1,0,0,0,0,0
-	// foo,42,FOO,10,1,0,9,0,0,0,1,0,0
-	// foo,53,BAR5,20,400,15,0,0,15,3,0,2,0
+	// Machine,Index,Name,Selections,Hits,Kills,Ok,Filtered,Flagged,CompileFail,CompileTimeout,RunFail,RunTimeout,RunLimit
+	// foo,2,,1,0,0,0,1,0,0,0,0,0,0
+	// foo,42,FOO,10,1,0,9,0,0,0,1,0,0,0
+	// foo,53,BAR5,20,400,15,0,0,15,3,0,2,0,0
 	// --
-	// bar,1,,500,0,0,500,0,0,0,0,0,0
-	// foo,2,,41,5000,40,0,1,40,0,0,0,0
-	// foo,42,FOO,100,1,0,99,0,0,0,1,0,0
-	// foo,53,BAR5,20,400,15,0,0,15,3,0,2,0
+	// bar,1,,500,0,0,500,0,0,0,0,0,0,0
+	// foo,2,,41,5000,40,0,1,40,0,0,0,0,0
+	// foo,42,FOO,100,1,0,99,0,0,0,1,0,0,0
+	// foo,53,BAR5,20,400,15,0,0,15,3,0,2,0,0
 }
@@ -0,0 +1,213 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package stat
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/c4-project/c4t/internal/director"
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/mutation"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/subject/status"
+	"github.com/c4-project/c4t/internal/tabulator"
+)
+
+// CycleRecord is an entry in the statistics history, covering one compiler in one cycle on one machine.
+//
+// Unlike the session and total counters in Set, cycle records are never aggregated, and so can be used to spot trends
+// over time (such as the flagged rate dropping after a compiler upgrade).
+type CycleRecord struct {
+	// Time is the time at which the cycle started.
+	Time time.Time `json:"time"`
+	// Machine is the ID of the machine on which the cycle ran.
+	Machine id.ID `json:"machine"`
+	// Iter is the iteration number of the cycle.
+	Iter uint64 `json:"iter"`
+	// Compiler is the ID of the compiler.
+	Compiler id.ID `json:"compiler"`
+	// Opt is the name of the optimisation level selected for the compiler, if any.
+	Opt string `json:"opt,omitempty"`
+	// Mutant is the index of the mutant selected for the cycle, if this is a mutation test.
+	Mutant mutation.Index `json:"mutant,omitempty"`
+	// Seed is the seed of the cycle's plan, from which `c4t replay` can rerun the cycle.
+	Seed int64 `json:"seed,omitempty"`
+	// FuzzParams contains the values of any adaptively tuned fuzzer parameters used in the cycle.
+	FuzzParams map[string]string `json:"fuzz_params,omitempty"`
+	// FuzzHashes maps the names of subjects that the cycle fuzzed to the hashes of their fuzzed files, so that
+	// replays of the cycle can be checked against it.
+	FuzzHashes map[string]string `json:"fuzz_hashes,omitempty"`
+	// Statuses counts the subjects that the compiler gave each status.
+	Statuses map[status.Status]int `json:"statuses,omitempty"`
+	// CompileTime summarises the compile times for the compiler.
+	CompileTime TimeSummary `json:"compile_time"`
+	// RunTime summarises the run times for the compiler.
+	RunTime TimeSummary `json:"run_time"`
+}
+
+// TimeSummary is a serialisable summary of a timing set.
+type TimeSummary struct {
+	// Min is the minimum time.
+	Min time.Duration `json:"min,omitempty"`
+	// Mean is the arithmetic mean time.
+	Mean time.Duration `json:"mean,omitempty"`
+	// Max is the maximum time.
+	Max time.Duration `json:"max,omitempty"`
+	// Count is the number of times sampled.
+	Count int `json:"count,omitempty"`
+}
+
+// summariseTimes summarises the time set ts, which may be nil.
+func summariseTimes(ts *analysis.TimeSet) TimeSummary {
+	if ts == nil {
+		return TimeSummary{}
+	}
+	return TimeSummary{Min: ts.Min, Mean: ts.Mean(), Max: ts.Max, Count: ts.Count}
+}
+
+// NewCycleRecords makes the history records for the cycle analysis a, in compiler order.
+func NewCycleRecords(a director.CycleAnalysis) []CycleRecord {
+	var (
+		mutant mutation.Index
+		replay director.Replay
+	)
+	if a.Plan != nil {
+		mutant = a.Plan.Mutant().Index
+		replay = director.ReplayOfPlan(a.Plan)
+	}
+
+	rs := make([]CycleRecord, 0, len(a.Compilers))
+	for cid, c := range a.Compilers {
+		rs = append(rs, CycleRecord{
+			Time:        a.Cycle.Start,
+			Machine:     a.Cycle.MachineID,
+			Iter:        a.Cycle.Iter,
+			Compiler:    cid,
+			Opt:         c.Info.SelectedOptName(),
+			Mutant:      mutant,
+			Seed:        replay.Seed,
+			FuzzParams:  replay.Params,
+			FuzzHashes:  replay.Hashes,
+			Statuses:    c.Counts,
+			CompileTime: summariseTimes(c.Time),
+			RunTime:     summariseTimes(c.RunTime),
+		})
+	}
+	sort.Slice(rs, func(i, j int) bool { return rs[i].Compiler.Less(rs[j].Compiler) })
+	return rs
+}
+
+// Replay gets a description of this record's cycle that the director can use to replay it.
+func (r CycleRecord) Replay() director.Replay {
+	return director.Replay{
+		Machine: r.Machine,
+		Iter:    r.Iter,
+		Seed:    r.Seed,
+		Mutant:  mutation.Mutant{Index: r.Mutant},
+		Params:  r.FuzzParams,
+		Hashes:  r.FuzzHashes,
+	}
+}
+
+// HistoryQuery selects cycle records from the statistics history.
+type HistoryQuery struct {
+	// Since, if non-zero, excludes records from before this time.
+	Since time.Time
+	// Until, if non-zero, excludes records from this time onwards.
+	Until time.Time
+	// Machine, if non-empty, is a glob ID that records' machine IDs must match.
+	Machine id.ID
+	// Compiler, if non-empty, is a glob ID that records' compiler IDs must match.
+	Compiler id.ID
+}
+
+// Matches gets whether r matches this query.
+func (q HistoryQuery) Matches(r CycleRecord) (bool, error) {
+	if !q.Since.IsZero() && r.Time.Before(q.Since) {
+		return false, nil
+	}
+	if !q.Until.IsZero() && !r.Time.Before(q.Until) {
+		return false, nil
+	}
+	if ok, err := matchesGlob(r.Machine, q.Machine); !ok || err != nil {
+		return false, err
+	}
+	return matchesGlob(r.Compiler, q.Compiler)
+}
+
+func matchesGlob(i, glob id.ID) (bool, error) {
+	if glob.IsEmpty() {
+		return true, nil
+	}
+	return i.Matches(glob)
+}
+
+// ReadHistory reads every cycle record in the history log r that matches q.
+func ReadHistory(r io.Reader, q HistoryQuery) ([]CycleRecord, error) {
+	var rs []CycleRecord
+	dec := json.NewDecoder(r)
+	for {
+		var rec CycleRecord
+		if err := dec.Decode(&rec); err != nil {
+			if errors.Is(err, io.EOF) {
+				return rs, nil
+			}
+			return rs, err
+		}
+		ok, err := q.Matches(rec)
+		if err != nil {
+			return rs, err
+		}
+		if ok {
+			rs = append(rs, rec)
+		}
+	}
+}
+
+// LoadHistoryFile reads every cycle record in the history log file name that matches q.
+func LoadHistoryFile(name string, q HistoryQuery) ([]CycleRecord, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	rs, rerr := ReadHistory(f, q)
+	cerr := f.Close()
+	return rs, errhelp.FirstError(rerr, cerr)
+}
+
+// OpenHistoryFile opens a file in the appropriate mode for using it as an append-only history log.
+func OpenHistoryFile(name string) (*os.File, error) {
+	return os.OpenFile(name, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+}
+
+// TabulateHistory writes the cycle records rs to t, one row per record, and flushes t.
+func TabulateHistory(t tabulator.Tabulator, rs []CycleRecord) error {
+	hdr := []string{"Time", "Machine", "Iter", "Compiler", "Opt", "Mutant"}
+	for i := status.Ok; i <= status.Last; i++ {
+		hdr = append(hdr, i.String())
+	}
+	t.Header(append(hdr, "MeanCompile", "MeanRun")...)
+
+	for _, r := range rs {
+		row := t.Cell(r.Time.Format(time.RFC3339)).Cell(r.Machine).Cell(r.Iter).Cell(r.Compiler).Cell(r.Opt).Cell(uint64(r.Mutant))
+		for i := status.Ok; i <= status.Last; i++ {
+			row = row.Cell(r.Statuses[i])
+		}
+		row.Cell(seconds(r.CompileTime.Mean)).Cell(seconds(r.RunTime.Mean)).EndRow()
+	}
+	return t.Flush()
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
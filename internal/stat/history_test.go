@@ -0,0 +1,129 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package stat_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/c4-project/c4t/internal/director"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/mutation"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/stat"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/status"
+	"github.com/c4-project/c4t/internal/tabulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockCycleAnalysis analyses the mock plan as if it were iteration iter of a cycle on machine mid starting at start.
+func mockCycleAnalysis(t *testing.T, mid string, iter uint64, start time.Time) director.CycleAnalysis {
+	t.Helper()
+	an, err := analysis.Analyse(context.Background(), plan.Mock())
+	require.NoError(t, err, "analysing mock plan")
+	return director.CycleAnalysis{
+		Cycle:    director.Cycle{MachineID: id.FromString(mid), Iter: iter, Start: start},
+		Analysis: *an,
+	}
+}
+
+// TestPersister_history tests that the persister appends cycle records to its history log, and that they can be
+// queried back.
+func TestPersister_history(t *testing.T) {
+	t.Parallel()
+
+	td := t.TempDir()
+	f, err := stat.OpenStatFile(filepath.Join(td, "stats.json"))
+	require.NoError(t, err, "opening stat file")
+	hpath := filepath.Join(td, "history.jsonl")
+	h, err := stat.OpenHistoryFile(hpath)
+	require.NoError(t, err, "opening history file")
+
+	sp, err := stat.NewPersister(f, stat.AppendHistoryTo(h))
+	require.NoError(t, err, "opening persister")
+
+	t0 := time.Date(2011, time.November, 11, 11, 0, 0, 0, time.UTC)
+	sp.OnCycleAnalysis(mockCycleAnalysis(t, "foo", 1, t0))
+	sp.OnCycleAnalysis(mockCycleAnalysis(t, "bar", 1, t0.Add(time.Hour)))
+	sp.OnCycleAnalysis(mockCycleAnalysis(t, "foo", 2, t0.Add(2*time.Hour)))
+	require.NoError(t, sp.Close(), "closing persister")
+
+	all, err := stat.LoadHistoryFile(hpath, stat.HistoryQuery{})
+	require.NoError(t, err, "loading history")
+	assert.Len(t, all, 3*len(plan.Mock().Compilers), "should be one record per compiler per cycle")
+
+	cases := map[string]struct {
+		q    stat.HistoryQuery
+		want int
+	}{
+		"machine":          {q: stat.HistoryQuery{Machine: id.FromString("foo")}, want: 2 * len(plan.Mock().Compilers)},
+		"compiler":         {q: stat.HistoryQuery{Compiler: id.FromString("gcc")}, want: 3},
+		"since":            {q: stat.HistoryQuery{Since: t0.Add(time.Hour)}, want: 2 * len(plan.Mock().Compilers)},
+		"until":            {q: stat.HistoryQuery{Until: t0.Add(time.Hour)}, want: len(plan.Mock().Compilers)},
+		"machine+compiler": {q: stat.HistoryQuery{Machine: id.FromString("bar"), Compiler: id.FromString("gcc")}, want: 1},
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := stat.LoadHistoryFile(hpath, c.q)
+			require.NoError(t, err, "loading history")
+			assert.Len(t, got, c.want, "wrong number of records")
+		})
+	}
+}
+
+// TestCycleRecord_Replay tests that a cycle record keeps everything needed to replay and check its cycle.
+func TestCycleRecord_Replay(t *testing.T) {
+	t.Parallel()
+
+	p := plan.Mock()
+	p.Metadata.Seed = 8675309
+	p.Mutation = &mutation.Config{Enabled: true, Selection: mutation.Mutant{Index: 42}}
+	s := p.Corpus["foo"]
+	s.Fuzz = &subject.Fuzz{Params: map[string]string{"int.action_cap_upper": "42"}, Hash: "cafe"}
+	p.Corpus["foo"] = s
+
+	an, err := analysis.Analyse(context.Background(), p)
+	require.NoError(t, err, "analysing plan")
+	ca := director.CycleAnalysis{Cycle: director.Cycle{MachineID: id.FromString("foo"), Iter: 3}, Analysis: *an}
+
+	rs := stat.NewCycleRecords(ca)
+	require.NotEmpty(t, rs, "should have records")
+	r := rs[0].Replay()
+	assert.Equal(t, mutation.Index(42), r.Mutant.Index, "mutant")
+	assert.Equal(t, map[string]string{"foo": "cafe"}, r.Hashes, "hashes")
+	assert.Equal(t, int64(8675309), r.Seed, "seed")
+	assert.Equal(t, uint64(3), r.Iter, "iter")
+	assert.False(t, r.Check(p).Unverified(), "replay should be checkable")
+}
+
+// ExampleTabulateHistory is a runnable example for TabulateHistory.
+func ExampleTabulateHistory() {
+	rs := []stat.CycleRecord{
+		{
+			Time:        time.Date(2011, time.November, 11, 11, 0, 0, 0, time.UTC),
+			Machine:     id.FromString("foo"),
+			Iter:        1,
+			Compiler:    id.FromString("gcc"),
+			Opt:         "3",
+			Statuses:    map[status.Status]int{status.Ok: 9, status.Flagged: 1},
+			CompileTime: stat.TimeSummary{Mean: 1500 * time.Millisecond},
+			RunTime:     stat.TimeSummary{Mean: 250 * time.Millisecond},
+		},
+	}
+	_ = stat.TabulateHistory(tabulator.NewCsv(os.Stdout), rs)
+
+	// Output:
+	// Time,Machine,Iter,Compiler,Opt,Mutant,Ok,Filtered,Flagged,CompileFail,CompileTimeout,RunFail,RunTimeout,RunLimit,MeanCompile,MeanRun
+	// 2011-11-11T11:00:00Z,foo,1,gcc,3,0,9,0,1,0,0,0,0,0,1.500,0.250
+}
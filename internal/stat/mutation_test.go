@@ -85,7 +85,7 @@ func ExampleMutation_DumpCSV() {
 	}).DumpCSV(csv.NewWriter(os.Stdout), id.FromString("localhost"))
 
 	// Output:
-	// localhost,2,,1,0,0,0,1,0,0,0,0,0
-	// localhost,42,FOO,10,1,0,9,0,0,0,1,0,0
-	// localhost,53,BAR10,20,400,15,0,0,15,3,0,2,0
+	// localhost,2,,1,0,0,0,1,0,0,0,0,0,0
+	// localhost,42,FOO,10,1,0,9,0,0,0,1,0,0,0
+	// localhost,53,BAR10,20,400,15,0,0,15,3,0,2,0,0
 }
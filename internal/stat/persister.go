@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sync"
 
 	"github.com/c4-project/c4t/internal/subject/corpus/builder"
 
@@ -27,6 +28,8 @@ import (
 
 // Persister is a forward handler that maintains and persists a statistics set on disk.
 type Persister struct {
+	// mu guards the yields in set, which other goroutines can read through SubjectYield.
+	mu sync.RWMutex
 	// set is the statistics set being persisted.
 	set Set
 	// f is the target file (we need a file to be able to truncate properly).
@@ -37,13 +40,32 @@ type Persister struct {
 	err error
 	// lastCount is the value of EventCount when set was last committed to disk.
 	lastCount uint64
+	// history, if non-nil, is the append-only file to which we log per-cycle records.
+	history *os.File
+	// henc is the encoder writing to history.
+	henc *json.Encoder
 }
 
-// NewPersister creates a Persister that reads and writes statistics from f.
+// PersisterOption is the type of options to NewPersister.
+type PersisterOption func(*Persister)
+
+// AppendHistoryTo makes the Persister append a record of each cycle analysis to the history file h.
+// The Persister takes ownership of h; see OpenHistoryFile.
+func AppendHistoryTo(h *os.File) PersisterOption {
+	return func(p *Persister) {
+		p.history = h
+		p.henc = json.NewEncoder(h)
+	}
+}
+
+// NewPersister creates a Persister that reads and writes statistics from f, with options o.
 // If f is non-empty, it immediately tries to read any existing stats dump, and fails if this doesn't work.
 // The Persister takes ownership of f; close f with the Persister's Close method.
-func NewPersister(f *os.File) (*Persister, error) {
+func NewPersister(f *os.File, o ...PersisterOption) (*Persister, error) {
 	sp := Persister{f: f, enc: json.NewEncoder(f)}
+	for _, opt := range o {
+		opt(&sp)
+	}
 	// Not strictly necessary, but makes eyeballing the stats easier.
 	sp.enc.SetIndent("", "\t")
 	if err := sp.tryReadStats(); err != nil {
@@ -57,7 +79,10 @@ func NewPersister(f *os.File) (*Persister, error) {
 func (s *Persister) Close() error {
 	perr := s.err
 	cerr := s.f.Close()
-	return errhelp.FirstError(perr, cerr)
+	if s.history == nil {
+		return errhelp.FirstError(perr, cerr)
+	}
+	return errhelp.FirstError(perr, cerr, s.history.Close())
 }
 
 // OnMachines feeds the information from m into the stats set.
@@ -86,10 +111,32 @@ func (s *Persister) OnCycleInstance(c director.Cycle, m director.InstanceMessage
 
 // OnCycleAnalysis feeds the information from a into the stats set.
 func (s *Persister) OnCycleAnalysis(a director.CycleAnalysis) {
+	s.mu.Lock()
 	s.set.OnCycleAnalysis(a)
+	s.mu.Unlock()
+	s.appendHistory(a)
 	s.flush()
 }
 
+// SubjectYield gets the number of tested, and productive, fuzzed descendants of the subject named name.
+// It is safe to call concurrently with the persister's observer methods.
+func (s *Persister) SubjectYield(name string) (tested, productive uint64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.set.SubjectYield(name)
+}
+
+func (s *Persister) appendHistory(a director.CycleAnalysis) {
+	if s.history == nil || s.err != nil {
+		return
+	}
+	for _, r := range NewCycleRecords(a) {
+		if s.err = s.henc.Encode(r); s.err != nil {
+			return
+		}
+	}
+}
+
 // OnCycleBuild feeds the information from c and m into the stats set.
 func (s *Persister) OnCycleBuild(c director.Cycle, m builder.Message) {
 	s.set.OnCycleBuild(c, m)
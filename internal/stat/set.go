@@ -40,6 +40,10 @@ type Set struct {
 
 	// Machines is a map from machine IDs to statistics about those machines.
 	Machines map[id.ID]Machine `json:"machines,omitempty"`
+
+	// Yields maps the names of original corpus subjects to how productive their fuzzed descendants have been,
+	// across all machines and sessions.
+	Yields YieldSet `json:"yields,omitempty"`
 }
 
 // OnCycle incorporates cycle information from c into the statistics set.
@@ -55,6 +59,10 @@ func (s *Set) OnCycleAnalysis(a director.CycleAnalysis) {
 	s.liftCycle(a.Cycle, func(m *Machine) {
 		m.AddAnalysis(a.Analysis)
 	})
+	if s.Yields == nil {
+		s.Yields = YieldSet{}
+	}
+	s.Yields.AddAnalysis(a.Analysis)
 	s.EventCount++
 }
 
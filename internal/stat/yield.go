@@ -0,0 +1,60 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package stat
+
+import (
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/stage/fuzzer"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/status"
+)
+
+// Yield counts how productive the fuzzed descendants of one corpus subject have been.
+type Yield struct {
+	// Tested is the number of descendants that have been tested.
+	Tested uint64 `json:"tested"`
+	// Productive is the number of tested descendants that were flagged on at least one compiler.
+	// As mutants are only killed by flagged compilations, this includes every descendant that killed a mutant.
+	Productive uint64 `json:"productive,omitempty"`
+}
+
+// YieldSet maps the names of original corpus subjects to their yields.
+type YieldSet map[string]Yield
+
+// AddAnalysis adds the subjects tested in analysis a to this yield set.
+func (ys YieldSet) AddAnalysis(a analysis.Analysis) {
+	if a.Plan == nil {
+		return
+	}
+	flagged := a.ByStatus[status.Flagged]
+	for n, s := range a.Plan.Corpus {
+		sn := seedName(n, s)
+		y := ys[sn]
+		y.Tested++
+		if _, ok := flagged[n]; ok {
+			y.Productive++
+		}
+		ys[sn] = y
+	}
+}
+
+// SubjectYield gets the number of tested, and productive, fuzzed descendants of the subject named name.
+func (s *Set) SubjectYield(name string) (tested, productive uint64) {
+	y := s.Yields[name]
+	return y.Tested, y.Productive
+}
+
+// seedName gets the name of the original corpus subject from which the subject s, named name, descends.
+func seedName(name string, s subject.Subject) string {
+	if s.Fuzz == nil {
+		return name
+	}
+	sc, err := fuzzer.ParseSubjectCycle(name)
+	if err != nil {
+		return name
+	}
+	return sc.Name
+}
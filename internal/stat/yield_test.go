@@ -0,0 +1,54 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package stat_test
+
+import (
+	"testing"
+
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/stat"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/c4-project/c4t/internal/subject/status"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestYieldSet_AddAnalysis tests that yields are credited to the subjects from which fuzzed subjects descend.
+func TestYieldSet_AddAnalysis(t *testing.T) {
+	t.Parallel()
+
+	fuzzed := subject.Subject{Fuzz: &subject.Fuzz{}}
+	p := plan.Mock()
+	p.Corpus = corpus.Corpus{
+		"foo_0":   fuzzed,
+		"foo_1":   fuzzed,
+		"bar_baz": fuzzed,
+		"qux_2":   {},
+	}
+	a := analysis.Analysis{
+		Plan: p,
+		ByStatus: map[status.Status]corpus.Corpus{
+			status.Flagged: {"foo_1": fuzzed, "qux_2": {}},
+		},
+	}
+
+	ys := stat.YieldSet{"foo": {Tested: 3, Productive: 1}}
+	ys.AddAnalysis(a)
+	assert.Equal(t,
+		stat.YieldSet{
+			"foo":     {Tested: 5, Productive: 2},
+			"bar_baz": {Tested: 1},
+			"qux_2":   {Tested: 1, Productive: 1},
+		},
+		ys,
+		"wrong yields after analysis")
+
+	s := stat.Set{Yields: ys}
+	tested, productive := s.SubjectYield("foo")
+	assert.Equal(t, uint64(5), tested, "wrong tested count")
+	assert.Equal(t, uint64(2), productive, "wrong productive count")
+}
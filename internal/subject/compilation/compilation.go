@@ -13,6 +13,8 @@ type Compilation struct {
 	Compile *CompileResult `json:"compile,omitempty"`
 	// Run contains any information about the run phase of this compilation.
 	Run *RunResult `json:"run,omitempty"`
+	// Iterations contains, if present, the iteration parameters chosen for running this compilation.
+	Iterations *Iterations `json:"iterations,omitempty"`
 }
 
 // Map is shorthand for a map from compiler IDs to compilations.
@@ -0,0 +1,21 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package compilation
+
+// Iterations holds the parameters that control how much work a test harness does per run.
+//
+// These correspond to the size (-s) and number of runs (-r) of a litmus7 harness.
+type Iterations struct {
+	// Size is the number of test iterations in each batch.
+	Size int `json:"size,omitempty"`
+	// Count is the number of batches.
+	Count int `json:"count,omitempty"`
+}
+
+// Total gets the total number of test iterations described by it.
+func (it Iterations) Total() int {
+	return it.Size * it.Count
+}
@@ -30,3 +30,11 @@ func (n Name) String() string {
 func (n Name) Path() string {
 	return path.Join(append(n.CompilerID.Tags(), n.SubjectName)...)
 }
+
+// Less gets whether this name orders before n2, first by subject name and then by compiler ID.
+func (n Name) Less(n2 Name) bool {
+	if n.SubjectName != n2.SubjectName {
+		return n.SubjectName < n2.SubjectName
+	}
+	return n.CompilerID.Less(n2.CompilerID)
+}
@@ -14,5 +14,14 @@ type RunResult struct {
 	Result
 
 	// Obs is this run's processed observation, if any.
+	// If the binary was run several times, this aggregates the observations of each run.
 	Obs *obs.Obs `toml:"obs,omitempty" json:"obs,omitempty"`
+
+	// Runs is the number of times the binary was run to produce this result, including any run that failed or timed
+	// out (and so stopped the repetition).
+	// If this number is zero, the binary was run once.
+	Runs int `toml:"runs,omitempty" json:"runs,omitempty"`
+
+	// Flaky is true if the binary was run several times, and the postcondition verdict differed between runs.
+	Flaky bool `toml:"flaky,omitempty" json:"flaky,omitempty"`
 }
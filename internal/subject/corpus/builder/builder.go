@@ -44,6 +44,9 @@ type Builder struct {
 	// obs is the observer set for the builder.
 	obs []Observer
 
+	// fingerprints maps litmus fingerprints to the subjects that have them, if we're deduplicating.
+	fingerprints map[string]fingerprinted
+
 	// reqCh is the receiving channel for requests.
 	reqCh <-chan Request
 
@@ -66,9 +69,49 @@ func New(cfg Config) (*Builder, error) {
 		reqCh:  reqCh,
 		SendCh: reqCh,
 	}
+	if cfg.Dedup {
+		b.fingerprints = initFingerprints(b.c)
+	}
 	return &b, nil
 }
 
+// fingerprinted records which subject in the corpus has a particular fingerprint.
+type fingerprinted struct {
+	// name is the name of the subject.
+	name string
+	// init is true if the subject was in the builder's initial corpus.
+	init bool
+}
+
+// wins gets whether a newly added subject called name should replace the subject f with the same fingerprint.
+//
+// Subjects from the initial corpus always stay; otherwise, the subject with the lexicographically smallest name
+// stays.  This makes the outcome independent of the order in which duplicates arrive.
+func (f fingerprinted) wins(name string) bool {
+	return !f.init && name < f.name
+}
+
+func initFingerprints(c corpus.Corpus) map[string]fingerprinted {
+	fps := make(map[string]fingerprinted, len(c))
+	for _, name := range c.Names() {
+		if fp := fingerprint(c[name]); fp != "" {
+			if _, ok := fps[fp]; !ok {
+				fps[fp] = fingerprinted{name: name, init: true}
+			}
+		}
+	}
+	return fps
+}
+
+// fingerprint gets the fingerprint of s's best litmus test, if it has one.
+func fingerprint(s subject.Subject) string {
+	l, err := s.BestLitmus()
+	if err != nil {
+		return ""
+	}
+	return l.Fingerprint
+}
+
 func initCorpus(init corpus.Corpus, nreqs int) corpus.Corpus {
 	if init == nil {
 		// The requests are probably all going to be add requests, so it's a good starter capacity.
@@ -102,6 +145,13 @@ func (b *Builder) runSingle(i int, ctx context.Context) error {
 }
 
 func (b *Builder) runRequest(i int, r Request) error {
+	if of, ok := b.duplicateOf(r); ok {
+		if !of.wins(r.Name) {
+			OnBuild(DuplicateMessage(i, r, of.name), b.obs...)
+			return nil
+		}
+		return b.replace(i, of.name, r)
+	}
 	OnBuild(StepMessage(i, r), b.obs...)
 	switch {
 	case r.Add != nil:
@@ -117,8 +167,42 @@ func (b *Builder) runRequest(i int, r Request) error {
 	}
 }
 
+// duplicateOf gets the subject that r would duplicate, if r is an add request and we're deduplicating.
+func (b *Builder) duplicateOf(r Request) (fingerprinted, bool) {
+	if b.fingerprints == nil || r.Add == nil {
+		return fingerprinted{}, false
+	}
+	fp := fingerprint(subject.Subject(*r.Add))
+	if fp == "" {
+		return fingerprinted{}, false
+	}
+	of, ok := b.fingerprints[fp]
+	return of, ok
+}
+
+// replace handles the add request r, which is step i, by replacing the duplicate subject old with r's subject.
+//
+// We report the step as old being dropped as a duplicate of r's subject.
+func (b *Builder) replace(i int, old string, r Request) error {
+	olds := subject.Named{Name: old, Subject: b.c[old]}
+	delete(b.c, old)
+	if err := b.add(r.Name, subject.Subject(*r.Add)); err != nil {
+		return err
+	}
+	OnBuild(DuplicateMessage(i, AddRequest(&olds), r.Name), b.obs...)
+	return nil
+}
+
 func (b *Builder) add(name string, s subject.Subject) error {
-	return b.c.Add(subject.Named{Name: name, Subject: s})
+	if err := b.c.Add(subject.Named{Name: name, Subject: s}); err != nil {
+		return err
+	}
+	if b.fingerprints != nil {
+		if fp := fingerprint(s); fp != "" {
+			b.fingerprints[fp] = fingerprinted{name: name}
+		}
+	}
+	return nil
 }
 
 func (b *Builder) addCompile(name string, cid id.ID, res compilation.CompileResult) error {
@@ -14,7 +14,9 @@ import (
 
 	"github.com/c4-project/c4t/internal/model/litmus"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/c4-project/c4t/internal/subject/corpus/builder/mocks"
 
@@ -105,6 +107,86 @@ func TestBuilder_Run_Adds(t *testing.T) {
 	obs.AssertExpectations(t)
 }
 
+// TestBuilder_Run_dedup tests that a deduplicating builder keeps one subject per fingerprint, and that which one it
+// keeps doesn't depend on the order of the adds.
+func TestBuilder_Run_dedup(t *testing.T) {
+	t.Parallel()
+
+	withFP := func(path, fp string) subject.Subject {
+		l := litmus.NewOrPanic(path)
+		l.Fingerprint = fp
+		return *subject.NewOrPanic(l)
+	}
+	adds := []subject.Named{
+		{Name: "foo", Subject: withFP("foo.litmus", "bbbb")},
+		{Name: "bar", Subject: withFP("bar.litmus", "bbbb")},
+		{Name: "baz", Subject: withFP("baz.litmus", "aaaa")},
+		{Name: "nofp1", Subject: withFP("nofp1.litmus", "")},
+		{Name: "nofp2", Subject: withFP("nofp2.litmus", "")},
+	}
+	reversed := make([]subject.Named, len(adds))
+	for i, a := range adds {
+		reversed[len(adds)-1-i] = a
+	}
+
+	for name, adds := range map[string][]subject.Named{"forwards": adds, "backwards": reversed} {
+		adds := adds
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var msgs []builder.Message
+			b, err := builder.New(builder.Config{
+				Init:      corpus.Corpus{"seed": withFP("seed.litmus", "aaaa")},
+				Observers: []builder.Observer{recorder(func(m builder.Message) { msgs = append(msgs, m) })},
+				Manifest:  builder.Manifest{Name: "dedup", NReqs: len(adds)},
+				Dedup:     true,
+			})
+			require.NoError(t, err, "making builder")
+
+			var got corpus.Corpus
+			eg, ectx := errgroup.WithContext(context.Background())
+			eg.Go(func() error {
+				var rerr error
+				got, rerr = b.Run(ectx)
+				return rerr
+			})
+			eg.Go(func() error {
+				for i := range adds {
+					if err := builder.AddRequest(&adds[i]).SendTo(ectx, b.SendCh); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			require.NoError(t, eg.Wait(), "running builder")
+
+			assert.ElementsMatch(t, []string{"seed", "bar", "nofp1", "nofp2"}, got.Names(), "duplicates should be dropped")
+			assert.Equal(t, "bar.litmus", got["bar"].Source.Path, "kept subject should be intact")
+
+			dups := map[string]string{}
+			steps := 0
+			for _, m := range msgs {
+				if m.Kind == observing.BatchStep {
+					steps++
+				}
+				if m.DuplicateOf != "" {
+					dups[m.Request.Name] = m.DuplicateOf
+				}
+			}
+			assert.Equal(t, map[string]string{"foo": "bar", "baz": "seed"}, dups, "duplicates should be reported")
+			assert.Equal(t, len(adds), steps, "should be one step per request")
+		})
+	}
+}
+
+// recorder is a builder observer that calls a function on each message.
+type recorder func(builder.Message)
+
+// OnBuild calls r on m.
+func (r recorder) OnBuild(m builder.Message) {
+	r(m)
+}
+
 func onBuild(m *mocks.Observer, k observing.BatchKind, f func(int, string, *builder.Request) bool) *mock.Call {
 	return m.On("OnBuild", mock.MatchedBy(func(m builder.Message) bool {
 		return m.Kind == k && f(m.Num, m.Name, m.Request)
@@ -19,4 +19,11 @@ type Config struct {
 
 	// Obs is the list of observers to notify as the builder performs various tasks.
 	Observers []Observer
+
+	// Dedup, if true, makes the builder keep only one subject for each litmus test fingerprint.  Subjects from Init
+	// always stay; otherwise, the subject with the lexicographically smallest name stays, whatever order the adds
+	// arrive in.  The builder reports each drop to its observers as a duplicate message.
+	//
+	// As a later add can displace an earlier one, deduplicating builders should only receive add requests.
+	Dedup bool
 }
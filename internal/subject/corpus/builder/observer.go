@@ -26,6 +26,10 @@ type Message struct {
 
 	// Request carries a builder request, if we're on a build-step.
 	Request *Request `json:"request,omitempty"`
+
+	// DuplicateOf carries, if we're on a build-step that the builder dropped as a duplicate, the name of the subject
+	// it duplicates.
+	DuplicateOf string `json:"duplicate_of,omitempty"`
 }
 
 // OnBuild sends an OnBuild message to each observer in obs.
@@ -45,6 +49,14 @@ func StepMessage(i int, r Request) Message {
 	return Message{Batch: observing.NewBatchStep(i), Request: &r}
 }
 
+// DuplicateMessage creates a build-step message for step i and request r, where the builder dropped r as a duplicate
+// of the subject named of.
+func DuplicateMessage(i int, r Request, of string) Message {
+	m := StepMessage(i, r)
+	m.DuplicateOf = of
+	return m
+}
+
 // EndMessage creates a build-end message.
 func EndMessage() Message {
 	return Message{Batch: observing.NewBatchEnd()}
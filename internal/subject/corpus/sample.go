@@ -6,6 +6,7 @@
 package corpus
 
 import (
+	"math"
 	"math/rand"
 	"sort"
 )
@@ -45,3 +46,47 @@ func (c Corpus) sampleIndices(r *rand.Rand, n int) []int {
 	sort.Ints(indices)
 	return indices
 }
+
+// WeightedSample is like Sample, but selects subjects with probability in proportion to the weight that weight gives
+// their names.
+// Subjects with non-positive weights are only selected once every positively weighted subject has been.
+func (c Corpus) WeightedSample(rng *rand.Rand, want int, weight func(name string) float64) (Corpus, error) {
+	got := len(c)
+
+	if got == 0 {
+		return nil, ErrNone
+	}
+
+	if want <= 0 || got <= want {
+		return c, nil
+	}
+
+	return c.actuallyWeightedSample(rng, want, weight), nil
+}
+
+// actuallyWeightedSample uses the Efraimidis-Spirakis algorithm: each subject gets a random key u^(1/w), and we take
+// the want subjects with the largest keys.
+func (c Corpus) actuallyWeightedSample(r *rand.Rand, want int, weight func(name string) float64) Corpus {
+	type keyed struct {
+		name string
+		key  float64
+	}
+
+	names := c.Names()
+	keys := make([]keyed, len(names))
+	for i, n := range names {
+		// We work in log space to avoid underflow with small weights; -Inf sorts below everything.
+		k := math.Inf(-1)
+		if w := weight(n); 0 < w {
+			k = math.Log(1-r.Float64()) / w
+		}
+		keys[i] = keyed{name: n, key: k}
+	}
+	sort.SliceStable(keys, func(i, j int) bool { return keys[i].key > keys[j].key })
+
+	sample := make(Corpus, want)
+	for _, k := range keys[:want] {
+		sample[k.name] = c[k.name]
+	}
+	return sample
+}
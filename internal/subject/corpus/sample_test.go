@@ -114,3 +114,48 @@ func checkCorpusIsSample(t *testing.T, corpus, smp corpus.Corpus) {
 		}
 	}
 }
+
+// TestCorpus_WeightedSample_actuallySample tests that weighted sampling with flat weights behaves correctly.
+func TestCorpus_WeightedSample_actuallySample(t *testing.T) {
+	t.Parallel()
+	var i int64
+	for name, c := range sampleCorpora {
+		c := c
+		j := i
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			smp, err := c.corpus.WeightedSample(rand.New(rand.NewSource(j)), c.want, func(string) float64 { return 1 })
+			if assert.NoError(t, err, "error when sampling corpus") {
+				assert.Len(t, smp, c.want, "sample is the wrong size")
+				checkCorpusIsSample(t, c.corpus, smp)
+			}
+		})
+		i++
+	}
+}
+
+// TestCorpus_WeightedSample_weights tests that weighted sampling prefers heavier subjects.
+func TestCorpus_WeightedSample_weights(t *testing.T) {
+	t.Parallel()
+
+	c := corpus.New("you're", "going", "to", "have", "a", "bad", "time")
+	weights := map[string]float64{"bad": 1e9, "time": 1e9, "to": -1, "a": 0}
+	weight := func(n string) float64 {
+		if w, ok := weights[n]; ok {
+			return w
+		}
+		return 1
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		smp, err := c.WeightedSample(rng, 5, weight)
+		if !assert.NoError(t, err, "error when sampling corpus") {
+			return
+		}
+		assert.Contains(t, smp, "bad", "heavy subject should always be sampled")
+		assert.Contains(t, smp, "time", "heavy subject should always be sampled")
+		assert.NotContains(t, smp, "to", "unweighted subject shouldn't be sampled while others remain")
+		assert.NotContains(t, smp, "a", "unweighted subject shouldn't be sampled while others remain")
+	}
+}
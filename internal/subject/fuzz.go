@@ -6,6 +6,9 @@
 package subject
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
 	"time"
 
 	"github.com/c4-project/c4t/internal/model/litmus"
@@ -21,4 +24,22 @@ type Fuzz struct {
 
 	// Trace is the slashpath to this subject's fuzzer trace file.
 	Trace string `toml:"trace,omitempty" json:"trace,omitempty"`
+
+	// Params records the values of any adaptively tuned fuzzer parameters used to make this subject.
+	Params map[string]string `toml:"params,omitempty" json:"params,omitempty"`
+
+	// Hash is the hex SHA-256 hash of this subject's fuzzed Litmus file, if known.
+	// Replaying the cycle that made the subject should produce a file with the same hash.
+	Hash string `toml:"hash,omitempty" json:"hash,omitempty"`
+}
+
+// PopulateHash reads this fuzz output's Litmus file, and sets its hash.
+func (f *Fuzz) PopulateHash() error {
+	bs, err := os.ReadFile(f.Litmus.Filepath())
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(bs)
+	f.Hash = hex.EncodeToString(sum[:])
+	return nil
 }
@@ -21,6 +21,12 @@ const (
 	FileFuzzLitmus = "fuzz.litmus"
 	// FileFuzzTrace is the normalised name for fuzzer traces.
 	FileFuzzTrace = "fuzz.trace"
+	// FileReproManifest is the normalised name for reproduction manifests.
+	FileReproManifest = "repro.json"
+	// FileReproScript is the normalised name for reproduction scripts.
+	FileReproScript = "repro.sh"
+	// FileReproPlan is the normalised name for the single-subject plans inside reproduction bundles.
+	FileReproPlan = "plan.json"
 	// DirCompiles is the normalised directory for compile results.
 	DirCompiles = "compiles"
 	// DirRecipes is the normalised directory for recipe results.
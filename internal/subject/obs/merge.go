@@ -0,0 +1,94 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package obs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// verdictFlags is the set of flags that make up an observation's postcondition verdict.
+const verdictFlags = Sat | Unsat | Undef
+
+// Merge aggregates the observations os, each from a separate run of the same test, into one observation.
+//
+// Partial observations, such as those of runs that failed or timed out, contribute their states and their Partial flag
+// to the aggregate, but nothing else.  The other, complete, observations decide the aggregate's verdict: if they
+// disagree, the aggregate takes the first verdict that makes it interesting, so it is interesting if any complete run
+// was interesting.  Each state's occurrences are summed across runs, and its Frequency is the fraction of complete
+// runs in which it appeared.  States appear in the order in which they were first observed.
+//
+// Merge also reports whether the observation is flaky: that is, whether the postcondition verdict differed between
+// complete runs.
+func Merge(os ...Obs) (agg Obs, flaky bool) {
+	counts := make(map[string]int)
+	index := make(map[string]int)
+	var (
+		ncomplete    int
+		firstVerdict Flag
+	)
+
+	for _, o := range os {
+		complete := !o.Flags.IsPartial()
+		if complete {
+			if ncomplete == 0 {
+				firstVerdict = o.Flags & verdictFlags
+			}
+			flaky = flaky || (o.Flags&verdictFlags) != firstVerdict
+			agg.Flags = mergeVerdict(agg.Flags, o.Flags, ncomplete == 0)
+			ncomplete++
+		} else {
+			agg.Flags |= Partial
+		}
+		for _, s := range o.States {
+			k := s.Key()
+			if complete {
+				counts[k]++
+			}
+			j, ok := index[k]
+			if !ok {
+				index[k] = len(agg.States)
+				agg.States = append(agg.States, State{Tag: s.Tag, Values: s.Values})
+				j = index[k]
+			}
+			agg.States[j].Occurrences += s.Occurrences
+		}
+	}
+
+	if ncomplete == 0 {
+		// There's no verdict to go on, but keep any flags (such as Exist) that the partial runs did report.
+		for _, o := range os {
+			agg.Flags |= o.Flags &^ verdictFlags
+		}
+		return agg, false
+	}
+	for i := range agg.States {
+		agg.States[i].Frequency = float64(counts[agg.States[i].Key()]) / float64(ncomplete)
+	}
+	return agg, flaky
+}
+
+// mergeVerdict merges the flags f of a complete run into the aggregate flags agg.
+//
+// The aggregate takes f's verdict if f is the first complete run, or if f's verdict is interesting and the aggregate's
+// isn't yet; verdicts are never combined, so the aggregate never ends up, for instance, both Sat and Unsat.
+func mergeVerdict(agg, f Flag, first bool) Flag {
+	agg |= f &^ verdictFlags
+	if first || (f.IsInteresting() && !agg.IsInteresting()) {
+		agg = (agg &^ verdictFlags) | (f & verdictFlags)
+	}
+	return agg
+}
+
+// Key gets a string uniquely identifying this state's tag and valuation.
+func (s State) Key() string {
+	var sb strings.Builder
+	sb.WriteString(s.Tag.String())
+	for _, v := range s.Values.Vars() {
+		_, _ = fmt.Fprintf(&sb, ";%s=%s", v, s.Values[v])
+	}
+	return sb.String()
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package obs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c4-project/c4t/internal/subject/obs"
+)
+
+// TestMerge tests Merge against some cases.
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	s0 := obs.Valuation{"x": "0", "y": "0"}
+	s1 := obs.Valuation{"x": "1", "y": "0"}
+
+	cases := map[string]struct {
+		in        []obs.Obs
+		want      obs.Obs
+		wantFlaky bool
+	}{
+		"empty": {},
+		"one": {
+			in: []obs.Obs{
+				{Flags: obs.Sat, States: []obs.State{{Tag: obs.TagWitness, Occurrences: 5, Values: s0}}},
+			},
+			want: obs.Obs{
+				Flags:  obs.Sat,
+				States: []obs.State{{Tag: obs.TagWitness, Occurrences: 5, Frequency: 1, Values: s0}},
+			},
+		},
+		"stable": {
+			in: []obs.Obs{
+				{Flags: obs.Sat, States: []obs.State{{Tag: obs.TagWitness, Occurrences: 5, Values: s0}}},
+				{Flags: obs.Sat, States: []obs.State{
+					{Tag: obs.TagWitness, Occurrences: 3, Values: s0},
+					{Tag: obs.TagWitness, Occurrences: 1, Values: s1},
+				}},
+			},
+			want: obs.Obs{
+				Flags: obs.Sat,
+				States: []obs.State{
+					{Tag: obs.TagWitness, Occurrences: 8, Frequency: 1, Values: s0},
+					{Tag: obs.TagWitness, Occurrences: 1, Frequency: 0.5, Values: s1},
+				},
+			},
+		},
+		"flaky": {
+			in: []obs.Obs{
+				{Flags: obs.Sat, States: []obs.State{{Tag: obs.TagWitness, Occurrences: 10, Values: s0}}},
+				{Flags: obs.Unsat, States: []obs.State{
+					{Tag: obs.TagWitness, Occurrences: 9, Values: s0},
+					{Tag: obs.TagCounter, Occurrences: 1, Values: s1},
+				}},
+				{Flags: obs.Sat, States: []obs.State{{Tag: obs.TagWitness, Occurrences: 10, Values: s0}}},
+				{Flags: obs.Sat, States: []obs.State{{Tag: obs.TagWitness, Occurrences: 10, Values: s0}}},
+			},
+			want: obs.Obs{
+				Flags: obs.Unsat,
+				States: []obs.State{
+					{Tag: obs.TagWitness, Occurrences: 39, Frequency: 1, Values: s0},
+					{Tag: obs.TagCounter, Occurrences: 1, Frequency: 0.25, Values: s1},
+				},
+			},
+			wantFlaky: true,
+		},
+		"flaky-exist": {
+			in: []obs.Obs{
+				{Flags: obs.Unsat | obs.Exist},
+				{Flags: obs.Sat | obs.Exist, States: []obs.State{{Tag: obs.TagWitness, Occurrences: 1, Values: s1}}},
+			},
+			want: obs.Obs{
+				Flags:  obs.Sat | obs.Exist,
+				States: []obs.State{{Tag: obs.TagWitness, Occurrences: 1, Frequency: 0.5, Values: s1}},
+			},
+			wantFlaky: true,
+		},
+		"partial": {
+			in: []obs.Obs{
+				{Flags: obs.Sat, States: []obs.State{{Tag: obs.TagWitness, Occurrences: 5, Values: s0}}},
+				{Flags: obs.Sat, States: []obs.State{{Tag: obs.TagWitness, Occurrences: 5, Values: s0}}},
+				{Flags: obs.Partial, States: []obs.State{{Tag: obs.TagWitness, Occurrences: 2, Values: s1}}},
+			},
+			want: obs.Obs{
+				Flags: obs.Sat | obs.Partial,
+				States: []obs.State{
+					{Tag: obs.TagWitness, Occurrences: 10, Frequency: 1, Values: s0},
+					{Tag: obs.TagWitness, Occurrences: 2, Values: s1},
+				},
+			},
+		},
+		"all-partial": {
+			in: []obs.Obs{
+				{Flags: obs.Partial | obs.Exist, States: []obs.State{{Occurrences: 2, Values: s1}}},
+			},
+			want: obs.Obs{
+				Flags:  obs.Partial | obs.Exist,
+				States: []obs.State{{Occurrences: 2, Values: s1}},
+			},
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, flaky := obs.Merge(c.in...)
+			assert.Equal(t, c.want, got, "merged observation")
+			assert.Equal(t, c.wantFlaky, flaky, "flakiness")
+		})
+	}
+}
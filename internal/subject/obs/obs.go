@@ -37,6 +37,21 @@ func (o Obs) CounterExamples() []State {
 	return o.WithTag(TagCounter)
 }
 
+// Unexpected gets the list of states in this observation that make it interesting.
+//
+// For a satisfied existential observation, these are the witnesses; for an unsatisfied universal one, they are the
+// counter-examples.  Other observations have no unexpected states.
+func (o Obs) Unexpected() []State {
+	switch {
+	case o.Flags&(Sat|Exist) == (Sat | Exist):
+		return o.Witnesses()
+	case o.Flags&(Unsat|Exist) == Unsat:
+		return o.CounterExamples()
+	default:
+		return nil
+	}
+}
+
 // WithTag gets the list of states with tag t in this observation.
 func (o Obs) WithTag(t Tag) []State {
 	xs := make([]State, 0, len(o.States))
@@ -56,6 +71,9 @@ type State struct {
 	// If this number is zero, there was no occurrence reporting for this state;
 	// states which were observed zero times will not appear in the observation at all.
 	Occurrences uint64 `json:"occurrences,omitempty"`
+	// Frequency is, for observations merged from several runs, the fraction of runs in which this state appeared.
+	// If this number is zero, the observation comes from a single run.
+	Frequency float64 `json:"frequency,omitempty"`
 	// Values is the valuation for this state.
 	Values Valuation `json:"values,omitempty"`
 }
@@ -62,6 +62,27 @@ func ExampleObs_Status() {
 	// e-unsat: Ok
 }
 
+// ExampleObs_Unexpected is a testable example for Obs.Unexpected.
+func ExampleObs_Unexpected() {
+	states := []obs.State{
+		{Tag: obs.TagWitness, Values: obs.Valuation{"x": "1"}},
+		{Tag: obs.TagCounter, Values: obs.Valuation{"x": "0"}},
+	}
+	for _, f := range []obs.Flag{obs.Sat, obs.Unsat, obs.Sat | obs.Exist, obs.Unsat | obs.Exist} {
+		fmt.Printf("%v:", f.Strings())
+		for _, s := range (obs.Obs{Flags: f, States: states}).Unexpected() {
+			fmt.Print(" ", s.Key())
+		}
+		fmt.Println()
+	}
+
+	// Output:
+	// [sat]:
+	// [unsat]: counter;x=0
+	// [exist sat]: witness;x=1
+	// [exist unsat]:
+}
+
 // TestObs_jsonRoundTrip tests that Obs can go round a JSON round-trip.
 func TestObs_jsonRoundTrip(t *testing.T) {
 	t.Parallel()
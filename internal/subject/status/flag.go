@@ -21,9 +21,11 @@ const (
 	FlagRunFail
 	// FlagRunTimeout signifies a runtime timeout.
 	FlagRunTimeout
+	// FlagRunLimit signifies a run exceeding a resource limit.
+	FlagRunLimit
 
 	// FlagFail is the union of all failure flags.
-	FlagFail = FlagCompileFail | FlagRunFail
+	FlagFail = FlagCompileFail | FlagRunFail | FlagRunLimit
 	// FlagTimeout is the union of all timeout flags.
 	FlagTimeout = FlagCompileTimeout | FlagRunTimeout
 	// FlagBad is the union of all 'bad' flags; it should match the calculation in Status.IsBad.
@@ -74,6 +76,7 @@ var statusFlags = [Last + 1]Flag{
 	CompileFail:    FlagCompileFail,
 	RunTimeout:     FlagRunTimeout,
 	RunFail:        FlagRunFail,
+	RunLimit:       FlagRunLimit,
 }
 
 // Flag gets the flag equivalent of this status.
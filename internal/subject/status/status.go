@@ -34,17 +34,24 @@ const (
 	RunFail
 	// RunTimeout indicates that a run timed out.
 	RunTimeout
+	// RunLimit indicates that a run was killed for exceeding its CPU time limit.
+	RunLimit
 
 	// FirstBad refers to the first status that represents an unwanted outcome.
 	FirstBad = Flagged
 	// Last is the last valid status.
-	Last = RunTimeout
+	Last = RunLimit
 )
 
 //go:generate stringer -type=Status
 
-// ErrBad occurs when FromString encounters an unknown status string.
-var ErrBad = errors.New("bad status")
+var (
+	// ErrBad occurs when FromString encounters an unknown status string.
+	ErrBad = errors.New("bad status")
+
+	// ErrRunLimit is an error that runners can wrap to signal that a run exceeded a resource limit.
+	ErrRunLimit = errors.New("run exceeded resource limit")
+)
 
 // FromCompileError tries to see if err represents a non-fatal issue such as a timeout or process error.
 // If so, it converts that error to a status and returns it alongside nil.
@@ -56,7 +63,12 @@ func FromCompileError(err error) (Status, error) {
 // FromRunError tries to see if err represents a non-fatal issue such as a timeout or process error.
 // If so, it converts that error to a status and returns it alongside nil.
 // Otherwise, it propagates the error forwards.
+//
+// Errors wrapping ErrRunLimit map to RunLimit.
 func FromRunError(err error) (Status, error) {
+	if errors.Is(err, ErrRunLimit) {
+		return RunLimit, nil
+	}
 	return statusOfError(err, RunTimeout, RunFail)
 }
 
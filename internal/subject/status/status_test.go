@@ -96,6 +96,10 @@ func TestFromRunError(t *testing.T) {
 			in:   &exec.ExitError{},
 			want: status.RunFail,
 		},
+		"limit": {
+			in:   fmt.Errorf("%w: cpu time", status.ErrRunLimit),
+			want: status.RunLimit,
+		},
 		"other": {
 			in:  e,
 			out: e,
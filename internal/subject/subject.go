@@ -117,6 +117,15 @@ func (s *Subject) AddRun(cid id.ID, r compilation.RunResult) error {
 	})
 }
 
+// SetIterations sets the iteration parameters for cid to it in this subject, replacing any existing parameters.
+func (s *Subject) SetIterations(cid id.ID, it compilation.Iterations) {
+	// The callback never fails.
+	_ = s.mapCompilation(cid, func(cc *compilation.Compilation) error {
+		cc.Iterations = &it
+		return nil
+	})
+}
+
 func (s *Subject) mapCompilation(cid id.ID, f func(cc *compilation.Compilation) error) error {
 	s.ensureCompilationMap()
 	// Deliberately taking the zero value if the compilation hasn't been seen yet.
@@ -223,6 +223,25 @@ func TestSubject_AddRun(t *testing.T) {
 	testhelp.ExpectErrorIs(t, err, subject.ErrDuplicateRun, "adding compile twice")
 }
 
+// TestSubject_SetIterations checks that SetIterations replaces iterations without disturbing other results.
+func TestSubject_SetIterations(t *testing.T) {
+	t.Parallel()
+
+	var s subject.Subject
+	mcomp := id.FromString("gcc")
+	c := compilation.RunResult{Result: compilation.Result{Status: status.Ok}}
+	assert.NoError(t, s.AddRun(mcomp, c), "err when adding run to empty subject")
+
+	s.SetIterations(mcomp, compilation.Iterations{Size: 100, Count: 2})
+	s.SetIterations(mcomp, compilation.Iterations{Size: 1000, Count: 5})
+
+	cc, err := s.Compilation(mcomp)
+	if assert.NoError(t, err, "err when getting compilation") {
+		assert.Equal(t, &compilation.Iterations{Size: 1000, Count: 5}, cc.Iterations, "iterations")
+		assert.Equal(t, &c, cc.Run, "run should be untouched")
+	}
+}
+
 // TestSubject_BestLitmus tests a few cases of BestLitmus.
 // It should be more comprehensive than the examples.
 func TestSubject_BestLitmus(t *testing.T) {
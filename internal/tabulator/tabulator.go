@@ -78,6 +78,8 @@ func stringify(value interface{}) string {
 		return strconv.Itoa(v)
 	case int64:
 		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
 	case string:
 		return v
 	case fmt.Stringer:
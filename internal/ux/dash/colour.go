@@ -31,6 +31,7 @@ const (
 	colourCompileTimeout = cell.ColorBlue
 	colourRunFail        = cell.ColorMagenta
 	colourRunTimeout     = cell.ColorCyan
+	colourRunLimit       = cell.ColorRed
 )
 
 // statusColours maps each status flag to its colour.
@@ -44,6 +45,7 @@ var statusColours = [status.Last + 1]cell.Color{
 	colourCompileTimeout,
 	colourRunFail,
 	colourRunTimeout,
+	colourRunLimit,
 }
 
 // optColour divines a colour to signify the optimisation level described by o.
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/c4-project/c4t/internal/director"
+	"github.com/c4-project/c4t/internal/plan/analysis"
 
 	"github.com/c4-project/c4t/internal/subject/corpus"
 
@@ -43,7 +44,70 @@ func (r *ResultLog) Log(sc director.CycleAnalysis) error {
 	if err := r.LogHeader(sc); err != nil {
 		return err
 	}
-	return r.logBuckets(sc)
+	if err := r.logBuckets(sc); err != nil {
+		return err
+	}
+	if err := r.logClusters(sc.Clusters); err != nil {
+		return err
+	}
+	if err := r.logSuppressions(sc.Suppressions); err != nil {
+		return err
+	}
+	if err := r.logNewWarnings(sc.NewWarnings); err != nil {
+		return err
+	}
+	return r.logRegressions(sc.Regressions)
+}
+
+func (r *ResultLog) logRegressions(rs []analysis.Regression) error {
+	for _, g := range rs {
+		if err := r.log.Write(fmt.Sprintf("  [timing regression %s]\n", g), text.WriteCellOpts(cell.FgColor(cell.ColorMagenta))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ResultLog) logNewWarnings(ks []analysis.WarningKey) error {
+	for _, k := range ks {
+		if err := r.log.Write(fmt.Sprintf("  [new warning %s]\n", k), text.WriteCellOpts(cell.FgColor(cell.ColorYellow))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ResultLog) logSuppressions(rs []analysis.SuppressionReport) error {
+	for _, s := range rs {
+		switch {
+		case len(s.Hits) != 0:
+			if err := r.log.Write(fmt.Sprintf("  [suppressed %d: %s]\n", len(s.Hits), s.Bug)); err != nil {
+				return err
+			}
+		case s.Stale:
+			txt := fmt.Sprintf("  [stale suppression %s: %s]\n", s.Fingerprint, s.Bug)
+			if err := r.log.Write(txt, text.WriteCellOpts(cell.FgColor(cell.ColorYellow))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *ResultLog) logClusters(cs analysis.ClusterSet) error {
+	if len(cs) == 0 {
+		return nil
+	}
+	header := fmt.Sprintf("  [%d distinct failure clusters]\n", len(cs))
+	if err := r.log.Write(header, text.WriteCellOpts(cell.FgColor(cell.ColorMagenta))); err != nil {
+		return err
+	}
+	for _, c := range cs.Sorted() {
+		if err := r.log.Write(fmt.Sprintf("  - %s (x%d): %s\n", c.Representative, len(c.Members), c.Signature)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (r *ResultLog) logBuckets(s director.CycleAnalysis) error {
@@ -20,6 +20,8 @@ import (
 	"github.com/c4-project/c4t/internal/helper/errhelp"
 	"github.com/c4-project/c4t/internal/ux/dash"
 	"golang.org/x/sync/errgroup"
+
+	"github.com/c4-project/c4t/internal/stage/perturber"
 )
 
 // Obs is the standard top-level director observer.
@@ -88,6 +90,14 @@ func (o *Obs) setupForwarder() error {
 	return err
 }
 
+// Yields gets the source of historical subject yields tracked by this observer's statistics, if any.
+func (o *Obs) Yields() perturber.YieldSource {
+	if o.statPersister == nil {
+		return nil
+	}
+	return o.statPersister
+}
+
 // loggerFromConfig constructs a logger according to the configuration in cfg.
 func loggerFromConfig(cfg *config.Config) (*Logger, error) {
 	logw, err := createResultLogFile(cfg)
@@ -118,7 +128,24 @@ func statPersisterFromConfig(c *config.Config) (*stat.Persister, error) {
 	if err != nil {
 		return nil, fmt.Errorf("opening stat persister file %q: %w", path, err)
 	}
-	return stat.NewPersister(f)
+	hpath, err := c.Paths.HistoryFile()
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("expanding stat history file path: %w", err)
+	}
+	h, err := stat.OpenHistoryFile(hpath)
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("opening stat history file %q: %w", hpath, err)
+	}
+	sp, err := stat.NewPersister(f, stat.AppendHistoryTo(h))
+	if err != nil {
+		// The persister only takes ownership of the files once constructed.
+		_ = h.Close()
+		_ = f.Close()
+		return nil, err
+	}
+	return sp, nil
 }
 
 func (o *Obs) Observers() []director.Observer {
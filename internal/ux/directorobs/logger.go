@@ -155,6 +155,12 @@ func (j *Logger) OnCycleSave(c director.Cycle, s saver.ArchiveMessage) {
 		j.l.Printf("saving (cycle %s) %s to %s\n", c, s.SubjectName, s.File)
 	case saver.ArchiveFileMissing:
 		j.l.Printf("when saving (cycle %s) %s: missing file %s\n", c, s.SubjectName, s.File)
+	case saver.ArchiveSkipped:
+		j.l.Printf("not saving (cycle %s) %s: duplicates known failure clusters\n", c, s.SubjectName)
+	case saver.ArchiveSent:
+		j.l.Printf("sent saved run (cycle %s) to %s\n", c, s.File)
+	case saver.ArchivePruned:
+		j.l.Printf("pruned saved run (cycle %s) %s\n", c, s.File)
 	}
 }
 
@@ -78,6 +78,7 @@ func ExampleLogger_OnCycleSave() {
 		saver.OnArchiveFileAdded("subj", "a.out", 0, i)
 		saver.OnArchiveFileMissing("subj", "compile.log", 1, i)
 		saver.OnArchiveFinish("subj", i)
+		saver.OnArchiveSkipped("dupe", i)
 		// Important, else the logger will keep waiting for the instance to provide observations.
 		i.OnInstance(director.InstanceClosedMessage())
 	}()
@@ -86,6 +87,7 @@ func ExampleLogger_OnCycleSave() {
 	// Output:
 	// saving (cycle [0:  #0 (Jan  1 00:00:00)]) subj to subj.tar.gz
 	// when saving (cycle [0:  #0 (Jan  1 00:00:00)]) subj: missing file compile.log
+	// not saving (cycle [0:  #0 (Jan  1 00:00:00)]) dupe: duplicates known failure clusters
 	// [instance 0 has closed]
 }
 
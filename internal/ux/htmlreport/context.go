@@ -0,0 +1,244 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package htmlreport
+
+import (
+	"os"
+	"sort"
+	"time"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/mutation"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/stage/analyser/saver"
+	"github.com/c4-project/c4t/internal/stat"
+	"github.com/c4-project/c4t/internal/subject/status"
+)
+
+// analysisContext is the root structure visible in the analysis report template.
+type analysisContext struct {
+	Title    string
+	Analysis *analysis.Analysis
+	saved    *saver.Pathset
+	suffix   string
+}
+
+func (p *Printer) newAnalysisContext(a *analysis.Analysis) analysisContext {
+	return analysisContext{Title: p.title, Analysis: a, saved: p.saved, suffix: p.savedSuffix}
+}
+
+// Statuses gets every status in order, for use as table columns.
+func (analysisContext) Statuses() []status.Status {
+	return allStatuses()
+}
+
+// Tarball gets the path to which the subject sname, with bad status st, was saved.
+//
+// It returns the empty string if we aren't linking to saved subjects, or if there is no local archive for the subject
+// (for instance, because the saver sent it to a remote target or skipped it as a known failure).
+func (c analysisContext) Tarball(st status.Status, sname string) string {
+	if c.saved == nil || !st.IsBad() {
+		return ""
+	}
+	rp, err := c.saved.SubjectRun(st, c.Analysis.Plan.Metadata.Creation)
+	if err != nil {
+		return ""
+	}
+	path := rp.SubjectArchiveFile(sname, c.suffix)
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// MaxTime gets the longest compile or run time across all compilers, for scaling timing bars.
+func (c analysisContext) MaxTime() time.Duration {
+	var max time.Duration
+	for _, cm := range c.Analysis.Compilers {
+		for _, ts := range []*analysis.TimeSet{cm.Time, cm.RunTime} {
+			if ts != nil && max < ts.Max {
+				max = ts.Max
+			}
+		}
+	}
+	return max
+}
+
+// TimeBar prepares the time set ts for display as a bar scaled against MaxTime.
+func (c analysisContext) TimeBar(ts *analysis.TimeSet) timeBar {
+	if ts == nil || ts.Count == 0 {
+		return timeBar{}
+	}
+	max := c.MaxTime()
+	return timeBar{
+		Set:     ts,
+		MinPct:  percent(ts.Min, max),
+		MeanPct: percent(ts.Mean(), max),
+		MaxPct:  percent(ts.Max, max),
+	}
+}
+
+// timeBar is a time set, along with the percentages needed to draw it as a bar.
+type timeBar struct {
+	// Set is the time set, or nil if there are no timings.
+	Set *analysis.TimeSet
+	// MinPct, MeanPct, and MaxPct are the minimum, mean, and maximum as percentages of the longest time in the report.
+	MinPct, MeanPct, MaxPct int
+}
+
+// RangePct gets the width of the bar between the minimum and maximum.
+func (t timeBar) RangePct() int {
+	return t.MaxPct - t.MinPct
+}
+
+// percent expresses d as a whole-number percentage of max, for use in CSS widths.
+func percent(d, max time.Duration) int {
+	if max <= 0 {
+		return 0
+	}
+	return int(100 * d / max)
+}
+
+// KillMatrix tabulates the analysis's mutation testing results by mutant and compiler.
+func (c analysisContext) KillMatrix() killMatrix {
+	cols := make(map[string]int)
+	for _, ma := range c.Analysis.Mutation {
+		for _, s := range ma.Selections {
+			cols[s.HitBy.CompilerID.String()] = 0
+		}
+	}
+	var m killMatrix
+	for col := range cols {
+		m.Columns = append(m.Columns, col)
+	}
+	sort.Strings(m.Columns)
+	for i, col := range m.Columns {
+		cols[col] = i
+	}
+
+	for _, mut := range sortedMutants(c.Analysis.Mutation) {
+		row := killRow{Mutant: mut.Mutant, Cells: make([]killCell, len(m.Columns))}
+		for _, s := range mut.Selections {
+			cell := &row.Cells[cols[s.HitBy.CompilerID.String()]]
+			cell.Selections++
+			cell.Hits += s.NumHits
+			if s.Killed() {
+				cell.Kills++
+			}
+		}
+		m.Rows = append(m.Rows, row)
+	}
+	return m
+}
+
+func sortedMutants(a mutation.Analysis) []mutation.MutantAnalysis {
+	mas := make([]mutation.MutantAnalysis, 0, len(a))
+	for i, ma := range a {
+		ma.Mutant.SetIndexIfZero(i)
+		mas = append(mas, ma)
+	}
+	sort.Slice(mas, func(i, j int) bool { return mas[i].Mutant.Index < mas[j].Mutant.Index })
+	return mas
+}
+
+// statsContext is the root structure visible in the statistics report template.
+type statsContext struct {
+	Title     string
+	Stats     *stat.Set
+	UseTotals bool
+}
+
+func (p *Printer) newStatsContext(s *stat.Set) statsContext {
+	return statsContext{Title: p.title, Stats: s, UseTotals: p.useTotals}
+}
+
+// Statuses gets every status in order, for use as table columns.
+func (statsContext) Statuses() []status.Status {
+	return allStatuses()
+}
+
+// Span gets from m the span required by the context.
+func (c statsContext) Span(m stat.Machine) stat.MachineSpan {
+	if c.UseTotals {
+		return m.Total
+	}
+	return m.Session
+}
+
+// KillMatrix tabulates the statistics set's mutation testing results by mutant and machine.
+func (c statsContext) KillMatrix() killMatrix {
+	var (
+		m    killMatrix
+		mids []id.ID
+	)
+	muts := make(map[mutation.Index]mutation.Mutant)
+	for mid, mach := range c.Stats.Machines {
+		mids = append(mids, mid)
+		for _, mut := range c.Span(mach).Mutation.MutantsWhere(stat.FilterAllMutants) {
+			muts[mut.Index] = mut
+		}
+	}
+	sort.Slice(mids, func(i, j int) bool { return mids[i].Less(mids[j]) })
+	for _, mid := range mids {
+		m.Columns = append(m.Columns, mid.String())
+	}
+
+	idxs := make([]mutation.Index, 0, len(muts))
+	for i := range muts {
+		idxs = append(idxs, i)
+	}
+	sort.Slice(idxs, func(i, j int) bool { return idxs[i] < idxs[j] })
+	for _, i := range idxs {
+		row := killRow{Mutant: muts[i], Cells: make([]killCell, len(mids))}
+		for j, mid := range mids {
+			ms := c.Span(c.Stats.Machines[mid]).Mutation.ByIndex[i]
+			row.Cells[j] = killCell{Selections: ms.Selections.Count, Hits: ms.Hits.Count, Kills: ms.Kills.Count}
+		}
+		m.Rows = append(m.Rows, row)
+	}
+	return m
+}
+
+// killMatrix is a table of mutation testing results, with one row per mutant.
+type killMatrix struct {
+	// Columns names the columns of the matrix (compilers or machines).
+	Columns []string
+	// Rows contains one row per mutant.
+	Rows []killRow
+}
+
+// killRow is a row in a kill matrix.
+type killRow struct {
+	Mutant mutation.Mutant
+	Cells  []killCell
+}
+
+// killCell is a cell in a kill matrix.
+type killCell struct {
+	Selections, Hits, Kills uint64
+}
+
+// Class gets the CSS class for this cell.
+func (k killCell) Class() string {
+	switch {
+	case 0 < k.Kills:
+		return "killed"
+	case 0 < k.Hits:
+		return "hit"
+	case 0 < k.Selections:
+		return "selected"
+	default:
+		return "none"
+	}
+}
+
+func allStatuses() []status.Status {
+	ss := make([]status.Status, 0, status.Last+1)
+	for s := status.Ok; s <= status.Last; s++ {
+		ss = append(ss, s)
+	}
+	return ss
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package htmlreport produces self-contained HTML reports of analyses and statistics sets, suitable for attaching
+// campaign results to reviews.
+package htmlreport
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+
+	"github.com/c4-project/c4t/internal/director"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/stage/analyser/saver"
+	"github.com/c4-project/c4t/internal/stat"
+)
+
+// Printer writes HTML reports to a writer.
+type Printer struct {
+	w     io.Writer
+	tmpl  *template.Template
+	title string
+	saved *saver.Pathset
+	// savedSuffix is the suffix of the archives in saved.
+	savedSuffix string
+	// useTotals makes statistics reports use multi-session totals rather than per-session totals.
+	useTotals bool
+}
+
+// NewPrinter constructs an HTML report printer using options o.
+func NewPrinter(o ...Option) (*Printer, error) {
+	t, err := getTemplate()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Printer{w: os.Stdout, tmpl: t, title: "c4t report"}
+	Options(o...)(p)
+
+	return p, nil
+}
+
+// Write writes a report of the analysis a to this printer.
+func (p *Printer) Write(a analysis.Analysis) error {
+	return p.tmpl.ExecuteTemplate(p.w, "analysis.tmpl", p.newAnalysisContext(&a))
+}
+
+// WriteSourced writes a report of the sourced analysis a to this printer.
+func (p *Printer) WriteSourced(a director.CycleAnalysis) error {
+	ctx := p.newAnalysisContext(&a.Analysis)
+	ctx.Title = fmt.Sprintf("%s: %s", p.title, &a.Cycle)
+	return p.tmpl.ExecuteTemplate(p.w, "analysis.tmpl", ctx)
+}
+
+// OnAnalysis writes a report of the analysis a to this printer; if an error occurs, it tries to rescue.
+func (p *Printer) OnAnalysis(a analysis.Analysis) {
+	if err := p.Write(a); err != nil {
+		_, _ = fmt.Fprintf(p.w, "<p>ERROR OUTPUTTING ANALYSIS: %s</p>\n", template.HTMLEscapeString(err.Error()))
+	}
+}
+
+// WriteStats writes a report of the statistics set s to this printer.
+func (p *Printer) WriteStats(s stat.Set) error {
+	return p.tmpl.ExecuteTemplate(p.w, "stats.tmpl", p.newStatsContext(&s))
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package htmlreport_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/stage/analyser/saver"
+	"github.com/c4-project/c4t/internal/stat"
+	"github.com/c4-project/c4t/internal/subject/status"
+	"github.com/c4-project/c4t/internal/ux/htmlreport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPrinter_Write tests writing an HTML report of the mock plan's analysis.
+func TestPrinter_Write(t *testing.T) {
+	t.Parallel()
+
+	a, err := analysis.Analyse(context.Background(), plan.Mock())
+	require.NoError(t, err, "analysing mock plan")
+
+	// Only baz has an archive, so only baz should get a link.
+	ps := saver.NewPathset(t.TempDir())
+	rp, err := ps.SubjectRun(status.Flagged, a.Plan.Metadata.Creation)
+	require.NoError(t, err, "getting run pathset")
+	baz := rp.SubjectArchiveFile("baz", saver.FormatTarZstd.Suffix())
+	require.NoError(t, os.MkdirAll(filepath.Dir(baz), 0755), "making run directory")
+	require.NoError(t, os.WriteFile(baz, nil, 0644), "faking archive")
+
+	var w bytes.Buffer
+	p, err := htmlreport.NewPrinter(
+		htmlreport.WriteTo(&w),
+		htmlreport.Title("mock report"),
+		htmlreport.LinkSaved(ps, saver.FormatTarZstd),
+	)
+	require.NoError(t, err, "constructing printer")
+	require.NoError(t, p.Write(*a), "writing report")
+
+	got := w.String()
+	assert.Contains(t, got, "<title>mock report</title>")
+	assert.Contains(t, got, "4 distinct failure cluster(s).")
+	assert.Contains(t, got, `<a href="`+baz+`">saved</a>`)
+	assert.Equal(t, 1, strings.Count(got, ">saved</a>"), "unsaved subjects shouldn't be linked")
+	assert.Contains(t, got, "<details>")
+	assert.NotContains(t, got, "<script", "report should be self-contained")
+	assert.NotContains(t, got, `<link`, "report should be self-contained")
+}
+
+// TestPrinter_WriteStats tests writing an HTML report of a statistics set.
+func TestPrinter_WriteStats(t *testing.T) {
+	t.Parallel()
+
+	// We share the statistics fixture of the pretty-printer.
+	var s stat.Set
+	require.NoError(t, s.LoadFile(filepath.Join("..", "..", "stat", "pretty", "testdata", "stats.json")), "loading stats")
+
+	var w bytes.Buffer
+	p, err := htmlreport.NewPrinter(htmlreport.WriteTo(&w), htmlreport.UseTotals(false))
+	require.NoError(t, err, "constructing printer")
+	require.NoError(t, p.WriteStats(s), "writing report")
+
+	got := w.String()
+	assert.Contains(t, got, "<td>bam</td>")
+	assert.Contains(t, got, "showing: this session")
+	assert.Contains(t, got, `class="num killed"`)
+}
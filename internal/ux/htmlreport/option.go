@@ -0,0 +1,56 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package htmlreport
+
+import (
+	"io"
+
+	"github.com/c4-project/c4t/internal/helper/iohelp"
+	"github.com/c4-project/c4t/internal/stage/analyser/saver"
+)
+
+// Option is the type of options for an HTML report printer.
+type Option func(*Printer)
+
+// Options combines the options os into a single option.
+func Options(os ...Option) Option {
+	return func(p *Printer) {
+		for _, o := range os {
+			o(p)
+		}
+	}
+}
+
+// WriteTo sets the printer's output to w.
+func WriteTo(w io.Writer) Option {
+	return func(p *Printer) {
+		p.w = iohelp.EnsureWriter(w)
+	}
+}
+
+// Title sets the title of the printer's reports to title.
+func Title(title string) Option {
+	return func(p *Printer) {
+		p.title = title
+	}
+}
+
+// LinkSaved makes the printer link each bad subject to its archive, in format f, in ps.
+// The printer links only to archives that exist locally when it writes the report.
+// If ps is nil, the printer doesn't link to saved subjects.
+func LinkSaved(ps *saver.Pathset, f saver.Format) Option {
+	return func(p *Printer) {
+		p.saved = ps
+		p.savedSuffix = f.Suffix()
+	}
+}
+
+// UseTotals determines whether statistics reports show totals rather than session statistics.
+func UseTotals(on bool) Option {
+	return func(p *Printer) {
+		p.useTotals = on
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package htmlreport
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"time"
+)
+
+//go:embed template/*.tmpl
+var templates embed.FS
+
+func getTemplate() (*template.Template, error) {
+	dir, err := fs.Sub(templates, "template")
+	if err != nil {
+		return nil, err
+	}
+	return template.New("").Funcs(template.FuncMap{
+		"seconds": func(d time.Duration) string { return fmt.Sprintf("%.3f", d.Seconds()) },
+		"time":    func(t time.Time) string { return t.Format(time.StampMilli) },
+	}).ParseFS(dir, "*.tmpl")
+}
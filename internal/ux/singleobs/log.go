@@ -39,9 +39,14 @@ type Logger log.Logger
 
 // OnBuild logs build messages.
 func (l *Logger) OnBuild(b builder.Message) {
-	if b.Kind == observing.BatchStep {
-		l.onBuildRequest(b.Request)
+	if b.Kind != observing.BatchStep {
+		return
 	}
+	if b.DuplicateOf != "" {
+		(*log.Logger)(l).Printf("subject %q duplicates %q; dropping", b.Request.Name, b.DuplicateOf)
+		return
+	}
+	l.onBuildRequest(b.Request)
 }
 
 // OnBuildRequest logs failed compile and run results.
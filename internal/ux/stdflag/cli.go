@@ -73,6 +73,21 @@ func ConfigFromCli(ctx *c.Context) (*config.Config, error) {
 	return config.Load(ctx.Path(FlagConfigFile))
 }
 
+// CheckFuzzParams checks cfg's fuzzer parameters against those that the c4f behind a supports.
+// If c4f can't list its parameters, CheckFuzzParams warns on errw rather than failing.
+func CheckFuzzParams(ctx *c.Context, cfg *config.Config, a *c4f.Runner, errw io.Writer) error {
+	if !cfg.HasC4fParams() {
+		return nil
+	}
+	cat, err := a.ListParams(ctx.Context)
+	if err != nil {
+		// Failing to list the parameters is deliberately non-fatal: only failing to report it is an error.
+		_, werr := fmt.Fprintln(errw, "warning: couldn't list fuzzer parameters, so can't check them:", err)
+		return werr
+	}
+	return cfg.CheckFuzzParams(cat)
+}
+
 // OutDirFromCli gets the output directory set up by OutDirCliFlag.
 func OutDirFromCli(ctx *c.Context) string {
 	return ctx.Path(FlagOutDir)
@@ -22,6 +22,18 @@ const (
 	FlagCompilerWorkerCountLong = "num-compiler-workers"
 	// FlagRunWorkerCountLong is a long flag for arguments that set a runner worker count.
 	FlagRunWorkerCountLong = "num-run-workers"
+	// FlagRunCountLong is a long flag for arguments that set how many times the runner runs each binary.
+	FlagRunCountLong = "num-runs"
+	// FlagPinRunCPUsLong is a long flag for arguments that enable CPU pinning in the runner.
+	FlagPinRunCPUsLong = "pin-run-cpus"
+	// FlagRunMaxMemoryLong is a long flag for arguments that limit the address space of run binaries.
+	FlagRunMaxMemoryLong = "run-max-memory"
+	// FlagRunMaxCPUTimeLong is a long flag for arguments that limit the CPU time of run binaries.
+	FlagRunMaxCPUTimeLong = "run-max-cpu-time"
+	// FlagRunMaxOpenFilesLong is a long flag for arguments that limit the open files of run binaries.
+	FlagRunMaxOpenFilesLong = "run-max-open-files"
+	// FlagRunMaxProcessesLong is a long flag for arguments that limit the processes of run binaries.
+	FlagRunMaxProcessesLong = "run-max-processes"
 
 	// TODO(@MattWindsor91): rename xLong/x to x/xShort.
 	flagGlobalTimeout  = "global-timeout"
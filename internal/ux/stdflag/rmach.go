@@ -26,6 +26,30 @@ func MachArgs(dir string, qs quantity.MachNodeSet) []string {
 		"-" + FlagCompilerWorkerCountLong, strconv.Itoa(qs.Compiler.NWorkers),
 		"-" + FlagRunWorkerCountLong, strconv.Itoa(qs.Runner.NWorkers),
 	}
+	if qs.Runner.NRuns != 0 {
+		args = append(args, "-"+FlagRunCountLong, strconv.Itoa(qs.Runner.NRuns))
+	}
+	if qs.Runner.PinCPUs {
+		args = append(args, "-"+FlagPinRunCPUsLong)
+	}
+	return append(args, limitArgs(qs.Runner.Limits)...)
+}
+
+// limitArgs gets the arguments for each active limit in lim.
+func limitArgs(lim quantity.LimitSet) []string {
+	var args []string
+	if 0 < lim.AddressSpaceMiB {
+		args = append(args, "-"+FlagRunMaxMemoryLong, strconv.Itoa(lim.AddressSpaceMiB))
+	}
+	if lim.CPUTime.IsActive() {
+		args = append(args, "-"+FlagRunMaxCPUTimeLong, lim.CPUTime.String())
+	}
+	if 0 < lim.OpenFiles {
+		args = append(args, "-"+FlagRunMaxOpenFilesLong, strconv.Itoa(lim.OpenFiles))
+	}
+	if 0 < lim.Processes {
+		args = append(args, "-"+FlagRunMaxProcessesLong, strconv.Itoa(lim.Processes))
+	}
 	return args
 }
 
@@ -48,7 +72,7 @@ func MachCliFlags() []c.Flag {
 			Name:        FlagRunTimeoutLong,
 			Aliases:     []string{FlagRunTimeout},
 			Value:       0,
-			Usage:       "a `timeout` to apply to each run",
+			Usage:       "a `timeout` to apply to each run; with several runs per binary, each run gets the full timeout",
 			DefaultText: "from config",
 		},
 		&c.IntFlag{
@@ -65,6 +89,36 @@ func MachCliFlags() []c.Flag {
 			Usage:       "number of runner `workers` to run in parallel (not recommended except on manycore machines)",
 			DefaultText: "from config",
 		},
+		&c.IntFlag{
+			Name:        FlagRunCountLong,
+			Usage:       "number of `times` to run each binary, aggregating observations across runs (each run has its own timeout)",
+			DefaultText: "from config",
+		},
+		&c.BoolFlag{
+			Name:        FlagPinRunCPUsLong,
+			Usage:       "pin each running binary to its own CPUs, sized by its thread count",
+			DefaultText: "from config",
+		},
+		&c.IntFlag{
+			Name:        FlagRunMaxMemoryLong,
+			Usage:       "maximum address space, in `MiB`, of each running binary",
+			DefaultText: "from config",
+		},
+		&c.DurationFlag{
+			Name:        FlagRunMaxCPUTimeLong,
+			Usage:       "maximum CPU `time` of each running binary; binaries exceeding it get the RunLimit status",
+			DefaultText: "from config",
+		},
+		&c.IntFlag{
+			Name:        FlagRunMaxOpenFilesLong,
+			Usage:       "maximum `number` of files each running binary may have open",
+			DefaultText: "from config",
+		},
+		&c.IntFlag{
+			Name:        FlagRunMaxProcessesLong,
+			Usage:       "maximum `number` of processes the user may have while running binaries",
+			DefaultText: "from config",
+		},
 		OutDirCliFlag(defaultOutDir),
 	}
 }
@@ -81,6 +135,14 @@ func MachNodeQuantitySetFromCli(ctx *c.Context) quantity.MachNodeSet {
 		Runner: quantity.BatchSet{
 			Timeout:  quantity.Timeout(ctx.Duration(FlagRunTimeoutLong)),
 			NWorkers: ctx.Int(FlagRunWorkerCountLong),
+			NRuns:    ctx.Int(FlagRunCountLong),
+			PinCPUs:  ctx.Bool(FlagPinRunCPUsLong),
+			Limits: quantity.LimitSet{
+				AddressSpaceMiB: ctx.Int(FlagRunMaxMemoryLong),
+				CPUTime:         quantity.Timeout(ctx.Duration(FlagRunMaxCPUTimeLong)),
+				OpenFiles:       ctx.Int(FlagRunMaxOpenFilesLong),
+				Processes:       ctx.Int(FlagRunMaxProcessesLong),
+			},
 		},
 	}
 }
@@ -64,6 +64,29 @@ func TestMachConfigFromCli_roundTrip(t *testing.T) {
 				},
 			},
 		},
+		"pinned": {
+			dir: "bar",
+			qs: quantity.MachNodeSet{
+				Runner: quantity.BatchSet{
+					NWorkers: 4,
+					NRuns:    10,
+					PinCPUs:  true,
+				},
+			},
+		},
+		"limits": {
+			dir: "baz",
+			qs: quantity.MachNodeSet{
+				Runner: quantity.BatchSet{
+					Limits: quantity.LimitSet{
+						AddressSpaceMiB: 2048,
+						CPUTime:         quantity.Timeout(30 * time.Second),
+						OpenFiles:       64,
+						Processes:       512,
+					},
+				},
+			},
+		},
 	}
 
 	for name, in := range cases {